@@ -0,0 +1,107 @@
+// citylyf-batch runs many seeded citylyf simulations side by side and
+// summarises the spread of their outcomes, to see how changes to the
+// simulation's constants play out.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/janithl/citylyf/internal/batch"
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/gamefile"
+	"github.com/janithl/citylyf/internal/mayor"
+	"github.com/janithl/citylyf/internal/scenario"
+)
+
+func main() {
+	runs := flag.Int("runs", 20, "number of simulations")
+	years := flag.Int("years", 10, "number of years to simulate each city for")
+	seed := flag.Uint64("seed", 1, "seed of the first simulation, the others use the seeds after it")
+	workers := flag.Int("workers", 0, "number of simulations run at once (default the number of CPUs)")
+	scenarioPath := flag.String("scenario", "", "scenario file to start each simulation from")
+	mayorName := flag.String("mayor", "", "let a mayor play each city: planner")
+	replayPath := flag.String("replay", "", "journal of commands to give each city on the journal's dates")
+	outPath := flag.String("out", "", "file to write the percentiles of each metric for each year to, as CSV")
+	outcomesPath := flag.String("outcomes", "", "file to write the final outcome of each simulation to, as CSV")
+	cataloguePath := flag.String("catalogue", "", "JSON file of industries and jobs that override the default ones (default ~/.citylyf/catalogue.json if it exists)")
+	flag.Parse()
+
+	if *runs < 1 || *years < 1 {
+		log.Fatal("runs and years must be at least 1")
+	}
+
+	var catalogue *entities.Catalogue
+	if *cataloguePath == "" {
+		catalogue = gamefile.FindCatalogue(gamefile.GetCataloguePath())
+	} else {
+		var err error
+		if catalogue, err = gamefile.LoadCatalogue(*cataloguePath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	config := batch.Config{Runs: *runs, Years: *years, Seed: *seed, Workers: *workers, Catalogue: catalogue}
+	if *scenarioPath != "" {
+		start, err := scenario.Load(*scenarioPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		config.Scenario = start
+	}
+	switch *mayorName {
+	case "":
+	case "planner":
+		config.Mayor = func() mayor.Mayor { return &mayor.Planner{} }
+	default:
+		log.Fatalf("unknown mayor %q, expected planner", *mayorName)
+	}
+	if *replayPath != "" {
+		if config.Mayor != nil {
+			log.Fatal("a mayor can't play cities that are replaying a journal")
+		}
+		journal, err := gamefile.LoadJournal(*replayPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		config.Journal = journal
+	}
+	if config.Mayor == nil && config.Journal == nil && (config.Scenario == nil || len(config.Scenario.Roads) == 0) {
+		log.Println("warning: without a mayor, a journal or a scenario with roads, no one will move to the cities")
+	}
+
+	config.Progress = func(run int, seed uint64) {
+		log.Printf("simulation %d of %d (seed %d) finished", run+1, *runs, seed)
+	}
+	outcomes := batch.Run(config)
+	summaries := batch.Summarise(outcomes, *years)
+
+	if err := batch.WriteTable(os.Stdout, summaries); err != nil {
+		log.Fatal(err)
+	}
+	if *outPath != "" {
+		if err := writeFile(*outPath, func(w io.Writer) error { return batch.WriteCSV(w, summaries) }); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *outcomesPath != "" {
+		if err := writeFile(*outcomesPath, func(w io.Writer) error { return batch.WriteOutcomesCSV(w, outcomes) }); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// writeFile creates a file and writes to it with write
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
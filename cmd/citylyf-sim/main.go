@@ -0,0 +1,275 @@
+// citylyf-sim runs a citylyf simulation without a window, writing periodic
+// stats snapshots as JSON lines or CSV. It is meant for balancing runs on
+// machines without a display.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/janithl/citylyf/internal"
+	"github.com/janithl/citylyf/internal/api"
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/gamefile"
+	"github.com/janithl/citylyf/internal/mayor"
+	"github.com/janithl/citylyf/internal/scenario"
+	"github.com/janithl/citylyf/internal/scheduler"
+)
+
+func main() {
+	days := flag.Int("days", 365, "number of days to simulate")
+	until := flag.String("until", "", "simulate until this date (YYYY-MM-DD), overrides -days")
+	interval := flag.Int("interval", 30, "number of days between stats snapshots")
+	format := flag.String("format", "jsonl", "stats output format: jsonl or csv")
+	outPath := flag.String("out", "", "file to write stats to (default stdout)")
+	loadPath := flag.String("load", "", "save file to start the simulation from")
+	scenarioPath := flag.String("scenario", "", "scenario file to start a new simulation from")
+	savePath := flag.String("save", "", "file to write a save to when the simulation ends")
+	exportPath := flag.String("export", "", "file to write a JSON save to when the simulation ends")
+	statisticsPath := flag.String("statistics", "", "file to write the city's monthly statistics to when the simulation ends, as .csv or .json")
+	cityName := flag.String("name", "", "city name for new simulations")
+	seed := flag.Uint64("seed", 0, "random seed for new simulations (default random)")
+	replayPath := flag.String("replay", "", "journal of commands to replay on a new simulation with the journal's seed")
+	autosaveOn := flag.String("autosave", "", "autosave the city on this cadence: daily, weekly, monthly, quarterly or annually")
+	autosaveSlots := flag.Int("autosave-slots", 3, "number of autosaves kept per city")
+	mayorName := flag.String("mayor", "", "let a mayor play the city: planner")
+	apiAddr := flag.String("api", "", "serve the local HTTP API on this address while simulating, such as localhost:8080")
+	cataloguePath := flag.String("catalogue", "", "JSON file of industries and jobs that override the default ones (default ~/.citylyf/catalogue.json if it exists)")
+	debug := flag.Bool("debug", false, "check and repair references between entities every day")
+	quiet := flag.Bool("quiet", false, "discard simulation log output")
+	flag.Parse()
+
+	if *format != "jsonl" && *format != "csv" {
+		log.Fatalf("unknown format %q, expected jsonl or csv", *format)
+	}
+	if *interval < 1 {
+		log.Fatal("interval must be at least 1 day")
+	}
+
+	var catalogue *entities.Catalogue
+	if *cataloguePath == "" {
+		catalogue = gamefile.FindCatalogue(gamefile.GetCataloguePath())
+	} else {
+		var err error
+		if catalogue, err = gamefile.LoadCatalogue(*cataloguePath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	out := os.Stdout
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	// log simulation events to stderr, out of the way of the stats
+	var eventLog io.Writer = os.Stderr
+	if *quiet {
+		eventLog = io.Discard
+	}
+
+	var gamePath *string
+	if *loadPath != "" {
+		if !gamefile.CheckExists(*loadPath) {
+			log.Fatalf("save file %s does not exist", *loadPath)
+		}
+		gamePath = loadPath
+	}
+
+	var start *scenario.Scenario
+	if *scenarioPath != "" {
+		if gamePath != nil {
+			log.Fatal("a scenario can only be used to start a new simulation, not a loaded one")
+		}
+		var err error
+		if start, err = scenario.Load(*scenarioPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var journal *entities.Journal
+	if *replayPath != "" {
+		if gamePath != nil {
+			log.Fatal("a journal can only be replayed on a new simulation, not a loaded one")
+		}
+		var err error
+		if journal, err = gamefile.LoadJournal(*replayPath); err != nil {
+			log.Fatal(err)
+		}
+		if *seed == 0 {
+			*seed = journal.Seed
+		}
+	}
+
+	simRunner := &internal.SimRunner{Seed: *seed, EventLog: eventLog, Debug: *debug, Scenario: start, Catalogue: catalogue}
+	switch *mayorName {
+	case "":
+	case "planner":
+		if journal != nil {
+			log.Fatal("a mayor can't play a city that is replaying a journal")
+		}
+		simRunner.Mayor = &mayor.Planner{}
+	default:
+		log.Fatalf("unknown mayor %q, expected planner", *mayorName)
+	}
+	if *autosaveOn != "" {
+		cadence, err := scheduler.ParseCadence(*autosaveOn)
+		if err != nil {
+			log.Fatal(err)
+		}
+		simRunner.Autosave = gamefile.NewAutosave(gamefile.GetSavesDir(), *autosaveSlots)
+		simRunner.AutosaveOn = cadence
+	}
+	if err := simRunner.NewGame(gamePath); err != nil {
+		log.Fatal(err)
+	}
+	sim := simRunner.Sim()
+	if report := simRunner.LoadReport(); report != nil && !report.OK() {
+		log.Println(report)
+	}
+	if journal != nil {
+		if err := journal.CheckStart(sim); err != nil {
+			log.Fatal(err)
+		}
+		simRunner.Replay(journal)
+	}
+	if *cityName != "" {
+		sim.CityName = *cityName
+	}
+	log.Printf("simulation seed is %d", sim.Seed)
+	if *apiAddr != "" {
+		apiServer := api.NewServer()
+		apiServer.SetSimulation(sim)
+		go func() { log.Fatal(apiServer.ListenAndServe(*apiAddr)) }()
+	}
+
+	endDate := sim.Date.AddDate(0, 0, *days)
+	if *until != "" {
+		date, err := time.Parse("2006-01-02", *until)
+		if err != nil {
+			log.Fatal(err)
+		}
+		endDate = date
+	}
+
+	writer := newStatsWriter(out, *format)
+	writer.write(sim.GetStatsSnapshot())
+	for day := 1; sim.Date.Before(endDate); day++ {
+		simRunner.Step()
+
+		ended := sim.GoalsOutcome() != entities.GoalInProgress // the scenario has been won or lost
+		if day%*interval == 0 || !sim.Date.Before(endDate) || ended {
+			writer.write(sim.GetStatsSnapshot())
+		}
+		if ended {
+			break
+		}
+	}
+	if err := writer.flush(); err != nil {
+		log.Fatal(err)
+	}
+	if simRunner.Autosave != nil {
+		simRunner.Autosave.Wait()
+	}
+
+	if *savePath != "" {
+		if err := gamefile.SaveTo(sim, *savePath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *exportPath != "" {
+		if err := gamefile.ExportJSON(sim, *exportPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *statisticsPath != "" {
+		if err := gamefile.ExportStatistics(sim.Statistics, *statisticsPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if len(sim.Goals) > 0 {
+		reportGoals(sim)
+	}
+}
+
+// reportGoals logs the scenario's goals, exiting with an error status if the scenario was lost
+func reportGoals(sim *entities.Simulation) {
+	for _, goal := range sim.Goals {
+		log.Println(goal)
+	}
+	switch sim.GoalsOutcome() {
+	case entities.GoalPassed:
+		log.Printf("PASS: every goal was met by %s", sim.Date.Format("2006-01-02"))
+	case entities.GoalFailed:
+		log.Printf("FAIL: a goal was failed by %s", sim.Date.Format("2006-01-02"))
+		os.Exit(1)
+	default:
+		log.Printf("the scenario was still in progress on %s", sim.Date.Format("2006-01-02"))
+	}
+}
+
+// statsWriter writes stats snapshots in the selected format
+type statsWriter struct {
+	format string
+	json   *json.Encoder
+	csv    *csv.Writer
+}
+
+func newStatsWriter(w io.Writer, format string) *statsWriter {
+	sw := &statsWriter{format: format}
+	if format == "csv" {
+		sw.csv = csv.NewWriter(w)
+		sw.csv.Write([]string{"Date", "Reserves", "Population", "PopulationGrowth", "Houses", "FreeHouses",
+			"Unemployment", "Companies", "MarketValue", "MarketGrowth", "Inflation", "InterestRate"})
+	} else {
+		sw.json = json.NewEncoder(w)
+	}
+	return sw
+}
+
+func (sw *statsWriter) write(stats entities.Stats) {
+	if sw.csv == nil {
+		if err := sw.json.Encode(stats); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	sw.csv.Write([]string{
+		stats.Date.Format("2006-01-02"),
+		formatFloat(stats.Reserves),
+		strconv.Itoa(stats.Population),
+		formatFloat(stats.PopulationGrowth),
+		strconv.Itoa(stats.Houses),
+		strconv.Itoa(stats.FreeHouses),
+		formatFloat(stats.Unemployment),
+		strconv.Itoa(stats.Companies),
+		formatFloat(stats.MarketValue),
+		formatFloat(stats.MarketGrowth),
+		formatFloat(stats.Inflation),
+		formatFloat(stats.InterestRate),
+	})
+}
+
+func (sw *statsWriter) flush() error {
+	if sw.csv == nil {
+		return nil
+	}
+	sw.csv.Flush()
+	return sw.csv.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,376 @@
+// Package api serves a local HTTP/JSON API for inspecting and controlling the simulation,
+// for dashboards and experiment scripts
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/gamefile"
+)
+
+// Server handles API requests for the simulation being run
+type Server struct {
+	mutex       sync.Mutex // guards the simulation being served
+	sim         *entities.Simulation
+	unsubscribe func()
+
+	clientsMutex sync.Mutex // guards the streaming clients, and is locked while events are published
+	clients      map[chan message]bool
+
+	mux *http.ServeMux
+}
+
+// message is a server-sent event
+type message struct {
+	event string
+	data  []byte
+}
+
+const clientBuffer = 256 // messages held for a slow streaming client before it misses some
+
+var speeds = map[string]entities.SimulationSpeed{
+	"pause": entities.Pause,
+	"slow":  entities.Slow,
+	"mid":   entities.Mid,
+	"fast":  entities.Fast,
+	"ultra": entities.Ultra,
+}
+
+// NewServer returns an API server, which serves nothing until it is given a simulation
+func NewServer() *Server {
+	s := &Server{clients: make(map[chan message]bool), mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /stats", s.read(func(sim *entities.Simulation) any { return sim.GetStatsSnapshot() }))
+	s.mux.HandleFunc("GET /statistics", s.read(func(sim *entities.Simulation) any { return sim.Statistics }))
+	s.mux.HandleFunc("GET /households", s.read(func(sim *entities.Simulation) any {
+		households := []*entities.Household{}
+		for _, id := range sim.People.GetHouseholdIDs() {
+			households = append(households, sim.People.Households[id])
+		}
+		return households
+	}))
+	s.mux.HandleFunc("GET /people", s.read(func(sim *entities.Simulation) any {
+		people := []*entities.Person{}
+		for _, id := range sim.People.GetPersonIDs() {
+			people = append(people, sim.People.People[id])
+		}
+		return people
+	}))
+	s.mux.HandleFunc("GET /companies", s.read(func(sim *entities.Simulation) any {
+		companies := []*entities.Company{}
+		for _, id := range sim.Companies.GetIDs() {
+			companies = append(companies, sim.Companies[id])
+		}
+		return companies
+	}))
+	s.mux.HandleFunc("GET /tiles", s.read(func(sim *entities.Simulation) any { return sim.Geography.GetTiles() }))
+	s.mux.HandleFunc("GET /roads", s.read(func(sim *entities.Simulation) any { return sim.Geography.GetRoads() }))
+	s.mux.HandleFunc("GET /regions", s.read(func(sim *entities.Simulation) any { return sim.Geography.Regions }))
+	s.mux.HandleFunc("GET /market", s.read(func(sim *entities.Simulation) any { return sim.Market.History }))
+	s.mux.HandleFunc("GET /bank", s.read(func(sim *entities.Simulation) any { return sim.Bank }))
+	s.mux.HandleFunc("GET /taxes", s.read(func(sim *entities.Simulation) any { return taxRates(sim) }))
+
+	s.mux.HandleFunc("POST /roads", s.command(func(sim *entities.Simulation, body []byte) (entities.Command, error) {
+		command := entities.PlaceRoadCommand{}
+		if err := json.Unmarshal(body, &command); err != nil {
+			return nil, err
+		}
+		if command.RoadType != entities.Asphalt && command.RoadType != entities.Chipseal && command.RoadType != entities.Unsealed {
+			return nil, fmt.Errorf("unknown road type %q", command.RoadType)
+		}
+		return command, checkBounds(sim, command.Start, command.End)
+	}))
+	s.mux.HandleFunc("POST /zones", s.command(func(sim *entities.Simulation, body []byte) (entities.Command, error) {
+		command := entities.PlaceLandUseCommand{}
+		if err := json.Unmarshal(body, &command); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(entities.ZoningUses, command.Use) {
+			return nil, fmt.Errorf("unknown land use %q", command.Use)
+		}
+		return command, checkBounds(sim, command.Start, command.End)
+	}))
+	s.mux.HandleFunc("POST /roundabouts", s.command(func(sim *entities.Simulation, body []byte) (entities.Command, error) {
+		command := entities.ToggleRoundaboutCommand{}
+		if err := json.Unmarshal(body, &command); err != nil {
+			return nil, err
+		}
+		return command, checkBounds(sim, command.At)
+	}))
+	s.mux.HandleFunc("POST /taxes", s.command(func(sim *entities.Simulation, body []byte) (entities.Command, error) {
+		command := taxRates(sim) // rates left out of the request stay as they are
+		if err := json.Unmarshal(body, &command); err != nil {
+			return nil, err
+		}
+		rates := []float64{command.CorporateTaxRate, command.SalesTaxRate}
+		for _, bracket := range command.IncomeTaxBrackets {
+			rates = append(rates, bracket.Rate)
+		}
+		for _, rate := range rates {
+			if rate < 0 || rate > 100 {
+				return nil, fmt.Errorf("tax rates are percentages, got %g", rate)
+			}
+		}
+		return command, nil
+	}))
+	s.mux.HandleFunc("POST /speed", s.setSpeed)
+	s.mux.HandleFunc("POST /save", s.save)
+	s.mux.HandleFunc("GET /events", s.streamEvents)
+
+	return s
+}
+
+// ServeHTTP handles an API request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the API on a loopback address, such as localhost:8080
+func (s *Server) ListenAndServe(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("the API can only be served on localhost, not %s", host)
+	}
+	return http.ListenAndServe(addr, s)
+}
+
+// SetSimulation serves a simulation, such as when a new game is started
+func (s *Server) SetSimulation(sim *entities.Simulation) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+		s.unsubscribe = nil
+	}
+	s.sim = sim
+	if sim == nil {
+		return
+	}
+
+	unsubscribeEvents := sim.Events().Subscribe(func(event entities.Event) { // called while the simulation is locked
+		data, err := json.Marshal(struct {
+			Date  time.Time
+			Type  string
+			Text  string
+			Event entities.Event
+		}{sim.Date, strings.TrimPrefix(fmt.Sprintf("%T", event), "entities."), event.String(), event})
+		if err == nil {
+			s.broadcast(message{event: "event", data: data})
+		}
+	})
+	unsubscribeStats := entities.SubscribeTo(sim.StatsEvents(), func(stats entities.Stats) {
+		if data, err := json.Marshal(stats); err == nil {
+			s.broadcast(message{event: "stats", data: data})
+		}
+	})
+	s.unsubscribe = func() {
+		unsubscribeEvents()
+		unsubscribeStats()
+	}
+}
+
+func (s *Server) simulation() *entities.Simulation {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sim
+}
+
+// read returns a handler that responds with a view of the simulation as JSON
+func (s *Server) read(view func(sim *entities.Simulation) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sim := s.simulation()
+		if sim == nil {
+			writeError(w, http.StatusServiceUnavailable, errors.New("no game is running"))
+			return
+		}
+
+		sim.Mutex.RLock()
+		data, err := json.Marshal(view(sim))
+		sim.Mutex.RUnlock()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, data)
+	}
+}
+
+// command returns a handler that decodes a player command from the request and executes
+// it, journalling it as if it came from the game. It responds with the journal entry.
+func (s *Server) command(decode func(sim *entities.Simulation, body []byte) (entities.Command, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sim := s.simulation()
+		if sim == nil {
+			writeError(w, http.StatusServiceUnavailable, errors.New("no game is running"))
+			return
+		}
+		body, err := readBody(w, r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		sim.Mutex.Lock()
+		command, err := decode(sim, body)
+		if err == nil {
+			sim.Execute(command)
+		}
+		entry := entities.JournalEntry{Date: sim.Date, Command: command}
+		sim.Mutex.Unlock()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, data)
+	}
+}
+
+func (s *Server) setSpeed(w http.ResponseWriter, r *http.Request) {
+	sim := s.simulation()
+	if sim == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no game is running"))
+		return
+	}
+	request := struct{ Speed string }{}
+	body, err := readBody(w, r)
+	if err == nil {
+		err = json.Unmarshal(body, &request)
+	}
+	speed, exists := speeds[strings.ToLower(request.Speed)]
+	if err == nil && !exists {
+		err = fmt.Errorf("unknown speed %q, expected pause, slow, mid, fast or ultra", request.Speed)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sim.Mutex.Lock()
+	sim.SimulationSpeed = speed
+	sim.Mutex.Unlock()
+	data, _ := json.Marshal(struct{ Speed string }{strings.ToLower(request.Speed)})
+	writeJSON(w, data)
+}
+
+func (s *Server) save(w http.ResponseWriter, r *http.Request) {
+	sim := s.simulation()
+	if sim == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("no game is running"))
+		return
+	}
+
+	sim.Mutex.RLock()
+	simCopy := sim.Clone()
+	sim.Mutex.RUnlock()
+	if simCopy.CityName == "" {
+		writeError(w, http.StatusConflict, errors.New("the city has to be named before it can be saved"))
+		return
+	}
+	if err := gamefile.Save(simCopy); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	data, _ := json.Marshal(struct{ Path string }{gamefile.SavePath(simCopy)})
+	writeJSON(w, data)
+}
+
+// streamEvents streams the simulation's stats and events to the client as server-sent events
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	client := make(chan message, clientBuffer)
+	s.clientsMutex.Lock()
+	s.clients[client] = true
+	s.clientsMutex.Unlock()
+	defer func() {
+		s.clientsMutex.Lock()
+		delete(s.clients, client)
+		s.clientsMutex.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case m := <-client:
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.event, m.data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// broadcast sends a message to every streaming client, skipping clients that have fallen behind
+func (s *Server) broadcast(m message) {
+	s.clientsMutex.Lock()
+	defer s.clientsMutex.Unlock()
+	for client := range s.clients {
+		select {
+		case client <- m:
+		default:
+		}
+	}
+}
+
+func taxRates(sim *entities.Simulation) entities.SetTaxRatesCommand {
+	return entities.SetTaxRatesCommand{
+		CorporateTaxRate:  sim.Government.CorporateTaxRate,
+		SalesTaxRate:      sim.Government.SalesTaxRate,
+		IncomeTaxBrackets: slices.Clone(sim.Government.IncomeTaxBrackets),
+	}
+}
+
+func checkBounds(sim *entities.Simulation, points ...entities.Point) error {
+	for _, point := range points {
+		if !sim.Geography.BoundsCheck(point.X, point.Y) {
+			return fmt.Errorf("%d,%d is off the map", point.X, point.Y)
+		}
+	}
+	return nil
+}
+
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	return io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+}
+
+func writeJSON(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	data, _ := json.Marshal(struct{ Error string }{err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
@@ -0,0 +1,133 @@
+package api_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/janithl/citylyf/internal"
+	"github.com/janithl/citylyf/internal/api"
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+func newTestServer(t *testing.T) (*internal.SimRunner, *httptest.Server) {
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard}
+	simRunner.NewGame(nil)
+	server := api.NewServer()
+	server.SetSimulation(simRunner.Sim())
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.SetSimulation(nil)
+	})
+	return simRunner, httpServer
+}
+
+func request(t *testing.T, method, url, body string, response any) int {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if response != nil {
+		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestServerRead(t *testing.T) {
+	t.Parallel()
+	simRunner, server := newTestServer(t)
+
+	stats := entities.Stats{}
+	if status := request(t, "GET", server.URL+"/stats", "", &stats); status != http.StatusOK || stats.Reserves != 1000000 {
+		t.Errorf("unexpected stats response %d: %+v", status, stats)
+	}
+	companies := []entities.Company{}
+	if request(t, "GET", server.URL+"/companies", "", &companies); len(companies) != len(simRunner.Sim().Companies) {
+		t.Errorf("expected %d companies, got %d", len(simRunner.Sim().Companies), len(companies))
+	}
+	tiles := [][]entities.Tile{}
+	if request(t, "GET", server.URL+"/tiles", "", &tiles); len(tiles) != simRunner.Sim().Geography.Size {
+		t.Errorf("expected %d columns of tiles, got %d", simRunner.Sim().Geography.Size, len(tiles))
+	}
+}
+
+func TestServerCommands(t *testing.T) {
+	t.Parallel()
+	simRunner, server := newTestServer(t)
+	sim := simRunner.Sim()
+
+	road := `{"Start": {"X": 4, "Y": 10}, "End": {"X": 12, "Y": 10}, "RoadType": "asphalt"}`
+	if status := request(t, "POST", server.URL+"/roads", road, nil); status != http.StatusOK || len(sim.Geography.GetRoads()) != 1 {
+		t.Errorf("expected the road to be placed, got %d", status)
+	}
+	offMap := `{"Start": {"X": 4, "Y": 10}, "End": {"X": 1000, "Y": 10}, "RoadType": "asphalt"}`
+	if status := request(t, "POST", server.URL+"/roads", offMap, nil); status != http.StatusBadRequest {
+		t.Errorf("expected a road off the map to be refused, got %d", status)
+	}
+
+	if status := request(t, "POST", server.URL+"/taxes", `{"SalesTaxRate": 15}`, nil); status != http.StatusOK {
+		t.Errorf("expected the tax rates to be set, got %d", status)
+	}
+	if sim.Government.SalesTaxRate != 15 || sim.Government.CorporateTaxRate != 9.5 || len(sim.Government.IncomeTaxBrackets) != 4 {
+		t.Error("expected only the sales tax rate to change")
+	}
+	if status := request(t, "POST", server.URL+"/taxes", `{"CorporateTaxRate": 150}`, nil); status != http.StatusBadRequest {
+		t.Errorf("expected a tax rate over 100%% to be refused, got %d", status)
+	}
+	if entries := sim.Journal().Entries; len(entries) != 2 || entries[1].Command.Type() != "SetTaxRates" {
+		t.Error("expected the road and tax rates to be journalled")
+	}
+
+	if status := request(t, "POST", server.URL+"/speed", `{"Speed": "fast"}`, nil); status != http.StatusOK || sim.SimulationSpeed != entities.Fast {
+		t.Errorf("expected the speed to be changed, got %d", status)
+	}
+	if status := request(t, "POST", server.URL+"/save", "", nil); status != http.StatusConflict {
+		t.Errorf("expected an unnamed city not to be saved, got %d", status)
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	t.Parallel()
+	simRunner, server := newTestServer(t)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %s", resp.Header.Get("Content-Type"))
+	}
+
+	var uiStats atomic.Int32 // the game's bottom bar also follows the stats
+	entities.SubscribeTo(simRunner.Sim().StatsEvents(), func(entities.Stats) { uiStats.Add(1) })
+	go simRunner.Advance(40) // on to the monthly economy calculation
+	received := map[string]bool{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && !(received["stats"] && received["event"]) {
+		if event, found := strings.CutPrefix(scanner.Text(), "event: "); found {
+			received[event] = true
+		}
+	}
+	if !received["stats"] || !received["event"] {
+		t.Errorf("expected stats and simulation events to be streamed, got %v", received)
+	}
+	if uiStats.Load() == 0 {
+		t.Error("expected the stats to be sent to other subscribers as well as streamed")
+	}
+}
@@ -0,0 +1,279 @@
+// Package batch runs many seeded simulations of a city side by side, and summarises the spread of
+// their outcomes, to see how changes to the simulation's constants play out
+package batch
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/janithl/citylyf/internal"
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/mayor"
+	"github.com/janithl/citylyf/internal/scenario"
+)
+
+// Metric is a recorded statistic of the city that is summarised across runs
+type Metric struct {
+	Name    string
+	Average bool // averaged over each year, rather than taken at the end of it
+}
+
+// Metrics are the figures that are summarised. Recession is averaged, giving the percentage of
+// each year spent in recession.
+var Metrics = []Metric{
+	{Name: "Population"},
+	{Name: "GDP"},
+	{Name: "Unemployment"},
+	{Name: "Inflation"},
+	{Name: "Reserves"},
+	{Name: "Recession", Average: true},
+}
+
+// Percentiles are the percentiles of each metric that are reported
+var Percentiles = []float64{5, 25, 50, 75, 95}
+
+// Config describes a batch of simulations
+type Config struct {
+	Runs      int                        // number of simulations
+	Years     int                        // number of years each is simulated for
+	Seed      uint64                     // seed of the first simulation, the others use the seeds after it
+	Workers   int                        // number of simulations run at once, the number of CPUs if zero
+	Scenario  *scenario.Scenario         // starting conditions, the default ones if nil
+	Catalogue *entities.Catalogue        // industries and jobs of each city, the default ones if nil
+	Mayor     func() mayor.Mayor         // returns a mayor to play each city, if not nil
+	Journal   *entities.Journal          // commands given to each city on the dates in the journal, if not nil
+	Progress  func(run int, seed uint64) // called as each simulation finishes, if not nil
+}
+
+// Outcome is how a single simulation turned out
+type Outcome struct {
+	Seed   uint64
+	Yearly map[string][]float64 // value of each metric for each year
+}
+
+// Run runs the batch of simulations, returning their outcomes in the order of their seeds
+func Run(config Config) []*Outcome {
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	outcomes := make([]*Outcome, config.Runs)
+	runs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, config.Runs) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range runs {
+				seed := config.Seed + uint64(run)
+				outcomes[run] = simulate(config, seed)
+				if config.Progress != nil {
+					config.Progress(run, seed)
+				}
+			}
+		}()
+	}
+	for run := range config.Runs {
+		runs <- run
+	}
+	close(runs)
+	wg.Wait()
+	return outcomes
+}
+
+// simulate runs a single simulation with the given seed
+func simulate(config Config, seed uint64) *Outcome {
+	simRunner := &internal.SimRunner{Seed: seed, EventLog: io.Discard, Scenario: config.Scenario, Catalogue: config.Catalogue}
+	if config.Mayor != nil {
+		simRunner.Mayor = config.Mayor()
+	}
+	simRunner.NewGame(nil)
+	if config.Journal != nil {
+		simRunner.Replay(config.Journal)
+	}
+
+	sim := simRunner.Sim()
+	start := sim.Date
+	simRunner.RunUntil(start.AddDate(config.Years, 0, 0))
+	return newOutcome(seed, sim.Statistics, start, config.Years)
+}
+
+// newOutcome takes the value of each metric for each year from a city's monthly statistics
+func newOutcome(seed uint64, statistics *entities.Statistics, start time.Time, years int) *Outcome {
+	outcome := &Outcome{Seed: seed, Yearly: make(map[string][]float64)}
+	for _, metric := range Metrics {
+		values := make([]float64, years)
+		for year := range years {
+			from, to := start.AddDate(year, 0, 0), start.AddDate(year+1, 0, 0)
+			total, months := 0.0, 0
+			for i, date := range statistics.Dates {
+				if date.After(from) && !date.After(to) { // the figures recorded on the first of the month are for the month before
+					total += statistics.Value(metric.Name, i)
+					months++
+					values[year] = statistics.Value(metric.Name, i)
+				}
+			}
+			if metric.Average && months > 0 {
+				values[year] = total / float64(months)
+			}
+		}
+		outcome.Yearly[metric.Name] = values
+	}
+	return outcome
+}
+
+// Final returns the value of a metric at the end of the simulation, or its average over the whole
+// simulation for averaged metrics
+func (o *Outcome) Final(metric Metric) float64 {
+	values := o.Yearly[metric.Name]
+	if len(values) == 0 {
+		return 0
+	}
+	if metric.Average {
+		total := 0.0
+		for _, value := range values {
+			total += value
+		}
+		return total / float64(len(values))
+	}
+	return values[len(values)-1]
+}
+
+// Summary is the spread of a metric across the runs
+type Summary struct {
+	Metric      string
+	Year        int // year of the simulation, or 0 for the final outcome
+	Mean        float64
+	Percentiles []float64 // values at each of the Percentiles
+}
+
+// Summarise returns the spread of each metric across the outcomes for each year, followed by the
+// spread of their final outcomes
+func Summarise(outcomes []*Outcome, years int) []Summary {
+	summaries := []Summary{}
+	for year := 1; year <= years; year++ {
+		for _, metric := range Metrics {
+			values := []float64{}
+			for _, outcome := range outcomes {
+				if series := outcome.Yearly[metric.Name]; year <= len(series) {
+					values = append(values, series[year-1])
+				}
+			}
+			summaries = append(summaries, summarise(metric.Name, year, values))
+		}
+	}
+	for _, metric := range Metrics {
+		values := []float64{}
+		for _, outcome := range outcomes {
+			values = append(values, outcome.Final(metric))
+		}
+		summaries = append(summaries, summarise(metric.Name, 0, values))
+	}
+	return summaries
+}
+
+func summarise(metric string, year int, values []float64) Summary {
+	summary := Summary{Metric: metric, Year: year, Percentiles: make([]float64, len(Percentiles))}
+	if len(values) == 0 {
+		return summary
+	}
+
+	sorted := slices.Sorted(slices.Values(values))
+	total := 0.0
+	for _, value := range sorted {
+		total += value
+	}
+	summary.Mean = total / float64(len(sorted))
+	for i, p := range Percentiles {
+		summary.Percentiles[i] = Percentile(sorted, p)
+	}
+	return summary
+}
+
+// Percentile returns the pth percentile of sorted values, interpolating between the nearest two
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := min(lower+1, len(sorted)-1)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// WriteTable writes the summaries of the final outcomes as a table
+func WriteTable(w io.Writer, summaries []Summary) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(tw, "Metric\tMean\t")
+	for _, p := range Percentiles {
+		fmt.Fprintf(tw, "P%g\t", p)
+	}
+	fmt.Fprintln(tw)
+	for _, summary := range summaries {
+		if summary.Year != 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%.2f\t", summary.Metric, summary.Mean)
+		for _, value := range summary.Percentiles {
+			fmt.Fprintf(tw, "%.2f\t", value)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the summaries as CSV, with a row for each metric in each year. The final outcomes
+// are written as year "final".
+func WriteCSV(w io.Writer, summaries []Summary) error {
+	cw := csv.NewWriter(w)
+	header := []string{"Year", "Metric", "Mean"}
+	for _, p := range Percentiles {
+		header = append(header, fmt.Sprintf("P%g", p))
+	}
+	cw.Write(header)
+	for _, summary := range summaries {
+		year := "final"
+		if summary.Year != 0 {
+			year = strconv.Itoa(summary.Year)
+		}
+		row := []string{year, summary.Metric, formatFloat(summary.Mean)}
+		for _, value := range summary.Percentiles {
+			row = append(row, formatFloat(value))
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteOutcomesCSV writes the final outcome of each run as CSV, with a row for each seed
+func WriteOutcomesCSV(w io.Writer, outcomes []*Outcome) error {
+	cw := csv.NewWriter(w)
+	header := []string{"Seed"}
+	for _, metric := range Metrics {
+		header = append(header, metric.Name)
+	}
+	cw.Write(header)
+	for _, outcome := range outcomes {
+		row := []string{strconv.FormatUint(outcome.Seed, 10)}
+		for _, metric := range Metrics {
+			row = append(row, formatFloat(outcome.Final(metric)))
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
@@ -0,0 +1,66 @@
+package batch_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/janithl/citylyf/internal/batch"
+	"github.com/janithl/citylyf/internal/mayor"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5}
+	for p, expected := range map[float64]float64{0: 1, 25: 2, 50: 3, 90: 4.6, 100: 5} {
+		if value := batch.Percentile(sorted, p); value != expected {
+			t.Errorf("expected P%g to be %g, got %g", p, expected, value)
+		}
+	}
+	if value := batch.Percentile(nil, 50); value != 0 {
+		t.Errorf("expected the percentile of nothing to be 0, got %g", value)
+	}
+}
+
+// TestRun checks that a batch gives the same outcomes however many simulations run at once, and
+// that they are summarised for every year
+func TestRun(t *testing.T) {
+	config := batch.Config{Runs: 3, Years: 2, Seed: 42, Workers: 1, Mayor: func() mayor.Mayor { return &mayor.Planner{} }}
+	outcomes := batch.Run(config)
+	config.Workers = 3
+	if !reflect.DeepEqual(outcomes, batch.Run(config)) {
+		t.Error("expected the same outcomes when running simulations at once")
+	}
+	for i, outcome := range outcomes {
+		if outcome.Seed != 42+uint64(i) || len(outcome.Yearly["Population"]) != 2 {
+			t.Fatalf("expected 2 years of outcomes for seed %d, got %v", 42+i, outcome)
+		}
+		if outcome.Final(batch.Metrics[0]) == 0 {
+			t.Errorf("expected the mayor to grow the city with seed %d", outcome.Seed)
+		}
+	}
+
+	summaries := batch.Summarise(outcomes, 2)
+	if len(summaries) != 3*len(batch.Metrics) {
+		t.Fatalf("expected summaries for 2 years and the final outcome, got %d", len(summaries))
+	}
+	for _, summary := range summaries {
+		if summary.Percentiles[0] > summary.Percentiles[2] || summary.Percentiles[2] > summary.Percentiles[4] {
+			t.Errorf("expected percentiles in order, got %v", summary)
+		}
+	}
+
+	var csv, table bytes.Buffer
+	if err := batch.WriteCSV(&csv, summaries); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != len(summaries)+1 || !strings.HasPrefix(lines[len(lines)-1], "final,Recession,") {
+		t.Errorf("expected a CSV row for each summary, got %q", csv.String())
+	}
+	if err := batch.WriteTable(&table, summaries); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(table.String()), "\n"); len(lines) != len(batch.Metrics)+1 {
+		t.Errorf("expected a table row for each metric, got %q", table.String())
+	}
+}
@@ -1,82 +1,101 @@
 package economy
 
 import (
-	"fmt"
-	"maps"
-	"math/rand/v2"
-	"time"
-
 	"github.com/janithl/citylyf/internal/entities"
 )
 
 type CalculationService struct {
-	companyService  *CompanyService
-	lastCalculation time.Time
-	nextCalculation time.Time
+	companyService *CompanyService
 }
 
 func NewCalculationService(cs *CompanyService) *CalculationService {
-	return &CalculationService{
-		companyService:  cs,
-		lastCalculation: entities.Sim.Date,
-		nextCalculation: entities.Sim.Date.AddDate(0, 1, 0),
-	}
+	return &CalculationService{companyService: cs}
 }
 
-func (cs *CalculationService) CalculateEconomy() {
-	if entities.Sim.Date.Before(cs.nextCalculation) { // run monthly
-		return
-	}
-	daysSinceLastCalculation := entities.Sim.Date.Sub(cs.lastCalculation).Hours() / entities.HoursPerDay
-	cs.lastCalculation = entities.Sim.Date
-	cs.nextCalculation = cs.lastCalculation.AddDate(0, 1, 0)
+// CalculateEconomy runs the monthly economic calculations for the month that has just ended
+func (cs *CalculationService) CalculateEconomy(sim *entities.Simulation) {
+	daysSinceLastCalculation := sim.Date.Sub(sim.Date.AddDate(0, -1, 0)).Hours() / entities.HoursPerDay
 
 	// calculate impact of population growth on city economy
-	populationGrowth := entities.Sim.People.PopulationGrowthRate()
-
-	entities.Sim.People.UpdatePopulationValues()
-	entities.Sim.People.CalculateAgeGroups()
-	entities.Sim.People.CalculateUnemployment()
-
-	entities.Sim.Market.CalculateInflation(populationGrowth)
-	marketGrowth := entities.Sim.Market.CalculateMarketGrowth()
-	entities.Sim.Market.CalculateHousingAndRetailDemand(len(entities.Sim.Houses), entities.Sim.Houses.GetFreeHouses())
-	entities.Sim.Market.UpdateMarketValue(marketGrowth)
-
-	fmt.Printf("[ Econ ] %s | Next calculation on %s\n", entities.Sim.GetStats(), cs.nextCalculation.Format("2006-01-02"))
-
-	if marketGrowth > 0 && rand.IntN(100) < 5 { // 5% chance of a farm being opened during good times
-		newFarm := cs.companyService.GenerateRandomCompany(entities.SME, entities.Agriculture)
-		entities.Sim.Companies.PlaceAgriculture(newFarm)
-		fmt.Printf("[ Econ ] Growth! %s (%s) founded!\n", newFarm.Name, newFarm.Industry)
-	} else if entities.Sim.Market.RetailDemand > 0.01 && rand.IntN(100) < 25 { // 25% chance of a shop being opened when retail demand over 1%
-		newRetailCompany := cs.companyService.GenerateRandomCompany(entities.Micro, entities.Retail)
-		entities.Sim.Companies.PlaceRetail(newRetailCompany)
-		fmt.Printf("[ Econ ] Growth! %s (%s) founded!\n", newRetailCompany.Name, newRetailCompany.Industry)
+	populationGrowth := sim.People.PopulationGrowthRate()
+
+	sim.People.UpdatePopulationValues()
+	sim.People.CalculateAgeGroups(sim.Date)
+	sim.People.CalculateUnemployment(sim.Date)
+
+	sim.Market.CalculateInflation(sim, populationGrowth)
+	marketGrowth := sim.Market.CalculateMarketGrowth(sim)
+	sim.Market.CalculateHousingAndRetailDemand(sim, len(sim.Houses), sim.Houses.GetFreeHouses())
+	sim.Market.UpdateMarketValue(marketGrowth)
+
+	sim.Events().Publish(entities.EconomyCalculated{Stats: sim.GetStatsSnapshot(), NextCalculation: sim.Date.AddDate(0, 1, 0)})
+
+	// companies without premises move into newly zoned land before any new ones are founded on it
+	sim.Companies.Settle(sim)
+
+	// companies are only founded where there is a vacant site in their industry's zone
+	if marketGrowth > 0 && sim.Rand().IntN(100) < 5 { // 5% chance of a farm being opened during good times
+		cs.found(sim, entities.SME, entities.Agriculture)
+	} else if sim.Market.RetailDemand > 0.01 && sim.Rand().IntN(100) < 25 { // 25% chance of a shop being opened when retail demand over 1%
+		cs.found(sim, entities.Micro, entities.Retail)
+	} else if marketGrowth > 0 && sim.Rand().IntN(100) < 10 { // 10% chance of an office or factory being opened during good times
+		if industry := sim.Catalogue.RandomIndustryIn(sim.Rand(), entities.CommercialUse, entities.IndustrialUse); industry != "" {
+			cs.found(sim, entities.GetRandomCompanySize(sim.Rand()), industry)
+		}
+	}
+
+	// companies invest when the return on it beats the cost of borrowing, and hire for what they invest in
+	totalProfits, totalInvestment, totalDebt := 0.0, 0.0, 0.0
+	for _, id := range sim.Companies.GetIDs() {
+		company := sim.Companies[id]
+		totalProfits += company.CalculateProfit(sim, daysSinceLastCalculation)
+		totalInvestment += company.Invest(sim)
+		totalDebt += company.Debt
+		company.DetermineJobOpenings(sim)
+		company.ReviseWages(sim)
+		sim.Companies[id] = company
 	}
+	sim.Market.ReportCompanyProfits(totalProfits)
+	sim.Market.ReportBusinessInvestment(totalInvestment, totalDebt)
 
-	totalProfits := 0.0
-	for id, company := range entities.Sim.Companies {
-		totalProfits += company.CalculateProfit(daysSinceLastCalculation)
-		company.DetermineJobOpenings()
-		company.ReviseWages()
-		entities.Sim.Companies[id] = company
+	// companies running out of cash lay off staff, and ones that have run out close down
+	closures, layoffs := 0, 0
+	for _, id := range sim.Companies.GetIDs() {
+		company := sim.Companies[id]
+		if company.IsInsolvent() {
+			layoffs += sim.Companies.Close(sim, id)
+			closures++
+		} else if company.Runway() < entities.ShedStaffRunway {
+			layoffs += sim.Companies.ShedStaff(sim, id)
+		}
 	}
-	entities.Sim.Market.ReportCompanyProfits(totalProfits)
+	sim.Market.ReportCompanyClosures(closures, layoffs)
 
 	// do govt interest calcuations (monthly)
-	monthlyInterestRate := (entities.Sim.Market.InterestRate() / 100) * (daysSinceLastCalculation / entities.DaysPerYear)
-	entities.Sim.Government.Reserves += int(float64(entities.Sim.Government.Reserves) * monthlyInterestRate)
+	monthlyInterestRate := (sim.Market.InterestRate() / 100) * (daysSinceLastCalculation / entities.DaysPerYear)
+	sim.Government.Reserves += int(float64(sim.Government.Reserves) * monthlyInterestRate)
 
-	// calculate monthly pay and interest for households
-	for household := range maps.Values(entities.Sim.People.Households) {
-		household.CalculateMonthlyBudget(cs.companyService.AddPayToPayroll)
-		household.Savings += int(float64(household.Savings) * monthlyInterestRate)
+	// calculate monthly pay for households, then bank their savings and repayments
+	for _, id := range sim.People.GetHouseholdIDs() {
+		household := sim.People.Households[id]
+		household.CalculateMonthlyBudget(sim, func(companyID int, payAmount float64) {
+			cs.companyService.AddPayToPayroll(sim, companyID, payAmount)
+		})
 	}
+	sim.Bank.CalculateMonthlyAccounts(sim)
 
-	// collect taxes, revise rents and calculate regional stats and sales
-	entities.Sim.Government.CollectTaxes()
-	entities.Sim.People.UpdateAverageWageValues()
-	entities.Sim.Houses.ReviseRents()
-	entities.Sim.Geography.Regions.CalculateRegionalStats()
+	// revise rents and calculate regional stats and sales
+	sim.People.UpdateAverageWageValues()
+	sim.Houses.ReviseRents(sim)
+	sim.Houses.RevalueHouses(sim)
+	sim.Landlord.CalculateMonthlyAccounts(sim)
+	sim.Geography.Regions.CalculateRegionalStats(sim)
+}
+
+// found sets up a new company because of economic growth, if there is a site for it
+func (cs *CalculationService) found(sim *entities.Simulation, size entities.CompanySize, industry entities.Industry) {
+	newCompany := cs.companyService.GenerateRandomCompany(sim, size, industry)
+	if sim.Companies.Place(sim, newCompany) {
+		sim.Events().Publish(entities.CompanyFounded{Name: newCompany.Name, Industry: newCompany.Industry, Growth: true})
+	}
 }
@@ -1,8 +1,6 @@
 package economy
 
 import (
-	"fmt"
-
 	"github.com/janithl/citylyf/internal/entities"
 )
 
@@ -12,27 +10,34 @@ type Employment struct {
 }
 
 // AssignJobs assigns unemployed people to jobs
-func (e *Employment) AssignJobs() {
-	for _, person := range entities.Sim.People.People {
-		if person.IsEmployable() && !person.IsEmployed() {
-			if companyID, remaining := e.findSuitableJob(*person); companyID != 0 {
-				e.CompanyService.AddEmployeeToCompany(companyID, person.ID)
+func (e *Employment) AssignJobs(sim *entities.Simulation) {
+	for _, id := range sim.People.GetPersonIDs() {
+		person := sim.People.People[id]
+		if person.IsEmployable(sim.Date) && !person.IsEmployed() {
+			if companyID, remaining := e.findSuitableJob(sim, *person); companyID != 0 {
+				e.CompanyService.AddEmployeeToCompany(sim, companyID, person.ID)
 				person.EmployerID = companyID
-				fmt.Printf("[  Job ] %s %s has accepted a job as %s, %d jobs remain\n",
-					person.FirstName, person.FamilyName, person.Occupation, remaining)
+				sim.Events().Publish(entities.JobAccepted{
+					FirstName:     person.FirstName,
+					FamilyName:    person.FamilyName,
+					Occupation:    person.Occupation,
+					CompanyID:     companyID,
+					JobsRemaining: remaining,
+				})
 			}
 		}
 	}
 }
 
 // findSuitableJob finds an appropriate job for a person based on their industry and career level
-func (e *Employment) findSuitableJob(p entities.Person) (companyID int, remaining int) {
-	for id, company := range entities.Sim.Companies {
-		if company.Industry == p.Industry {
+func (e *Employment) findSuitableJob(sim *entities.Simulation, p entities.Person) (companyID int, remaining int) {
+	for _, id := range sim.Companies.GetIDs() {
+		company := sim.Companies[id]
+		if company.Industry == p.Industry && company.Location != nil { // companies need premises to take people on
 			if openings, exists := company.JobOpenings[p.CareerLevel]; exists && openings > 0 {
 				openings--
 				company.JobOpenings[p.CareerLevel] = openings
-				entities.Sim.Companies[id] = company
+				sim.Companies[id] = company
 				return company.ID, company.GetNumberOfJobOpenings()
 			}
 		}
@@ -0,0 +1,352 @@
+package entities
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/janithl/citylyf/internal/utils"
+)
+
+const (
+	DepositSpread          = 1.0  // percentage points under the central bank's rate that savings earn
+	MortgageSpread         = 2.0  // percentage points over the central bank's rate that mortgages cost
+	PersonalLoanSpread     = 6.0  // percentage points over the central bank's rate that personal loans and overdrafts cost
+	MortgageTermMonths     = 300  // months over which mortgages are repaid
+	PersonalLoanTermMonths = 36   // months over which personal loans are repaid
+	MaxDebtServiceRatio    = 0.35 // share of a household's monthly income that can go on loan repayments
+	MaxMortgageMultiple    = 4.5  // times its annual income a household can borrow for a mortgage
+	MaxPersonalLoanShare   = 0.5  // share of its annual income a household can borrow as a personal loan
+	DefaultMonths          = 3    // months of missed repayments before a loan is written off
+)
+
+type LoanKind string
+
+const (
+	Mortgage     LoanKind = "mortgage"
+	PersonalLoan LoanKind = "personal loan"
+)
+
+// Loan is money a household has borrowed from the bank
+type Loan struct {
+	ID, HouseholdID int
+	Kind            LoanKind
+	Principal       float64 // amount originally lent
+	Balance         float64 // amount still owed
+	IssueDate       time.Time
+	TermMonths      int // months over which the loan is repaid
+	MonthsPaid      int // repayments made so far
+	MissedPayments  int // repayments missed in a row
+}
+
+// MonthlyPayment returns the repayment that pays off the loan over the rest of its term at the given
+// annual percentage rate
+func (l *Loan) MonthlyPayment(rate float64) float64 {
+	return monthlyPayment(l.Balance, rate, max(l.TermMonths-l.MonthsPaid, 1))
+}
+
+func (l *Loan) GetStats(sim *Simulation) string {
+	familyName := ""
+	if household, exists := sim.People.Households[l.HouseholdID]; exists {
+		familyName = household.FamilyName(sim.People)
+	}
+	return fmt.Sprintf("%5d %-15s %-13s %-10s %-10s %3d/%3d %d missed", l.ID, familyName, l.Kind,
+		utils.FormatCurrency(l.Principal, "$"), utils.FormatCurrency(l.Balance, "$"), l.MonthsPaid, l.TermMonths, l.MissedPayments)
+}
+
+// monthlyPayment returns the repayment that pays off an amount over a number of months at an annual
+// percentage rate
+func monthlyPayment(amount, rate float64, months int) float64 {
+	r := rate / 1200
+	if r <= 0 {
+		return amount / float64(months)
+	}
+	return amount * r / (1 - math.Pow(1+r, -float64(months)))
+}
+
+// BankHistory tracks the bank's figures over the last 12 months
+type BankHistory struct {
+	Profits, LoanBook, Deposits, WriteOffs []float64
+}
+
+// Bank holds the savings of the city's households and the cash of its companies and landlord, lends to households,
+// and lends to companies at the rate in BorrowingRate. Its rates follow the central bank's rate.
+type Bank struct {
+	Loans                                      map[int]*Loan
+	Capital                                    float64 // the bank's profits, less its losses, since the city was founded
+	InterestIncome, DepositInterest, WriteOffs float64 // this month's, until the month's accounts are closed
+	Defaults                                   int     // household loans defaulted on this month
+	Insolvencies                               int     // companies that went bust owing the bank this month
+	LastProfit                                 float64
+	History                                    BankHistory
+}
+
+func NewBank() *Bank {
+	return &Bank{Loans: make(map[int]*Loan)}
+}
+
+// Rate returns the annual percentage rate the bank lends at for a kind of loan
+func (b *Bank) Rate(sim *Simulation, kind LoanKind) float64 {
+	if kind == Mortgage {
+		return sim.Market.InterestRate() + MortgageSpread
+	}
+	return sim.Market.InterestRate() + PersonalLoanSpread
+}
+
+// DepositRate returns the annual percentage rate the bank pays on savings
+func (b *Bank) DepositRate(sim *Simulation) float64 {
+	return max(sim.Market.InterestRate()-DepositSpread, 0)
+}
+
+// GetLoanIDs returns a sorted list of loan IDs
+func (b *Bank) GetLoanIDs() []int {
+	return slices.Sorted(maps.Keys(b.Loans))
+}
+
+// HouseholdLoans returns a household's loans, in the order they were taken out
+func (b *Bank) HouseholdLoans(householdID int) []*Loan {
+	loans := []*Loan{}
+	for _, id := range b.GetLoanIDs() {
+		if b.Loans[id].HouseholdID == householdID {
+			loans = append(loans, b.Loans[id])
+		}
+	}
+	return loans
+}
+
+// MonthlyRepayments returns what a household repays on its loans each month
+func (b *Bank) MonthlyRepayments(sim *Simulation, householdID int) float64 {
+	repayments := 0.0
+	for _, loan := range b.HouseholdLoans(householdID) {
+		repayments += loan.MonthlyPayment(b.Rate(sim, loan.Kind))
+	}
+	return repayments
+}
+
+// CreditCheck returns true if a household earns enough to borrow an amount: no more than a multiple of
+// its income, with repayments on all of its loans that take up no more than a share of its income
+func (b *Bank) CreditCheck(sim *Simulation, household *Household, kind LoanKind, amount float64) bool {
+	income := float64(household.AnnualIncome(sim.People, sim.Date, false))
+	if income <= 0 || amount <= 0 {
+		return false
+	}
+
+	term := PersonalLoanTermMonths
+	limit := income * MaxPersonalLoanShare
+	if kind == Mortgage {
+		term = MortgageTermMonths
+		limit = income * MaxMortgageMultiple
+	}
+	repayments := b.MonthlyRepayments(sim, household.ID) + monthlyPayment(amount, b.Rate(sim, kind), term)
+	return amount <= limit && repayments <= income/12*MaxDebtServiceRatio
+}
+
+// Lend lends a household an amount if it passes a credit check, paying it into its savings
+func (b *Bank) Lend(sim *Simulation, household *Household, kind LoanKind, amount float64) (*Loan, bool) {
+	if !b.CreditCheck(sim, household, kind, amount) {
+		return nil, false
+	}
+
+	loan := &Loan{
+		ID:          sim.GetNextID(),
+		HouseholdID: household.ID,
+		Kind:        kind,
+		Principal:   amount,
+		Balance:     amount,
+		IssueDate:   sim.Date,
+		TermMonths:  PersonalLoanTermMonths,
+	}
+	if kind == Mortgage {
+		loan.TermMonths = MortgageTermMonths
+	}
+	b.Loans[loan.ID] = loan
+	household.Savings += int(amount)
+	sim.Events().Publish(LoanIssued{FamilyName: household.FamilyName(sim.People), Kind: kind, Amount: amount, Rate: b.Rate(sim, kind)})
+	return loan, true
+}
+
+// Repay pays off a household's loans of a kind out of its savings, as far as they go
+func (b *Bank) Repay(household *Household, kind LoanKind) {
+	for _, loan := range b.HouseholdLoans(household.ID) {
+		if loan.Kind != kind {
+			continue
+		}
+		payment := min(int(math.Ceil(loan.Balance)), household.Savings)
+		if payment <= 0 {
+			return
+		}
+		household.Savings -= payment
+		loan.Balance -= float64(payment)
+		if loan.Balance < 1 {
+			delete(b.Loans, loan.ID)
+		}
+	}
+}
+
+// TransferLoans hands a household's loans to another household, when the two are combined
+func (b *Bank) TransferLoans(fromID, toID int) {
+	for _, loan := range b.HouseholdLoans(fromID) {
+		loan.HouseholdID = toID
+	}
+}
+
+// WriteOff writes off the debt of a company that has gone bust, which won't be repaid
+func (b *Bank) WriteOff(amount float64) {
+	if amount <= 0 {
+		return
+	}
+	b.WriteOffs += amount
+	b.Insolvencies++
+}
+
+// CalculateMonthlyAccounts runs the bank's month once households have been paid: it pays interest on
+// savings, takes repayments on loans and writes off the ones in default, lends overdrawn households
+// enough to clear their overdraft, charges interest on the overdrafts it won't clear, and closes the
+// month's accounts
+func (b *Bank) CalculateMonthlyAccounts(sim *Simulation) {
+	depositRate := b.DepositRate(sim) / 1200
+	overdraftRate := b.Rate(sim, PersonalLoan) / 1200
+	for _, id := range sim.People.GetHouseholdIDs() {
+		household := sim.People.Households[id]
+		if household.Savings > 0 {
+			interest := int(float64(household.Savings) * depositRate)
+			household.Savings += interest
+			b.DepositInterest += float64(interest)
+		}
+	}
+
+	for _, id := range b.GetLoanIDs() {
+		b.service(sim, b.Loans[id])
+	}
+
+	for _, id := range sim.People.GetHouseholdIDs() {
+		household := sim.People.Households[id]
+		if household.Savings >= 0 {
+			continue
+		}
+		if _, ok := b.Lend(sim, household, PersonalLoan, float64(-household.Savings)); !ok {
+			interest := int(math.Ceil(float64(-household.Savings) * overdraftRate))
+			household.Savings -= interest
+			b.InterestIncome += float64(interest)
+		}
+	}
+
+	// companies and the landlord borrow from the bank, and keep their cash in it
+	loanBook, deposits := 0.0, 0.0
+	for _, id := range sim.Companies.GetIDs() {
+		company := sim.Companies[id]
+		b.InterestIncome += company.LastInterest
+		loanBook += company.Debt
+		if company.Cash > 0 {
+			interest := company.Cash * depositRate
+			company.Cash += interest
+			b.DepositInterest += interest
+			deposits += company.Cash
+		}
+	}
+	if sim.Landlord.Cash > 0 {
+		interest := sim.Landlord.Cash * depositRate
+		sim.Landlord.Cash += interest
+		b.DepositInterest += interest
+		deposits += sim.Landlord.Cash
+	}
+	for _, id := range b.GetLoanIDs() {
+		loanBook += b.Loans[id].Balance
+	}
+	for _, id := range sim.People.GetHouseholdIDs() {
+		deposits += float64(max(sim.People.Households[id].Savings, 0))
+	}
+
+	b.LastProfit = b.InterestIncome - b.DepositInterest - b.WriteOffs
+	b.Capital += b.LastProfit
+	b.History.Profits = utils.AddFifo(b.History.Profits, b.LastProfit, 12)
+	b.History.LoanBook = utils.AddFifo(b.History.LoanBook, loanBook, 12)
+	b.History.Deposits = utils.AddFifo(b.History.Deposits, deposits, 12)
+	b.History.WriteOffs = utils.AddFifo(b.History.WriteOffs, b.WriteOffs, 12)
+	b.InterestIncome, b.DepositInterest, b.WriteOffs, b.Defaults, b.Insolvencies = 0, 0, 0, 0, 0
+}
+
+// service takes a month's repayment on a loan, writing it off if the household has left the city or
+// has missed too many repayments
+func (b *Bank) service(sim *Simulation, loan *Loan) {
+	household, exists := sim.People.Households[loan.HouseholdID]
+	if !exists { // the household has left the city
+		b.writeOffLoan(sim, loan, "")
+		return
+	}
+
+	rate := b.Rate(sim, loan.Kind)
+	interest := loan.Balance * rate / 1200
+	payment := math.Ceil(loan.MonthlyPayment(rate))
+	if float64(household.Savings) < payment {
+		loan.MissedPayments++
+		loan.Balance += interest // unpaid interest is added to the loan
+		if loan.MissedPayments >= DefaultMonths {
+			b.writeOffLoan(sim, loan, household.FamilyName(sim.People))
+		}
+		return
+	}
+
+	household.Savings -= int(payment)
+	loan.Balance -= payment - interest
+	loan.MonthsPaid++
+	loan.MissedPayments = 0
+	b.InterestIncome += interest
+	if loan.Balance < 1 {
+		delete(b.Loans, loan.ID)
+	}
+}
+
+// writeOffLoan writes off what is left of a loan in default
+func (b *Bank) writeOffLoan(sim *Simulation, loan *Loan, familyName string) {
+	b.WriteOffs += loan.Balance
+	b.Defaults++
+	delete(b.Loans, loan.ID)
+	sim.Events().Publish(LoanDefaulted{FamilyName: familyName, Kind: loan.Kind, Balance: loan.Balance})
+}
+
+// LoanBook returns the amount owed to the bank at the end of last month
+func (b *Bank) LoanBook() float64 {
+	return utils.GetLastValue(b.History.LoanBook)
+}
+
+// DefaultRate returns the percentage of the bank's lending that was written off over the last 12 months
+func (b *Bank) DefaultRate() float64 {
+	writeOffs := 0.0
+	for _, amount := range b.History.WriteOffs {
+		writeOffs += amount
+	}
+	if writeOffs == 0 {
+		return 0
+	}
+	return writeOffs / (b.LoanBook() + writeOffs) * 100
+}
+
+// Confidence returns how the bank's health moves market sentiment: defaults shake confidence, and a
+// profitable bank steadies it
+func (b *Bank) Confidence() float64 {
+	if b == nil {
+		return 0
+	}
+	confidence := -min(b.DefaultRate()/5, 1)
+	if b.LastProfit > 0 {
+		confidence += 0.25
+	}
+	return confidence
+}
+
+func (b *Bank) clone() *Bank {
+	c := *b
+	c.Loans = make(map[int]*Loan, len(b.Loans))
+	for id, loan := range b.Loans {
+		loanCopy := *loan
+		c.Loans[id] = &loanCopy
+	}
+	c.History.Profits = slices.Clone(b.History.Profits)
+	c.History.LoanBook = slices.Clone(b.History.LoanBook)
+	c.History.Deposits = slices.Clone(b.History.Deposits)
+	c.History.WriteOffs = slices.Clone(b.History.WriteOffs)
+	return &c
+}
@@ -0,0 +1,98 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// newBankHousehold adds a household with a single earner on the given income
+func newBankHousehold(sim *entities.Simulation, income, savings int) *entities.Household {
+	person := &entities.Person{ID: sim.GetNextID(), FamilyName: "Perera", AnnualIncome: income, EmployerID: 1}
+	household := &entities.Household{ID: sim.GetNextID(), MemberIDs: []int{person.ID}, Savings: savings}
+	sim.People.People[person.ID] = person
+	sim.People.Households[household.ID] = household
+	return household
+}
+
+// TestBankLending checks that the bank lends to households that can afford the repayments at rates
+// that follow the central bank's rate, and takes repayments
+func TestBankLending(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	household := newBankHousehold(sim, 60000, 0)
+
+	if rate := sim.Bank.Rate(sim, entities.Mortgage); rate != sim.Market.InterestRate()+entities.MortgageSpread {
+		t.Errorf("expected mortgages at %.2f%% over the central bank's rate, got %.2f%%", entities.MortgageSpread, rate)
+	}
+	if sim.Bank.CreditCheck(sim, household, entities.Mortgage, 60000*5) {
+		t.Error("expected a mortgage of five times the household's income to be declined")
+	}
+	if sim.Bank.CreditCheck(sim, &entities.Household{}, entities.PersonalLoan, 1000) {
+		t.Error("expected a household without income to be declined")
+	}
+
+	mortgage, ok := sim.Bank.Lend(sim, household, entities.Mortgage, 200000)
+	if !ok || household.Savings != 200000 {
+		t.Fatalf("expected a mortgage of 200000 to be paid into the household's savings, got %d", household.Savings)
+	}
+	if sim.Bank.CreditCheck(sim, household, entities.PersonalLoan, 25000) {
+		t.Error("expected a personal loan on top of the mortgage to be declined, as the repayments are too high")
+	}
+
+	payment := mortgage.MonthlyPayment(sim.Bank.Rate(sim, entities.Mortgage))
+	sim.Bank.CalculateMonthlyAccounts(sim)
+	if mortgage.MonthsPaid != 1 || mortgage.Balance >= 200000 || household.Savings >= 200000 {
+		t.Errorf("expected a repayment of %.0f to be taken, got %d months paid and %.0f owed", payment, mortgage.MonthsPaid, mortgage.Balance)
+	}
+	if sim.Bank.LastProfit <= 0 || sim.Bank.LoanBook() != mortgage.Balance {
+		t.Errorf("expected the bank to profit from the mortgage, got %.0f with %.0f lent", sim.Bank.LastProfit, sim.Bank.LoanBook())
+	}
+
+	// raising the central bank's rate raises the repayments
+	sim.Market.History.InterestRate = append(sim.Market.History.InterestRate, sim.Market.InterestRate()+2)
+	if higher := mortgage.MonthlyPayment(sim.Bank.Rate(sim, entities.Mortgage)); higher <= payment {
+		t.Errorf("expected repayments to rise with rates, from %.0f to more than that, got %.0f", payment, higher)
+	}
+}
+
+// TestBankDefaults checks that overdrawn households are lent enough to clear their overdraft, that
+// savings and company cash earn interest, that loans that go unpaid are written off, and that defaults
+// shake market confidence
+func TestBankDefaults(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	household := newBankHousehold(sim, 60000, -5000)
+	saver := newBankHousehold(sim, 0, 120000)
+	company := &entities.Company{ID: sim.GetNextID(), Cash: 120000}
+	sim.Companies[company.ID] = company
+
+	sim.Bank.CalculateMonthlyAccounts(sim)
+	loans := sim.Bank.HouseholdLoans(household.ID)
+	if len(loans) != 1 || loans[0].Kind != entities.PersonalLoan || loans[0].Principal != 5000 || household.Savings != 0 {
+		t.Fatalf("expected the overdraft to be cleared with a personal loan, got %d loans and %d savings", len(loans), household.Savings)
+	}
+	if saver.Savings <= 120000 || company.Cash <= 120000 {
+		t.Error("expected savings and company cash to earn interest")
+	}
+	if confidence := sim.Bank.Confidence(); confidence < 0 {
+		t.Errorf("expected no loss of confidence without defaults, got %.2f", confidence)
+	}
+
+	for range entities.DefaultMonths {
+		sim.Bank.CalculateMonthlyAccounts(sim)
+	}
+	if len(sim.Bank.Loans) != 0 {
+		t.Fatalf("expected the unpaid loan to be written off, got %d loans", len(sim.Bank.Loans))
+	}
+	if sim.Bank.DefaultRate() != 100 || sim.Bank.LastProfit >= 0 || sim.Bank.Confidence() >= 0 {
+		t.Errorf("expected all lending to have defaulted, shaking confidence, got %.2f%% and %.2f", sim.Bank.DefaultRate(), sim.Bank.Confidence())
+	}
+
+	company.Debt = 50000
+	sim.Companies.Close(sim, company.ID)
+	if sim.Bank.Insolvencies != 1 || sim.Bank.Defaults != 0 || sim.Bank.WriteOffs != 50000 {
+		t.Errorf("expected the bust company's debt to be written off as an insolvency, got %d insolvencies and %d defaults",
+			sim.Bank.Insolvencies, sim.Bank.Defaults)
+	}
+}
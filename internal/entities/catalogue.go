@@ -0,0 +1,179 @@
+package entities
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+)
+
+//go:embed catalogue.json
+var defaultCatalogue []byte
+
+// IndustryJob represents the jobs in an industry
+type IndustryJob struct {
+	Industry        Industry
+	Job             Job
+	EducationLevels []EducationLevel
+	SalaryRange     map[CareerLevel][2]int // Min and max salary for each career level
+	JobAbundance    int                    // Higher value = more common job
+}
+
+// Catalogue lists the industries companies are founded in, the zone their companies are sited in,
+// and the jobs in each industry. Each city keeps the catalogue it was founded with.
+type Catalogue struct {
+	Industries []Industry
+	Zones      map[Industry]LandUse // commercial for industries that are not listed
+	Jobs       []IndustryJob
+}
+
+// workingLevels are the career levels every job has a salary range for, from lowest paid to highest
+var workingLevels = []CareerLevel{EntryLevel, MidLevel, SeniorLevel, ExecutiveLevel}
+
+func mustLoadDefaultCatalogue() *Catalogue {
+	c, err := ParseCatalogue(defaultCatalogue)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// DefaultCatalogue returns a copy of the catalogue built into the game
+func DefaultCatalogue() *Catalogue {
+	return mustLoadDefaultCatalogue()
+}
+
+// HasIndustry returns true if companies can be founded in the industry
+func (c *Catalogue) HasIndustry(industry Industry) bool {
+	return slices.Contains(c.Industries, industry)
+}
+
+// LandUse returns the zone companies in the industry are sited in, commercial unless the catalogue
+// says otherwise
+func (c *Catalogue) LandUse(industry Industry) LandUse {
+	if use, exists := c.Zones[industry]; exists {
+		return use
+	}
+	return CommercialUse
+}
+
+// RandomIndustry returns a random industry
+func (c *Catalogue) RandomIndustry(rng *rand.Rand) Industry {
+	return c.Industries[rng.IntN(len(c.Industries))]
+}
+
+// RandomIndustryIn returns a random industry whose companies are sited in one of the given zones,
+// or an empty industry if there are none
+func (c *Catalogue) RandomIndustryIn(rng *rand.Rand, uses ...LandUse) Industry {
+	industries := []Industry{}
+	for _, industry := range c.Industries {
+		if slices.Contains(uses, c.LandUse(industry)) {
+			industries = append(industries, industry)
+		}
+	}
+	if len(industries) == 0 {
+		return ""
+	}
+	return industries[rng.IntN(len(industries))]
+}
+
+// ParseCatalogue decodes and validates a catalogue from JSON
+func ParseCatalogue(data []byte) (*Catalogue, error) {
+	c := &Catalogue{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Override returns a copy of the catalogue with the industries and jobs of another added to it.
+// Jobs with the same industry and name as one in the catalogue replace it.
+func (c *Catalogue) Override(other *Catalogue) *Catalogue {
+	o := &Catalogue{Industries: slices.Clone(c.Industries), Zones: maps.Clone(c.Zones), Jobs: slices.Clone(c.Jobs)}
+	for _, industry := range other.Industries {
+		if !slices.Contains(o.Industries, industry) {
+			o.Industries = append(o.Industries, industry)
+		}
+	}
+	if o.Zones == nil {
+		o.Zones = make(map[Industry]LandUse)
+	}
+	maps.Copy(o.Zones, other.Zones)
+	for _, job := range other.Jobs {
+		if i := slices.IndexFunc(o.Jobs, func(j IndustryJob) bool { return j.Industry == job.Industry && j.Job == job.Job }); i >= 0 {
+			o.Jobs[i] = job
+		} else {
+			o.Jobs = append(o.Jobs, job)
+		}
+	}
+	return o
+}
+
+// Validate returns an error describing every problem with the catalogue, or nil if it can be used
+func (c *Catalogue) Validate() error {
+	errs := []error{}
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	for _, industry := range []Industry{Agriculture, Retail} { // the economy founds farms and shops itself
+		check(slices.Contains(c.Industries, industry), "industry %s is missing", industry)
+	}
+	for i, industry := range c.Industries {
+		check(industry != "", "industry %d has no name", i+1)
+		check(!slices.Contains(c.Industries[:i], industry), "industry %s is listed more than once", industry)
+		check(slices.ContainsFunc(c.Jobs, func(j IndustryJob) bool { return j.Industry == industry }), "industry %s has no jobs", industry)
+	}
+
+	zones := map[Industry]LandUse{Agriculture: AgricultureUse, Retail: RetailUse} // farms and shops are sited by the economy
+	for industry, use := range c.Zones {
+		check(slices.Contains(c.Industries, industry), "zone for unknown industry %s", industry)
+		check(slices.Contains(CompanyUses, use), "industry %s has unknown zone %q", industry, use)
+		if expected, exists := zones[industry]; exists {
+			check(use == expected, "industry %s must be in the %s zone, not %q", industry, expected, use)
+		}
+	}
+	for industry, expected := range zones {
+		_, exists := c.Zones[industry]
+		check(exists || !slices.Contains(c.Industries, industry), "industry %s has no zone, it must be in the %s zone", industry, expected)
+	}
+
+	for i, job := range c.Jobs {
+		name := fmt.Sprintf("%s job %q", job.Industry, job.Job)
+		check(job.Job != "", "job %d has no name", i+1)
+		check(job.Industry != "", "job %d has no industry", i+1)
+		check(job.Industry == "" || slices.Contains(c.Industries, job.Industry), "%s is in an unknown industry", name)
+		check(!slices.ContainsFunc(c.Jobs[:i], func(j IndustryJob) bool { return j.Industry == job.Industry && j.Job == job.Job }), "%s is listed more than once", name)
+		check(job.JobAbundance > 0, "%s abundance %d is not positive", name, job.JobAbundance)
+		check(len(job.EducationLevels) > 0, "%s has no education levels", name)
+		for _, education := range job.EducationLevels {
+			check(slices.Contains(EducationLevels, education), "%s has unknown education level %q", name, education)
+		}
+
+		for level := range job.SalaryRange {
+			check(slices.Contains(workingLevels, level), "%s has a salary range for unknown career level %q", name, level)
+		}
+		for l, level := range workingLevels {
+			salaryRange, exists := job.SalaryRange[level]
+			check(exists, "%s has no salary range for %s", name, level)
+			check(salaryRange[0] > 0 && salaryRange[0] <= salaryRange[1], "%s %s salary range %v is not a positive min and max", name, level, salaryRange)
+			if previous, hasPrevious := job.SalaryRange[workingLevels[max(l-1, 0)]]; l > 0 && hasPrevious && exists {
+				check(salaryRange[0] >= previous[1], "%s %s salary range %v overlaps the %s range %v", name, level, salaryRange, workingLevels[l-1], previous)
+			}
+		}
+	}
+
+	for _, education := range EducationLevels { // everyone needs to be able to find some job
+		check(slices.ContainsFunc(c.Jobs, func(j IndustryJob) bool { return slices.Contains(j.EducationLevels, education) }), "no jobs for education level %s", education)
+	}
+
+	return errors.Join(errs...)
+}
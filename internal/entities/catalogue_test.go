@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestDefaultCatalogue(t *testing.T) {
+	c := DefaultCatalogue()
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Industries) != 11 || len(c.Jobs) != 20 {
+		t.Errorf("expected 11 industries and 20 jobs, got %d and %d", len(c.Industries), len(c.Jobs))
+	}
+}
+
+func TestCatalogueValidate(t *testing.T) {
+	tests := map[string]struct {
+		change func(c *Catalogue)
+		err    string
+	}{
+		"overlapping ranges": {func(c *Catalogue) { c.Jobs[0].SalaryRange[MidLevel] = [2]int{50000, 90000} }, "overlaps"},
+		"inverted range":     {func(c *Catalogue) { c.Jobs[0].SalaryRange[EntryLevel] = [2]int{60000, 40000} }, "not a positive min and max"},
+		"unknown career":     {func(c *Catalogue) { c.Jobs[0].SalaryRange[Retired] = [2]int{1, 2} }, "unknown career level"},
+		"missing career":     {func(c *Catalogue) { delete(c.Jobs[0].SalaryRange, SeniorLevel) }, "no salary range for Senior Level"},
+		"unknown education":  {func(c *Catalogue) { c.Jobs[0].EducationLevels = []EducationLevel{"Bootcamp"} }, "unknown education level"},
+		"no abundance":       {func(c *Catalogue) { c.Jobs[0].JobAbundance = 0 }, "abundance"},
+		"duplicate job":      {func(c *Catalogue) { c.Jobs = append(c.Jobs, c.Jobs[0]) }, "more than once"},
+		"job of unknown":     {func(c *Catalogue) { c.Jobs[0].Industry = "Mining" }, "in an unknown industry"},
+		"industry no jobs":   {func(c *Catalogue) { c.Industries = append(c.Industries, "Mining") }, "Mining has no jobs"},
+		"unknown zone":       {func(c *Catalogue) { c.Zones[Finance] = ResidentialUse }, "unknown zone"},
+		"zone for unknown":   {func(c *Catalogue) { c.Zones["Mining"] = IndustrialUse }, "zone for unknown industry Mining"},
+		"farms out of zone":  {func(c *Catalogue) { c.Zones[Agriculture] = IndustrialUse }, "must be in the agriculture zone"},
+		"shops without zone": {func(c *Catalogue) { delete(c.Zones, Retail) }, "Retail has no zone"},
+		"missing retail": {func(c *Catalogue) {
+			c.Industries = []Industry{Agriculture}
+		}, "Retail is missing"},
+	}
+
+	for name, test := range tests {
+		c := DefaultCatalogue()
+		test.change(c)
+		if err := c.Validate(); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected an error containing %q, got %v", name, test.err, err)
+		}
+	}
+}
+
+func TestCatalogueOverride(t *testing.T) {
+	base := DefaultCatalogue()
+	teacher := base.Jobs[3]
+	teacher.JobAbundance = 20
+	miner := IndustryJob{
+		Industry:        "Mining",
+		Job:             "Miner",
+		EducationLevels: []EducationLevel{Unqualified},
+		SalaryRange:     map[CareerLevel][2]int{EntryLevel: {50000, 60000}, MidLevel: {60000, 70000}, SeniorLevel: {70000, 80000}, ExecutiveLevel: {80000, 90000}},
+		JobAbundance:    3,
+	}
+
+	c := base.Override(&Catalogue{Industries: []Industry{"Mining", Retail}, Zones: map[Industry]LandUse{"Mining": IndustrialUse}, Jobs: []IndustryJob{teacher, miner}})
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Industries) != 12 || len(c.Jobs) != 21 {
+		t.Errorf("expected 12 industries and 21 jobs, got %d and %d", len(c.Industries), len(c.Jobs))
+	}
+	if c.Jobs[3].JobAbundance != 20 || base.Jobs[3].JobAbundance == 20 {
+		t.Errorf("teacher job was not overridden in a copy of the catalogue")
+	}
+	if c.Zones["Mining"] != IndustrialUse || c.Zones[Finance] != CommercialUse || base.Zones["Mining"] != NoUse {
+		t.Errorf("expected mining to be zoned industrial in a copy of the catalogue, got %v", c.Zones)
+	}
+}
+
+// TestRandomIndustryIn checks that industries are only drawn from the given zones
+func TestRandomIndustryIn(t *testing.T) {
+	c := DefaultCatalogue()
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 100 {
+		industry := c.RandomIndustryIn(rng, CommercialUse, IndustrialUse)
+		if use := c.LandUse(industry); use != CommercialUse && use != IndustrialUse {
+			t.Fatalf("expected an office or factory industry, got %s in the %s zone", industry, use)
+		}
+	}
+	if industry := c.RandomIndustryIn(rng, ResidentialUse); industry != "" {
+		t.Errorf("expected no industry in residential zones, got %s", industry)
+	}
+}
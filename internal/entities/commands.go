@@ -0,0 +1,185 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Command is a player action on the simulation, which is recorded in the journal so that it can be replayed
+type Command interface {
+	Type() string
+	Apply(sim *Simulation)
+}
+
+// PlaceRoadCommand builds or extends a road
+type PlaceRoadCommand struct {
+	Start, End Point
+	RoadType   RoadType
+}
+
+func (c PlaceRoadCommand) Type() string { return "PlaceRoad" }
+
+func (c PlaceRoadCommand) Apply(sim *Simulation) {
+	PlaceRoad(sim, c.Start, c.End, c.RoadType)
+}
+
+// PlaceLandUseCommand zones an area of land
+type PlaceLandUseCommand struct {
+	Start, End Point
+	Use        LandUse
+}
+
+func (c PlaceLandUseCommand) Type() string { return "PlaceLandUse" }
+
+func (c PlaceLandUseCommand) Apply(sim *Simulation) {
+	sim.Geography.PlaceLandUse(c.Start, c.End, c.Use)
+}
+
+// ToggleRoundaboutCommand turns an intersection into a roundabout, or back
+type ToggleRoundaboutCommand struct {
+	At Point
+}
+
+func (c ToggleRoundaboutCommand) Type() string { return "ToggleRoundabout" }
+
+func (c ToggleRoundaboutCommand) Apply(sim *Simulation) {
+	sim.Geography.ToggleRoundabout(c.At.X, c.At.Y)
+}
+
+// RegenerateMapCommand generates new terrain for the city
+type RegenerateMapCommand struct {
+	PeakProbability, RangeProbability, CliffProbability float64
+}
+
+func (c RegenerateMapCommand) Type() string { return "RegenerateMap" }
+
+func (c RegenerateMapCommand) Apply(sim *Simulation) {
+	sim.RegenerateMap(c.PeakProbability, c.RangeProbability, c.CliffProbability)
+}
+
+// NameCityCommand names the city
+type NameCityCommand struct {
+	Name string
+}
+
+func (c NameCityCommand) Type() string { return "NameCity" }
+
+func (c NameCityCommand) Apply(sim *Simulation) {
+	sim.CityName = c.Name
+}
+
+// SetTaxRatesCommand sets the tax rates, as percentages
+type SetTaxRatesCommand struct {
+	CorporateTaxRate, SalesTaxRate float64
+	IncomeTaxBrackets              []TaxBracket
+}
+
+func (c SetTaxRatesCommand) Type() string { return "SetTaxRates" }
+
+func (c SetTaxRatesCommand) Apply(sim *Simulation) {
+	sim.Government.CorporateTaxRate = c.CorporateTaxRate
+	sim.Government.SalesTaxRate = c.SalesTaxRate
+	sim.Government.IncomeTaxBrackets = slices.Clone(c.IncomeTaxBrackets)
+}
+
+// commandTypes creates an empty command of each type, for decoding journals
+var commandTypes = map[string]func() Command{
+	"PlaceRoad":        func() Command { return &PlaceRoadCommand{} },
+	"PlaceLandUse":     func() Command { return &PlaceLandUseCommand{} },
+	"ToggleRoundabout": func() Command { return &ToggleRoundaboutCommand{} },
+	"RegenerateMap":    func() Command { return &RegenerateMapCommand{} },
+	"NameCity":         func() Command { return &NameCityCommand{} },
+	"SetTaxRates":      func() Command { return &SetTaxRatesCommand{} },
+}
+
+// Execute applies a command to the simulation and records it in the journal
+func (s *Simulation) Execute(command Command) {
+	command.Apply(s)
+	s.journal.Entries = append(s.journal.Entries, JournalEntry{Date: s.Date, Command: command})
+}
+
+// Journal is the record of every command given to a simulation, which
+// replayed on a new simulation with the same seed reproduces it exactly
+type Journal struct {
+	Seed      uint64
+	Scenario  string // fingerprint of the scenario the city was started from, empty in older journals
+	Catalogue string // fingerprint of the catalogue the city was started with, empty in older journals
+	Entries   []JournalEntry
+}
+
+// CheckStart returns an error if the journal was recorded on a city started from a different scenario or
+// catalogue to the simulation's, as replaying it there wouldn't reproduce the original session
+func (j *Journal) CheckStart(sim *Simulation) error {
+	if j.Scenario != "" && sim.journal.Scenario != "" && j.Scenario != sim.journal.Scenario {
+		return fmt.Errorf("journal was recorded on a city started from a different scenario")
+	}
+	if j.Catalogue != "" && sim.journal.Catalogue != "" && j.Catalogue != sim.journal.Catalogue {
+		return fmt.Errorf("journal was recorded on a city with different industries and jobs")
+	}
+	return nil
+}
+
+// Fingerprint returns a short hash of v encoded as JSON, which tells apart journals of different starts
+func Fingerprint(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+}
+
+// JournalEntry is a command and the date it was given on
+type JournalEntry struct {
+	Date    time.Time
+	Command Command
+}
+
+type journalEntryJSON struct {
+	Date    time.Time
+	Type    string
+	Command json.RawMessage
+}
+
+func (e JournalEntry) MarshalJSON() ([]byte, error) {
+	command, err := json.Marshal(e.Command)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(journalEntryJSON{Date: e.Date, Type: e.Command.Type(), Command: command})
+}
+
+func (e *JournalEntry) UnmarshalJSON(data []byte) error {
+	entry := journalEntryJSON{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+
+	newCommand, exists := commandTypes[entry.Type]
+	if !exists {
+		return fmt.Errorf("unknown command type %q", entry.Type)
+	}
+	command := newCommand()
+	if err := json.Unmarshal(entry.Command, command); err != nil {
+		return err
+	}
+
+	e.Date, e.Command = entry.Date, command
+	return nil
+}
+
+// Journal returns the record of commands given to the simulation
+func (s *Simulation) Journal() *Journal {
+	return s.journal
+}
+
+// SetJournal replaces the record of commands given to the simulation, such as when it is loaded
+func (s *Simulation) SetJournal(journal *Journal) {
+	s.journal = journal
+}
+
+func (j *Journal) clone() *Journal {
+	return &Journal{Seed: j.Seed, Scenario: j.Scenario, Catalogue: j.Catalogue, Entries: slices.Clone(j.Entries)}
+}
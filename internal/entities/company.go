@@ -12,9 +12,23 @@ import (
 
 type Companies map[int]*Company
 
+const (
+	CashReserveMonths  = 3    // months of expenses a new company has in the bank
+	ShedStaffRunway    = 6    // months of losses a company's cash has to cover before it lays off staff
+	ShedStaffShare     = 0.25 // share of its staff a struggling company lays off each month
+	AssetMonths        = 6    // months of revenue a new company has invested in its premises and equipment
+	DepreciationRate   = 10.0 // percentage of a company's assets that wear out each year
+	BaseExpectedReturn = 10.0 // annual percentage return companies expect on new capital in a flat market
+	MaxExpansionRate   = 10.0 // percentage a company can grow its assets by in a year, on top of replacing wear
+	LoanSpread         = 2.0  // percentage points over the central bank's rate that companies borrow at
+	LoanTermMonths     = 60   // months over which company loans are repaid
+	MaxLeverage        = 0.5  // share of its assets a company can borrow against
+	DividendPayout     = 0.5  // share of its profit a profitable company pays out in dividends
+)
+
 // Add adds a new company
-func (c Companies) Add(company *Company) {
-	company.ID = Sim.GetNextID()
+func (c Companies) Add(sim *Simulation, company *Company) {
+	company.ID = sim.GetNextID()
 	c[company.ID] = company
 }
 
@@ -33,30 +47,54 @@ func (c Companies) GetIDs() []int {
 	return IDs
 }
 
-func (c Companies) PlaceRetail(newCompany *Company) {
-	site := Sim.Geography.GetPotentialSite(RetailUse)
-	if site == nil { // no suitable sites
-		return
+// Place adds a new company on a site in its industry's zone, returning false and leaving it out of
+// the city if there are no vacant sites
+func (c Companies) Place(sim *Simulation, newCompany *Company) bool {
+	if !c.moveIn(sim, newCompany) {
+		return false
 	}
+	c.Add(sim, newCompany)
+	return true
+}
 
-	Sim.Geography.tiles[site.X][site.Y].LandStatus = DevelopedStatus
+// Settle moves companies without premises onto vacant sites in their industry's zones, returning the
+// number that moved in
+func (c Companies) Settle(sim *Simulation) int {
+	settled := 0
+	for _, id := range c.GetIDs() {
+		company := c[id]
+		if company.Location != nil || !c.moveIn(sim, company) {
+			continue
+		}
+		settled++
+		sim.Events().Publish(CompanyMovedIn{Name: company.Name, Industry: company.Industry, Use: sim.Catalogue.LandUse(company.Industry)})
+	}
+	return settled
+}
 
-	newCompany.Location = site
-	newCompany.RoadDirection = Sim.Geography.getAccessRoad(site.X, site.Y)
-	c.Add(newCompany)
+// Unsited returns the number of companies without premises waiting for a site in each zone
+func (c Companies) Unsited(sim *Simulation) map[LandUse]int {
+	unsited := make(map[LandUse]int)
+	for company := range maps.Values(c) {
+		if company.Location == nil {
+			unsited[sim.Catalogue.LandUse(company.Industry)]++
+		}
+	}
+	return unsited
 }
 
-func (c Companies) PlaceAgriculture(newCompany *Company) {
-	site := Sim.Geography.GetPotentialSite(AgricultureUse)
+// moveIn develops a vacant site in the company's industry's zone as its premises
+func (c Companies) moveIn(sim *Simulation, company *Company) bool {
+	site := sim.Geography.GetPotentialSite(sim.rng, sim.Catalogue.LandUse(company.Industry))
 	if site == nil { // no suitable sites
-		return
+		return false
 	}
 
-	Sim.Geography.tiles[site.X][site.Y].LandStatus = DevelopedStatus
+	sim.Geography.tiles[site.X][site.Y].LandStatus = DevelopedStatus
 
-	newCompany.Location = site
-	newCompany.RoadDirection = Sim.Geography.getAccessRoad(site.X, site.Y)
-	c.Add(newCompany)
+	company.Location = site
+	company.RoadDirection = sim.Geography.getAccessRoad(site.X, site.Y)
+	return true
 }
 
 func (c Companies) GetLocationCompany(x, y int) *Company {
@@ -68,9 +106,54 @@ func (c Companies) GetLocationCompany(x, y int) *Company {
 	return nil
 }
 
+// ShedStaff lays off a share of a company's employees, the most recently hired first, returning the
+// number laid off
+func (c Companies) ShedStaff(sim *Simulation, companyID int) int {
+	company, ok := c[companyID]
+	if !ok || company.GetNumberOfEmployees() == 0 {
+		return 0
+	}
+
+	layoffs := int(math.Ceil(float64(company.GetNumberOfEmployees()) * ShedStaffShare))
+	employees := company.Employees[company.GetNumberOfEmployees()-layoffs:]
+	c.layOff(sim, company, employees)
+	sim.Events().Publish(StaffLaidOff{CompanyName: company.Name, Employees: layoffs, Remaining: company.GetNumberOfEmployees()})
+	return layoffs
+}
+
+// Close lays off all of an insolvent company's employees, frees up its site and removes it, returning
+// the number laid off
+func (c Companies) Close(sim *Simulation, companyID int) int {
+	company, ok := c[companyID]
+	if !ok {
+		return 0
+	}
+
+	layoffs := company.GetNumberOfEmployees()
+	c.layOff(sim, company, company.Employees)
+	sim.Bank.WriteOff(company.Debt) // the company's loans won't be repaid
+	if site := company.Location; site != nil && sim.Geography.BoundsCheck(site.X, site.Y) {
+		sim.Geography.tiles[site.X][site.Y].LandStatus = UndevelopedStatus
+	}
+	c.Remove(companyID)
+	sim.Events().Publish(CompanyClosed{Name: company.Name, Industry: company.Industry, Employees: layoffs})
+	return layoffs
+}
+
+// layOff removes employees from a company, including any that are no longer in the city
+func (c Companies) layOff(sim *Simulation, company *Company, employees []int) {
+	for _, employeeID := range slices.Clone(employees) {
+		if person := sim.People.GetPerson(employeeID); person != nil && person.EmployerID == company.ID {
+			c.RemoveEmployeeFromTheirCompany(person)
+		} else {
+			company.RemoveEmployee(employeeID)
+		}
+	}
+}
+
 // RemoveEmployeeFromTheirCompany removes a person from their company list of employees
 func (c Companies) RemoveEmployeeFromTheirCompany(person *Person) {
-	if company, ok := Sim.Companies[person.EmployerID]; ok {
+	if company, ok := c[person.EmployerID]; ok {
 		company.RemoveEmployee(person.ID)
 		person.EmployerID = 0
 	}
@@ -89,42 +172,65 @@ type Company struct {
 	JobOpenings      map[CareerLevel]int // Available job positions at each level
 	Employees        []int               // Employee IDs
 	RetailSales      float64
+	Cash             float64 // money in the bank, which pays for the company's losses
+	Debt             float64 // outstanding loans, repaid monthly
+	Assets           float64 // book value of the company's premises and equipment
+	RetainedEarnings float64 // profits kept in the company since it was founded
+	CapitalPerJob    float64 // assets needed to employ each member of staff
 	CorpTaxPayable   float64
 	SalesTaxPayable  float64
 	FixedCosts       float64
 	Payroll          float64
 
 	// Historical
-	LastRevenue, LastExpenses, LastProfit float64
+	LastRevenue, LastExpenses, LastProfit       float64
+	LastInterest, LastDividends, LastInvestment float64
 }
 
-// CalculateProfit computes monthly net profit
-func (c *Company) CalculateProfit(monthLength float64) float64 {
+// CalculateProfit computes monthly net profit, services the company's debt and pays its dividends
+func (c *Company) CalculateProfit(sim *Simulation, monthLength float64) float64 {
+	// debts are serviced whether or not the company is trading
+	c.LastInterest = c.Debt * c.BorrowingRate(sim) / 1200
+	repayment := c.Debt / LoanTermMonths
+	if c.Debt-repayment < 1 { // pay off what is left
+		repayment = c.Debt
+	}
+	c.Debt -= repayment
+	c.Cash -= repayment
+	c.LastDividends = 0
+
 	// if there are no employees, stop calculation and return 0 profits (because the company is inactive)
 	if c.GetNumberOfEmployees() == 0 {
-		c.LastProfit = 0
+		c.LastProfit = -c.LastInterest
+		c.Cash += c.LastProfit
+		c.RetainedEarnings += c.LastProfit
 		return c.LastProfit
 	}
 
 	// **Monthly Expense Growth**: Inflation applied proportionally
-	inflationMultiplier := 1.0 + (Sim.Market.InflationRate() / 2400) // Divided by 2400 for smoother monthly change
+	inflationMultiplier := 1.0 + (sim.Market.InflationRate() / 2400) // Divided by 2400 for smoother monthly change
 
 	// **Cost-cutting for struggling companies**: Reduces expenses if past profits were negative
 	if c.LastProfit < 0 {
 		inflationMultiplier *= 0.95 // Expenses grow slower for struggling businesses
 	}
-	c.FixedCosts *= inflationMultiplier // Adjust fixed costs with inflation
-	c.LastExpenses = c.FixedCosts + c.Payroll
-	c.Payroll = 0.0 // Reset payroll liabilites
+	c.FixedCosts *= inflationMultiplier       // Adjust fixed costs with inflation
+	c.LastExpenses = c.FixedCosts - c.Payroll // payroll is recorded as a liability, so it is negative
+	c.Payroll = 0.0                           // Reset payroll liabilites
+
+	// interest and the wear on the company's assets are costs of doing business
+	depreciation := c.Assets * DepreciationRate / 1200
+	c.Assets -= depreciation
+	c.LastExpenses += c.LastInterest + depreciation
 
 	if c.Industry == Retail { // For retail, revenue == sales
-		taxedAmount := math.Ceil(c.RetailSales * (Sim.Government.SalesTaxRate / 100)) // calculate sales tax
+		taxedAmount := math.Ceil(c.RetailSales * (sim.Government.SalesTaxRate / 100)) // calculate sales tax
 		c.LastRevenue = c.RetailSales
 		c.LastExpenses += taxedAmount
 		c.SalesTaxPayable += taxedAmount
 	} else {
 		// **Monthly Revenue Growth**: Adjusts based on market conditions for non-retail
-		lastMarketGrowthRate := utils.GetLastValue(Sim.Market.History.MarketGrowthRate)
+		lastMarketGrowthRate := utils.GetLastValue(sim.Market.History.MarketGrowthRate)
 		revenueMultiplier := 1.0 + (lastMarketGrowthRate / 1200) // Gradual revenue increase
 		if c.LastProfit > 0 {
 			revenueMultiplier += 0.002 // Small bonus growth for profitable companies
@@ -137,7 +243,7 @@ func (c *Company) CalculateProfit(monthLength float64) float64 {
 
 	// **Apply Corporate Tax**
 	if grossProfit > 0 {
-		taxedAmount := math.Ceil(grossProfit * (Sim.Government.CorporateTaxRate / 100.0)) // round to nearest dollar
+		taxedAmount := math.Ceil(grossProfit * (sim.Government.CorporateTaxRate / 100.0)) // round to nearest dollar
 		c.LastProfit = grossProfit - taxedAmount
 
 		// Store unpaid tax in liability account
@@ -151,9 +257,88 @@ func (c *Company) CalculateProfit(monthLength float64) float64 {
 		c.LastProfit = -c.LastRevenue * 0.25
 	}
 
+	// profitable companies with cash to spare pay out some of their profit to their owners
+	if c.LastProfit > 0 && c.Cash > c.cashReserve() {
+		c.LastDividends = min(c.LastProfit*DividendPayout, c.Cash-c.cashReserve())
+	}
+	c.Cash += c.LastProfit + depreciation - c.LastDividends // depreciation is a cost, but not one paid in cash
+	c.RetainedEarnings += c.LastProfit - c.LastDividends
 	return c.LastProfit
 }
 
+// cashReserve returns the cash the company keeps in the bank to pay for its expenses
+func (c *Company) cashReserve() float64 {
+	return c.LastExpenses * CashReserveMonths
+}
+
+// BorrowingRate returns the annual percentage interest rate the company borrows at
+func (c *Company) BorrowingRate(sim *Simulation) float64 {
+	return sim.Market.InterestRate() + LoanSpread
+}
+
+// ReturnOnAssets returns the annual percentage return the company makes on its assets, before interest
+func (c *Company) ReturnOnAssets() float64 {
+	if c.Assets <= 0 {
+		return 0
+	}
+	return (c.LastProfit + c.LastInterest) * 12 / c.Assets * 100
+}
+
+// ExpectedReturn returns the annual percentage return the company expects on new capital: what the
+// market's growth offers, but no more than it makes on the capital it already has
+func (c *Company) ExpectedReturn(sim *Simulation) float64 {
+	marketReturn := BaseExpectedReturn + utils.GetLastValue(sim.Market.History.MarketGrowthRate) + utils.GetLastValue(sim.Market.History.MarketSentiment)
+	return min(marketReturn, c.ReturnOnAssets())
+}
+
+// Invest replaces the company's worn out assets and expands them when the return it expects on new
+// capital beats the cost of borrowing, growing faster the wider the gap. It borrows for the
+// investment as far as its assets allow, paying for the rest from spare cash, and returns the amount
+// invested.
+func (c *Company) Invest(sim *Simulation) float64 {
+	c.LastInvestment = 0
+	spread := c.ExpectedReturn(sim) - c.BorrowingRate(sim)
+	if spread <= 0 || c.Runway() < ShedStaffRunway {
+		return 0
+	}
+
+	investment := c.Assets * (DepreciationRate + min(spread, MaxExpansionRate)) / 1200
+	borrowed := min(investment, max(c.Assets*MaxLeverage-c.Debt, 0))
+	fromCash := min(investment-borrowed, max(c.Cash-c.cashReserve(), 0))
+	c.Debt += borrowed
+	c.Cash -= fromCash
+	c.LastInvestment = borrowed + fromCash
+	c.Assets += c.LastInvestment
+	return c.LastInvestment
+}
+
+// Equity returns what the company is worth to its owners: its cash and assets, less its debt
+func (c *Company) Equity() float64 {
+	return c.Cash + c.Assets - c.Debt
+}
+
+// JobCapacity returns the number of staff the company's assets can employ
+func (c *Company) JobCapacity() int {
+	if c.CapitalPerJob <= 0 {
+		return c.GetNumberOfEmployees()
+	}
+	return int(c.Assets / c.CapitalPerJob)
+}
+
+// Runway returns the number of months the company's cash would pay for its losses, or infinity if it
+// is not making a loss
+func (c *Company) Runway() float64 {
+	if c.LastProfit >= 0 {
+		return math.Inf(1)
+	}
+	return max(c.Cash, 0) / -c.LastProfit
+}
+
+// IsInsolvent returns true if the company has run out of cash
+func (c *Company) IsInsolvent() bool {
+	return c.Cash < 0
+}
+
 // GetNumberOfJobOpenings returns the number of job openings
 func (c *Company) GetNumberOfJobOpenings() int {
 	openings := 0
@@ -184,10 +369,12 @@ func (c *Company) GetProductivity() float64 {
 }
 
 // GetEmployees returns a list of employees
-func (c *Company) GetEmployees() []*Person {
+func (c *Company) GetEmployees(people *People) []*Person {
 	employees := []*Person{}
 	for _, employeeID := range c.Employees {
-		employees = append(employees, Sim.People.People[employeeID])
+		if employee := people.GetPerson(employeeID); employee != nil {
+			employees = append(employees, employee)
+		}
 	}
 	return employees
 }
@@ -204,60 +391,38 @@ func (c *Company) RemoveEmployee(employeeID int) {
 	})
 }
 
-// DetermineJobOpenings calculates jobs available based on economic factors
-func (c *Company) DetermineJobOpenings() {
-	lastMarketSentiment := utils.GetLastValue(Sim.Market.History.MarketSentiment)
-	baseJobs := c.CompanySize.GetBaseJobs()
-
-	// Adjust based on economic conditions
-	marketMultiplier := 1.0
-
-	// Interest Rate Effect: High rates slow down hiring
-	if Sim.Market.InterestRate() > 5 {
-		marketMultiplier -= 0.3
-	}
-
-	// Inflation Effect: High inflation discourages hiring
-	if Sim.Market.InflationRate() > 6 {
-		marketMultiplier -= 0.2
+// DetermineJobOpenings opens jobs for the staff the company's assets can employ, so that hiring follows
+// investment, and stops hiring when the company is running out of cash
+func (c *Company) DetermineJobOpenings(sim *Simulation) {
+	for level := range c.JobOpenings {
+		c.JobOpenings[level] = 0
 	}
-
-	// Government Spending Effect: More spending stimulates job creation
-	if Sim.Government.GetGovernmentSpending() > 5 {
-		marketMultiplier += 0.2
+	vacancies := c.JobCapacity() - c.GetNumberOfEmployees()
+	if c.Runway() < ShedStaffRunway || vacancies <= 0 {
+		return
 	}
 
-	// Market Sentiment Effect: High confidence = More job openings
-	marketMultiplier += lastMarketSentiment * 0.1
-
-	// Adjust hiring based on profitability
-	if c.LastProfit < 0 {
-		marketMultiplier -= 0.5 // If losing money, reduce hiring
+	// spread the vacancies across career levels like the jobs of a company of its size
+	levels := c.CompanySize.GetBaseJobs(sim.rng)
+	total := 0
+	for _, jobs := range levels {
+		total += jobs
 	}
-
-	// Apply adjustments
-	for level, jobs := range baseJobs {
-		adjustedJobs := int(math.Round(float64(jobs) * marketMultiplier))
-		if openings, exists := c.JobOpenings[level]; exists && openings > 0 { // if job openings already exists, use that value
-			adjustedJobs = int(math.Round(float64(openings) * marketMultiplier))
-		}
-		if adjustedJobs < 1 {
-			adjustedJobs = 1 // Prevent zero or negative jobs
-		}
-		c.JobOpenings[level] = adjustedJobs
+	for level, jobs := range levels {
+		c.JobOpenings[level] = int(math.Round(float64(vacancies*jobs) / float64(total)))
 	}
 }
 
 // ReviseWages calculates jobs available based on economic factors
-func (c *Company) ReviseWages() {
-	if c.GetNumberOfEmployees() == 0 || Sim.Date.Before(c.NextWageRevision) { // run yearly, and don't run if no employees
+func (c *Company) ReviseWages(sim *Simulation) {
+	if c.GetNumberOfEmployees() == 0 || sim.Date.Before(c.NextWageRevision) { // run yearly, and don't run if no employees
 		return
 	}
-	c.NextWageRevision = Sim.Date.AddDate(1, 0, 0)
+	c.NextWageRevision = sim.Date.AddDate(1, 0, 0)
 
-	incrementRate := 2 * Sim.Market.InflationRate() / 100   // start at a base of 2x the inflation rate
-	if Sim.People.UnemploymentRate() > SevereUnemployment { // Adjust downward if there is severe unemployment
-		incrementRate *= 0.5 * (Sim.People.UnemploymentRate() - SevereUnemployment) / 100
+	incrementRate := 2 * sim.Market.InflationRate() / 100   // start at a base of 2x the inflation rate
+	if sim.People.UnemploymentRate() > SevereUnemployment { // Adjust downward if there is severe unemployment
+		incrementRate *= 0.5 * (sim.People.UnemploymentRate() - SevereUnemployment) / 100
 	}
 	if c.LastProfit < 0 { // Adjust downward if the company is unprofitable.
 		incrementRate *= 0.5
@@ -265,16 +430,16 @@ func (c *Company) ReviseWages() {
 
 	incrementRate = utils.Clamp(incrementRate, 0.005, 0.05) // increment rate clamped between 0.5% and 5%
 
-	for _, employee := range c.GetEmployees() {
+	for _, employee := range c.GetEmployees(sim.People) {
 		wageIncrease := float64(employee.AnnualIncome) * incrementRate
 		employee.AnnualIncome += int(wageIncrease)
 	}
 
-	fmt.Printf("[ Wage ] %s has increased the wages of its %d employees by %.2f%%\n", c.Name, c.GetNumberOfEmployees(), incrementRate*100)
+	sim.Events().Publish(WagesRevised{CompanyName: c.Name, Employees: c.GetNumberOfEmployees(), Increment: incrementRate * 100})
 }
 
-func (c *Company) CompanyAge() int {
-	duration := Sim.Date.Sub(c.FoundingDate)
+func (c *Company) CompanyAge(now time.Time) int {
+	duration := now.Sub(c.FoundingDate)
 	return int(duration.Hours() / HoursPerYear)
 }
 
@@ -282,6 +447,6 @@ func (c *Company) GetID() int {
 	return c.ID
 }
 
-func (c *Company) GetStats() string {
+func (c *Company) GetStats(sim *Simulation) string {
 	return fmt.Sprintf("%5d %-25s %-5s %02d/%02d %4d %-18s %-10s", c.ID, c.Name, c.CompanySize, c.GetNumberOfEmployees(), c.GetNumberOfJobOpenings(), c.FoundingDate.Year(), c.Industry, utils.FormatCurrency(c.LastProfit, "$"))
 }
@@ -1,49 +1,53 @@
 package entities_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/janithl/citylyf/internal/economy"
 	"github.com/janithl/citylyf/internal/entities"
 	"github.com/janithl/citylyf/internal/people"
+	"github.com/janithl/citylyf/internal/scheduler"
 )
 
 func TestReviseWages(t *testing.T) {
-	entities.Sim = entities.NewSimulation(2020, 1e6)
-	entities.Sim.SimulationSpeed = entities.Fast
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	sim.SimulationSpeed = entities.Fast
 
 	employment := economy.Employment{CompanyService: &economy.CompanyService{}}
 	calculationService := economy.NewCalculationService(employment.CompanyService)
-	industry := entities.GetRandomIndustry()
+	industry := sim.Catalogue.RandomIndustry(sim.Rand())
 
-	newCompany := employment.CompanyService.GenerateRandomCompany(entities.Large, industry)
-	entities.Sim.Companies.Add(newCompany)
+	newCompany := employment.CompanyService.GenerateRandomCompany(sim, entities.Large, industry)
+	sim.Companies.Add(sim, newCompany)
 
 	for range 10 {
-		household := people.CreateHousehold()
-		entities.Sim.People.Households[household.ID] = household
-		for _, person := range household.GetMembers() {
-			if person.IsEmployable() {
+		household := people.CreateHousehold(sim)
+		sim.People.Households[household.ID] = household
+		for _, person := range household.GetMembers(sim.People) {
+			if person.IsEmployable(sim.Date) {
 				newCompany.AddEmployee(person.ID)
 				person.EmployerID = newCompany.ID
 			}
 		}
 	}
 
-	initialAvgWage := entities.Sim.People.AverageWage()
+	systems := scheduler.New()
+	systems.Register(scheduler.NewFunc("employment", employment.AssignJobs), scheduler.Daily, 30)
+	systems.Register(scheduler.NewFunc("lifecycle", people.SimulateLifecycle), scheduler.Daily, 50)
+	systems.Register(scheduler.NewFunc("interest-rate", func(sim *entities.Simulation) { sim.Market.ReviseInterestRate(sim) }), scheduler.Quarterly, 60)
+	systems.Register(scheduler.NewFunc("economy", calculationService.CalculateEconomy), scheduler.Monthly, 70)
+
+	initialAvgWage := sim.People.AverageWage()
 	for range 366 * 5 {
-		entities.Sim.Tick(func() {
-			employment.AssignJobs()
-			people.SimulateLifecycle()
-			entities.Sim.Market.ReviseInterestRate()
-			calculationService.CalculateEconomy()
-		})
+		sim.Tick(func() { systems.Update(sim) })
 		if initialAvgWage == 0 {
-			initialAvgWage = entities.Sim.People.AverageWage()
+			initialAvgWage = sim.People.AverageWage()
 		}
 	}
 
-	finalAvgWage := entities.Sim.People.AverageWage()
+	finalAvgWage := sim.People.AverageWage()
 	expectedMaxWageGrowth := 1.28  // %5 compounding over 5 years
 	expectedMinWageGrowth := 1.025 // %0.5 compounding over 5 years
 	actualWageGrowth := finalAvgWage / initialAvgWage
@@ -54,3 +58,148 @@ func TestReviseWages(t *testing.T) {
 		t.Errorf(`ReviseWages(): Expected more than %.2f growth over 5 years at min 0.5%% annually, got %.2f\n`, expectedMinWageGrowth, actualWageGrowth)
 	}
 }
+
+// TestCompanyClosure checks that a company running out of cash lays off staff, and that one that has
+// run out lays off everyone, frees up its site and closes
+func TestCompanyClosure(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	companyService := &economy.CompanyService{}
+	entities.PlaceRoad(sim, entities.Point{X: 10, Y: 20}, entities.Point{X: 50, Y: 20}, entities.Chipseal)
+	sim.Geography.PlaceLandUse(entities.Point{X: 10, Y: 19}, entities.Point{X: 50, Y: 21}, entities.RetailUse)
+
+	company := companyService.GenerateRandomCompany(sim, entities.SME, entities.Retail)
+	sim.Companies.Place(sim, company)
+	if company.Location == nil {
+		t.Fatal("expected the company to be given a site")
+	}
+	employees := []*entities.Person{}
+	for len(employees) < 8 {
+		household := people.CreateHousehold(sim)
+		sim.People.Households[household.ID] = household
+		for _, person := range household.GetMembers(sim.People) {
+			company.AddEmployee(person.ID)
+			person.EmployerID = company.ID
+			employees = append(employees, person)
+		}
+	}
+
+	closed := []entities.CompanyClosed{}
+	entities.SubscribeTo(sim.Events(), func(e entities.CompanyClosed) { closed = append(closed, e) })
+
+	company.LastProfit, company.Cash = -1000, 5000
+	if runway := company.Runway(); runway != 5 {
+		t.Errorf("expected 5 months of runway, got %.1f", runway)
+	}
+	staff := company.GetNumberOfEmployees()
+	if laidOff := sim.Companies.ShedStaff(sim, company.ID); laidOff != (staff+3)/4 || company.GetNumberOfEmployees() != staff-laidOff {
+		t.Errorf("expected a quarter of %d employees to be laid off, %d were", staff, laidOff)
+	}
+	if last := employees[len(employees)-1]; last.IsEmployed() {
+		t.Error("expected the most recent hires to be laid off first")
+	}
+
+	company.Cash = -1
+	site := *company.Location
+	if laidOff := sim.Companies.Close(sim, company.ID); laidOff == 0 || !company.IsInsolvent() {
+		t.Errorf("expected the insolvent company's remaining employees to be laid off, got %d", laidOff)
+	}
+	for _, person := range employees {
+		if person.IsEmployed() {
+			t.Errorf("expected %s to be laid off", person.FirstName)
+		}
+	}
+	if _, exists := sim.Companies[company.ID]; exists || len(closed) != 1 {
+		t.Error("expected the company to be closed")
+	}
+	if tile := sim.Geography.GetTiles()[site.X][site.Y]; tile.LandStatus != entities.UndevelopedStatus || tile.LandUse != entities.RetailUse {
+		t.Errorf("expected the company's site to be free for another shop, got %v", tile)
+	}
+}
+
+// TestCompanyPlacement checks that companies are only sited in their industry's zone, and that
+// companies without premises move in once land is zoned for them
+func TestCompanyPlacement(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	companyService := &economy.CompanyService{}
+	entities.PlaceRoad(sim, entities.Point{X: 10, Y: 20}, entities.Point{X: 50, Y: 20}, entities.Chipseal)
+	sim.Geography.PlaceLandUse(entities.Point{X: 10, Y: 19}, entities.Point{X: 50, Y: 19}, entities.CommercialUse)
+
+	bank := companyService.GenerateRandomCompany(sim, entities.SME, entities.Finance)
+	if !sim.Companies.Place(sim, bank) {
+		t.Fatal("expected the bank to be given a site in the commercial zone")
+	}
+	if tile := sim.Geography.GetTiles()[bank.Location.X][bank.Location.Y]; tile.LandUse != entities.CommercialUse || tile.LandStatus != entities.DevelopedStatus {
+		t.Errorf("expected the bank to develop a commercial tile, got %s %s", tile.LandStatus, tile.LandUse)
+	}
+
+	plant := companyService.GenerateRandomCompany(sim, entities.SME, entities.Energy)
+	if sim.Companies.Place(sim, plant) || plant.Location != nil || len(sim.Companies) != 1 {
+		t.Fatal("expected the power plant not to be founded without an industrial zone")
+	}
+
+	sim.Companies.Add(sim, plant)
+	if unsited := sim.Companies.Unsited(sim); unsited[entities.IndustrialUse] != 1 || unsited[entities.CommercialUse] != 0 {
+		t.Errorf("expected one company waiting for an industrial site, got %v", unsited)
+	}
+	sim.Geography.PlaceLandUse(entities.Point{X: 10, Y: 21}, entities.Point{X: 50, Y: 21}, entities.IndustrialUse)
+	if settled := sim.Companies.Settle(sim); settled != 1 || plant.Location == nil {
+		t.Fatalf("expected the power plant to move into the industrial zone, %d settled", settled)
+	}
+	if company := sim.Companies.GetLocationCompany(plant.Location.X, plant.Location.Y); company != plant || plant.Location.Y != 21 {
+		t.Errorf("expected the power plant at its site in the industrial zone, got %v", plant.Location)
+	}
+}
+
+// TestCompanyInvestment checks that a profitable company borrows to invest when the return beats the
+// cost of borrowing, hires for what it invests in, services its debt and pays dividends, and that it
+// stops investing when rates rise
+func TestCompanyInvestment(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	sim.Market.History.InterestRate = []float64{2}
+	sim.Market.History.MarketGrowthRate = []float64{2}
+	company := (&economy.CompanyService{}).GenerateRandomCompany(sim, entities.SME, entities.Technology)
+	sim.Companies.Add(sim, company)
+	if company.Assets <= 0 || company.JobCapacity() < int(entities.SME.GetAverageJobs()) || company.GetNumberOfJobOpenings() == 0 {
+		t.Fatalf("expected a new company to have the assets and openings for its jobs, got %.0f and %d", company.Assets, company.GetNumberOfJobOpenings())
+	}
+
+	company.LastProfit = company.Assets * 0.2 / 12 // a 20% return on its assets
+	assets, capacity := company.Assets, company.JobCapacity()
+	investment := company.Invest(sim)
+	if investment <= 0 || company.Debt != investment || company.Assets != assets+investment {
+		t.Fatalf("expected the company to borrow to invest, invested %.0f and owes %.0f", investment, company.Debt)
+	}
+	for range 12 {
+		company.Invest(sim)
+	}
+	if company.JobCapacity() <= capacity {
+		t.Errorf("expected investment to make room for more staff than %d, got %d", capacity, company.JobCapacity())
+	}
+	company.DetermineJobOpenings(sim)
+	if openings := company.GetNumberOfJobOpenings(); openings < company.JobCapacity()-1 || openings > company.JobCapacity()+2 {
+		t.Errorf("expected openings for the %d staff the company can employ, got %d", company.JobCapacity(), openings)
+	}
+
+	// the company starts trading, paying interest on its debt and paying out some of its profit
+	company.AddEmployee(1)
+	company.Cash = company.FixedCosts * 10
+	debt, equity := company.Debt, company.Equity()
+	profit := company.CalculateProfit(sim, 30)
+	if interest := debt * company.BorrowingRate(sim) / 1200; company.LastInterest != interest || company.Debt >= debt {
+		t.Errorf("expected %.0f interest and a repayment on %.0f of debt, got %.0f and %.0f owed", interest, debt, company.LastInterest, company.Debt)
+	}
+	if profit <= 0 || company.LastDividends != profit*entities.DividendPayout || company.RetainedEarnings != profit-company.LastDividends {
+		t.Errorf("expected half of the profit of %.0f to be paid in dividends, got %.0f", profit, company.LastDividends)
+	}
+	if retained := company.Equity() - equity; math.Abs(retained-company.RetainedEarnings) > 1 {
+		t.Errorf("expected the company's equity to grow by its retained earnings of %.0f, got %.0f", company.RetainedEarnings, retained)
+	}
+
+	sim.Market.History.InterestRate = []float64{15}
+	if investment := company.Invest(sim); investment != 0 {
+		t.Errorf("expected no investment when borrowing costs more than the return, got %.0f", investment)
+	}
+}
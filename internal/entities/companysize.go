@@ -1,6 +1,9 @@
 package entities
 
-import "math/rand/v2"
+import (
+	"math/rand/v2"
+	"slices"
+)
 
 type CompanySize string
 
@@ -10,45 +13,61 @@ const (
 	Large CompanySize = "Large"
 )
 
-func (r CompanySize) GetBaseRevenue() float64 {
+func (r CompanySize) GetBaseRevenue(rng *rand.Rand) float64 {
 	if r == Micro {
-		return 800_000 + rand.NormFloat64()*400_000 // 400K - 1.2M for micro businesses
+		return 800_000 + rng.NormFloat64()*400_000 // 400K - 1.2M for micro businesses
 	}
 	if r == SME {
-		return 3_000_000 + rand.NormFloat64()*1_000_000 // 2M - 4M for SME businesses
+		return 3_000_000 + rng.NormFloat64()*1_000_000 // 2M - 4M for SME businesses
 	}
-	return 7_500_000 + rand.NormFloat64()*2_500_000 // 5M - 10M for large businesses
+	return 7_500_000 + rng.NormFloat64()*2_500_000 // 5M - 10M for large businesses
 }
 
-func (r CompanySize) GetBaseJobs() map[CareerLevel]int {
+func (r CompanySize) GetBaseJobs(rng *rand.Rand) map[CareerLevel]int {
 	if r == Micro { // Micro companies have < 15 jobs
 		return map[CareerLevel]int{
-			EntryLevel:     rand.IntN(3) + 3, // 2-5 jobs
-			MidLevel:       rand.IntN(3) + 1, // 2-3 jobs
-			SeniorLevel:    rand.IntN(2),     // 0-1 jobs
-			ExecutiveLevel: 0,                // 0 jobs
+			EntryLevel:     rng.IntN(3) + 3, // 2-5 jobs
+			MidLevel:       rng.IntN(3) + 1, // 2-3 jobs
+			SeniorLevel:    rng.IntN(2),     // 0-1 jobs
+			ExecutiveLevel: 0,               // 0 jobs
 		}
 	}
 	if r == SME { // SME companies have < 50 jobs
 		return map[CareerLevel]int{
-			EntryLevel:     rand.IntN(11) + 10, // 10-20 jobs
-			MidLevel:       rand.IntN(11) + 5,  // 5-15 jobs
-			SeniorLevel:    rand.IntN(5) + 4,   // 4-8 jobs
-			ExecutiveLevel: rand.IntN(2) + 1,   // 1-2 jobs
+			EntryLevel:     rng.IntN(11) + 10, // 10-20 jobs
+			MidLevel:       rng.IntN(11) + 5,  // 5-15 jobs
+			SeniorLevel:    rng.IntN(5) + 4,   // 4-8 jobs
+			ExecutiveLevel: rng.IntN(2) + 1,   // 1-2 jobs
 		}
 	} // Large companies have <100 jobs
 	return map[CareerLevel]int{
-		EntryLevel:     rand.IntN(31) + 15, // 15-45 jobs
-		MidLevel:       rand.IntN(15) + 11, // 11-25 jobs
-		SeniorLevel:    rand.IntN(10) + 3,  // 3-12 jobs
-		ExecutiveLevel: rand.IntN(3) + 2,   // 2-4 jobs
+		EntryLevel:     rng.IntN(31) + 15, // 15-45 jobs
+		MidLevel:       rng.IntN(15) + 11, // 11-25 jobs
+		SeniorLevel:    rng.IntN(10) + 3,  // 3-12 jobs
+		ExecutiveLevel: rng.IntN(3) + 2,   // 2-4 jobs
 	}
 }
 
+// GetAverageJobs returns the number of jobs a company of this size has on average
+func (r CompanySize) GetAverageJobs() float64 {
+	if r == Micro {
+		return 6.5
+	}
+	if r == SME {
+		return 32.5
+	}
+	return 58.5
+}
+
 var companysizes = []CompanySize{
 	Micro, SME, Large,
 }
 
-func GetRandomCompanySize() CompanySize {
-	return companysizes[rand.IntN(len(companysizes))]
+// IsValid returns true if the company size is known
+func (r CompanySize) IsValid() bool {
+	return slices.Contains(companysizes, r)
+}
+
+func GetRandomCompanySize(rng *rand.Rand) CompanySize {
+	return companysizes[rng.IntN(len(companysizes))]
 }
@@ -9,3 +9,5 @@ const (
 	University  EducationLevel = "University"
 	Postgrad    EducationLevel = "Postgrad"
 )
+
+var EducationLevels = []EducationLevel{Unqualified, HighSchool, University, Postgrad}
@@ -1,13 +1,15 @@
 package entities_test
 
 import (
+	"math/rand/v2"
 	"testing"
 
 	"github.com/janithl/citylyf/internal/entities"
 )
 
 func TestGetPlaceName(t *testing.T) {
-	ns := entities.NewNameService()
+	t.Parallel()
+	ns := entities.NewNameService(rand.New(rand.NewPCG(1, 2)))
 	place := ns.GetPlaceName()
 	if place == "" {
 		t.Errorf(`GetPlaceName() = %q, got "", wanted a non-empty string, error`, place)
@@ -15,9 +17,28 @@ func TestGetPlaceName(t *testing.T) {
 }
 
 func TestGetCompanyName(t *testing.T) {
-	ns := entities.NewNameService()
+	t.Parallel()
+	ns := entities.NewNameService(rand.New(rand.NewPCG(1, 2)))
 	company := ns.GetCompanyName()
 	if company == "" {
 		t.Errorf(`GetCompanyName() = %q, got "", wanted a non-empty string, error`, company)
 	}
 }
+
+// TestSimulationSpeeds checks that days are ticked by at each speed, and not at all when paused or at
+// Ultra speed, which the game loop runs flat out instead
+func TestSimulationSpeeds(t *testing.T) {
+	t.Parallel()
+	tests := map[entities.SimulationSpeed]int{entities.Pause: 0, entities.Slow: 1, entities.Mid: 4, entities.Fast: 16, entities.Ultra: 0}
+	for speed, expected := range tests {
+		sim := entities.NewSimulation(2020, 1e6, 1)
+		sim.SimulationSpeed = speed
+		days := 0
+		for range 16 {
+			sim.Tick(func() { days++ })
+		}
+		if days != expected || sim.IsRunningFlatOut() != (speed == entities.Ultra) {
+			t.Errorf("expected %d days in 16 ticks at speed %d, got %d", expected, speed, days)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package entities
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/janithl/citylyf/internal/utils"
+)
+
+// Event is something noteworthy that happened in the simulation
+type Event interface {
+	String() string
+}
+
+// EventBus delivers simulation events to its subscribers
+type EventBus struct {
+	mutex       sync.Mutex
+	nextID      int
+	subscribers map[int]func(Event)
+}
+
+// NewEventBus returns an event bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[int]func(Event))}
+}
+
+// Subscribe calls handler for every event published on the bus, and returns
+// a function that removes the subscription. Handlers are called from the
+// simulation goroutine while the simulation is locked, so they should be quick
+// and must not lock the simulation themselves.
+func (b *EventBus) Subscribe(handler func(Event)) func() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = handler
+	return func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// Publish sends an event to every subscriber, in the order they subscribed
+func (b *EventBus) Publish(event Event) {
+	b.mutex.Lock()
+	handlers := make([]func(Event), 0, len(b.subscribers))
+	for id := range b.nextID {
+		if handler, ok := b.subscribers[id]; ok {
+			handlers = append(handlers, handler)
+		}
+	}
+	b.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// SubscribeTo calls handler for every event of type T published on the bus
+func SubscribeTo[T Event](b *EventBus, handler func(T)) func() {
+	return b.Subscribe(func(event Event) {
+		if e, ok := event.(T); ok {
+			handler(e)
+		}
+	})
+}
+
+// CompanyFounded is published when a new company is set up
+type CompanyFounded struct {
+	Name     string
+	Industry Industry
+	Growth   bool // founded because of economic growth
+}
+
+func (e CompanyFounded) String() string {
+	if e.Growth {
+		return fmt.Sprintf("[ Econ ] Growth! %s (%s) founded!", e.Name, e.Industry)
+	}
+	return fmt.Sprintf("[ Econ ] %s (%s) founded!", e.Name, e.Industry)
+}
+
+// LoanIssued is published when the bank lends to a household
+type LoanIssued struct {
+	FamilyName   string
+	Kind         LoanKind
+	Amount, Rate float64
+}
+
+func (e LoanIssued) String() string {
+	return fmt.Sprintf("[ Bank ] %s family took out a %s of %s at %.2f%%", e.FamilyName, e.Kind, utils.FormatCurrency(e.Amount, "$"), e.Rate)
+}
+
+// LoanDefaulted is published when the bank writes off a loan that won't be repaid
+type LoanDefaulted struct {
+	FamilyName string // empty if the household has left the city
+	Kind       LoanKind
+	Balance    float64
+}
+
+func (e LoanDefaulted) String() string {
+	if e.FamilyName == "" {
+		return fmt.Sprintf("[ Bank ] A %s of %s was written off after the borrowers left the city", e.Kind, utils.FormatCurrency(e.Balance, "$"))
+	}
+	return fmt.Sprintf("[ Bank ] %s family defaulted on a %s, %s was written off", e.FamilyName, e.Kind, utils.FormatCurrency(e.Balance, "$"))
+}
+
+// CompanyMovedIn is published when a company without premises moves onto a site
+type CompanyMovedIn struct {
+	Name     string
+	Industry Industry
+	Use      LandUse
+}
+
+func (e CompanyMovedIn) String() string {
+	return fmt.Sprintf("[ Econ ] %s (%s) has moved into %s premises", e.Name, e.Industry, e.Use)
+}
+
+// CompanyClosed is published when a company runs out of cash and closes down
+type CompanyClosed struct {
+	Name      string
+	Industry  Industry
+	Employees int // number of employees laid off
+}
+
+func (e CompanyClosed) String() string {
+	return fmt.Sprintf("[ Econ ] %s (%s) has gone bust, laying off %d employees", e.Name, e.Industry, e.Employees)
+}
+
+// StaffLaidOff is published when a company that is running out of cash lays off some of its staff
+type StaffLaidOff struct {
+	CompanyName          string
+	Employees, Remaining int
+}
+
+func (e StaffLaidOff) String() string {
+	return fmt.Sprintf("[  Job ] %s is running out of cash and has laid off %d employees, %d remain", e.CompanyName, e.Employees, e.Remaining)
+}
+
+// JobAccepted is published when a person takes up a job
+type JobAccepted struct {
+	FirstName, FamilyName string
+	Occupation            Job
+	CompanyID             int
+	JobsRemaining         int
+}
+
+func (e JobAccepted) String() string {
+	return fmt.Sprintf("[  Job ] %s %s has accepted a job as %s, %d jobs remain", e.FirstName, e.FamilyName, e.Occupation, e.JobsRemaining)
+}
+
+// PersonRetired is published when a person retires
+type PersonRetired struct {
+	FirstName, FamilyName string
+	Age                   int
+}
+
+func (e PersonRetired) String() string {
+	return fmt.Sprintf("[  Job ] %s %s (%d) has retired", e.FirstName, e.FamilyName, e.Age)
+}
+
+// WagesRevised is published when a company revises the wages of its employees
+type WagesRevised struct {
+	CompanyName string
+	Employees   int
+	Increment   float64 // percentage increase
+}
+
+func (e WagesRevised) String() string {
+	return fmt.Sprintf("[ Wage ] %s has increased the wages of its %d employees by %.2f%%", e.CompanyName, e.Employees, e.Increment)
+}
+
+// Marriage is published when two people get married
+type Marriage struct {
+	FirstName1, FamilyName1 string
+	Age1                    int
+	FirstName2, FamilyName2 string
+	Age2                    int
+}
+
+func (e Marriage) String() string {
+	return fmt.Sprintf("[ Weds ] Wedding bells as %s %s (%d) marries %s %s (%d)!", e.FirstName1, e.FamilyName1, e.Age1,
+		e.FirstName2, e.FamilyName2, e.Age2)
+}
+
+// JoinedHousehold is published when a person moves in with another family
+type JoinedHousehold struct {
+	FirstName, HouseholdName string
+	Newlywed                 bool
+}
+
+func (e JoinedHousehold) String() string {
+	if e.Newlywed {
+		return fmt.Sprintf("[ Weds ] %s moves in with the %s family", e.FirstName, e.HouseholdName)
+	}
+	return fmt.Sprintf("[ Move ] %s moves in with the %s family", e.FirstName, e.HouseholdName)
+}
+
+// HouseholdsCombined is published when two households become one
+type HouseholdsCombined struct {
+	HouseholdName, CombinedName string
+}
+
+func (e HouseholdsCombined) String() string {
+	return fmt.Sprintf("[ Weds ] %s and %s families combine", e.HouseholdName, e.CombinedName)
+}
+
+// Birth is published when a baby is born
+type Birth struct {
+	FirstName, FamilyName string
+}
+
+func (e Birth) String() string {
+	return fmt.Sprintf("[ Baby ] %s %s has been born!", e.FirstName, e.FamilyName)
+}
+
+// HouseholdMovedIn is published when a household moves into a house
+type HouseholdMovedIn struct {
+	HouseholdName string
+	HouseID       int
+	FreeHouses    int
+}
+
+func (e HouseholdMovedIn) String() string {
+	return fmt.Sprintf("[ Move ] %s family has moved into house #%d, %d houses remain", e.HouseholdName, e.HouseID, e.FreeHouses)
+}
+
+// LeftHome is published when an adult moves out of their family home into a house of their own
+type LeftHome struct {
+	FirstName, FamilyName string
+	Age                   int
+	HouseID               int
+	FreeHouses            int
+}
+
+func (e LeftHome) String() string {
+	return fmt.Sprintf("[ Move ] %s %s (%d) has moved into house #%d, %d houses remain", e.FirstName, e.FamilyName, e.Age, e.HouseID, e.FreeHouses)
+}
+
+// HouseholdMovedOut is published when a household leaves the city
+type HouseholdMovedOut struct {
+	HouseholdName string
+	HouseID       int // zero if the household never found a house
+	FreeHouses    int
+}
+
+func (e HouseholdMovedOut) String() string {
+	if e.HouseID == 0 {
+		return fmt.Sprintf("[ Move ] The newlywed %s family has been unable to find housing, and has moved out of the city", e.HouseholdName)
+	}
+	return fmt.Sprintf("[ Move ] %s family has moved out of house #%d and the city, %d houses remain", e.HouseholdName, e.HouseID, e.FreeHouses)
+}
+
+// HouseSold is published when a household buys a house, or sells the one it owns as it moves out
+type HouseSold struct {
+	FamilyName string // empty if the household has no members left
+	HouseID    int
+	Price      int
+	Buyer      Owner
+}
+
+func (e HouseSold) String() string {
+	price := utils.FormatCurrency(float64(e.Price), "$")
+	if e.Buyer == OwnerHousehold {
+		return fmt.Sprintf("[ Home ] %s family has bought house #%d for %s", e.FamilyName, e.HouseID, price)
+	}
+	if e.FamilyName == "" {
+		return fmt.Sprintf("[ Home ] House #%d has been sold to the %s for %s", e.HouseID, e.Buyer, price)
+	}
+	return fmt.Sprintf("[ Home ] %s family has sold house #%d to the %s for %s", e.FamilyName, e.HouseID, e.Buyer, price)
+}
+
+// RateRevised is published when the central bank revises the interest rate
+type RateRevised struct {
+	AverageInflation float64
+	Change, NewRate  float64
+	NextRevision     time.Time
+}
+
+func (e RateRevised) String() string {
+	var change string
+	if e.Change > 0 {
+		change = fmt.Sprintf("above target range. Interest rate raised by %.2f%% to", e.Change)
+	} else if e.Change < 0 {
+		change = fmt.Sprintf("within or below target range. Interest rate lowered by %.2f%% to", e.Change)
+	} else {
+		change = "within the target range. Interest rates held steady at"
+	}
+	return fmt.Sprintf("[ Rate ] Avg. inflation at %.2f%%, %s %.2f%%. Next rates revision on %s", e.AverageInflation, change,
+		e.NewRate, e.NextRevision.Format("2006-01-02"))
+}
+
+// TaxesCollected is published when the government collects its annual taxes
+type TaxesCollected struct {
+	Year                       int
+	Personal, Sales, Corporate int
+	CapEx, OpEx, Reserves      int
+}
+
+func (e TaxesCollected) String() string {
+	return fmt.Sprintf("[  Tax ] %d: Collected $%d in personal income taxes, $%d in sales taxes, and $%d corporate taxes. "+
+		"CapEx: $%d, OpEx: $%d, Total Government Reserves: $%d", e.Year, e.Personal, e.Sales, e.Corporate, e.CapEx, e.OpEx, e.Reserves)
+}
+
+// RoadOpened is published when a new road is built
+type RoadOpened struct {
+	Name string
+	Type RoadType
+}
+
+func (e RoadOpened) String() string {
+	return fmt.Sprintf("[ Road ] %s opened!", e.Name)
+}
+
+// RoadExtended is published when an existing road is extended
+type RoadExtended struct {
+	Name string
+	Type RoadType
+}
+
+func (e RoadExtended) String() string {
+	return fmt.Sprintf("[ Road ] %s extended!", e.Name)
+}
+
+// DemandCalculated is published when housing and retail demand are recalculated
+type DemandCalculated struct {
+	HousingDemand, RetailDemand float64
+}
+
+func (e DemandCalculated) String() string {
+	return fmt.Sprintf("[ Econ ] Housing demand is at %.2f and Retail demand is at %.2f", e.HousingDemand, e.RetailDemand)
+}
+
+// EconomyCalculated is published after the monthly economic calculations
+type EconomyCalculated struct {
+	Stats           Stats
+	NextCalculation time.Time
+}
+
+func (e EconomyCalculated) String() string {
+	return fmt.Sprintf("[ Econ ] %s | Next calculation on %s", e.Stats, e.NextCalculation.Format("2006-01-02"))
+}
+
+// GoalDecided is published when a scenario goal is passed or failed
+type GoalDecided struct {
+	Name   string
+	Status GoalStatus
+}
+
+func (e GoalDecided) String() string {
+	return fmt.Sprintf("[ Goal ] %s: %s", e.Name, e.Status)
+}
+
+// ScenarioEnded is published when every goal of a scenario has been passed, or one has been failed
+type ScenarioEnded struct {
+	Won bool
+}
+
+func (e ScenarioEnded) String() string {
+	if e.Won {
+		return "[ Goal ] Every goal has been met, the scenario is won!"
+	}
+	return "[ Goal ] A goal has been failed, the scenario is lost"
+}
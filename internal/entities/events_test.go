@@ -0,0 +1,41 @@
+package entities
+
+import "testing"
+
+func TestEventBus(t *testing.T) {
+	t.Parallel()
+
+	bus := NewEventBus()
+	var all []Event
+	var roads []RoadOpened
+	unsubscribe := bus.Subscribe(func(e Event) { all = append(all, e) })
+	SubscribeTo(bus, func(e RoadOpened) { roads = append(roads, e) })
+
+	bus.Publish(RoadOpened{Name: "Main Street"})
+	bus.Publish(Birth{FirstName: "Ada", FamilyName: "Lovelace"})
+	unsubscribe()
+	bus.Publish(RoadOpened{Name: "High Street"})
+
+	if len(all) != 2 {
+		t.Errorf("expected 2 events before unsubscribing, got %d", len(all))
+	}
+	if len(roads) != 2 || roads[1].Name != "High Street" {
+		t.Errorf("expected 2 road events, got %v", roads)
+	}
+	if got := all[1].String(); got != "[ Baby ] Ada Lovelace has been born!" {
+		t.Errorf("unexpected event text %q", got)
+	}
+}
+
+func TestSimulationEvents(t *testing.T) {
+	t.Parallel()
+
+	sim := NewSimulation(2020, 1000000, 1)
+	var opened []RoadOpened
+	SubscribeTo(sim.Events(), func(e RoadOpened) { opened = append(opened, e) })
+
+	PlaceRoad(sim, Point{X: 4, Y: 10}, Point{X: 12, Y: 10}, Asphalt)
+	if len(opened) != 1 || opened[0].Type != Asphalt {
+		t.Errorf("expected one asphalt road to be opened, got %v", opened)
+	}
+}
@@ -1,6 +1,6 @@
 package entities
 
-import "math/rand"
+import "math/rand/v2"
 
 // Gender defines the person's gender
 type Gender string
@@ -11,8 +11,8 @@ const (
 	Other  Gender = "Other"
 )
 
-func GetRandomGender() Gender {
-	randomGender := rand.Intn(100)
+func GetRandomGender(rng *rand.Rand) Gender {
+	randomGender := rng.IntN(100)
 	gender := Other
 	switch {
 	case randomGender < 49:
@@ -17,8 +17,8 @@ type Geography struct {
 
 // Generate generates the terrain map
 // From: https://janithl.github.io/2019/09/go-terrain-gen-part-4/
-func (g *Geography) Generate() {
-	elevationMap := utils.GenerateElevationMap(g.SeaLevel, g.MaxElevation, g.Size+1,
+func (g *Geography) Generate(rng *rand.Rand) {
+	elevationMap := utils.GenerateElevationMap(rng, g.SeaLevel, g.MaxElevation, g.Size+1,
 		g.peakProbability, g.rangeProbability, g.cliffProbability)
 
 	for x := range len(elevationMap) - 1 {
@@ -119,8 +119,8 @@ func (g *Geography) GetRoadByStartEnd(roadType RoadType, x, y int) (*Road, int)
 func (g *Geography) PlaceLandUse(start Point, end Point, use LandUse) {
 	for x := min(start.X, end.X); x <= max(start.X, end.X); x++ {
 		for y := min(start.Y, end.Y); y <= max(start.Y, end.Y); y++ {
-			roadDir := Sim.Geography.getAccessRoad(x, y)
-			if roadDir != "" && g.tiles[x][y].LandUse == NoUse && g.tiles[x][y].IsBuildable() { // zone placeable!
+			roadDir := g.getAccessRoad(x, y)
+			if roadDir != "" && g.tiles[x][y].LandUse == NoUse && g.tiles[x][y].IsBuildable(g) { // zone placeable!
 				g.tiles[x][y].LandUse = use
 				g.tiles[x][y].LandStatus = UndevelopedStatus
 			}
@@ -130,7 +130,7 @@ func (g *Geography) PlaceLandUse(start Point, end Point, use LandUse) {
 
 // get access road
 func (g *Geography) getAccessRoad(x, y int) Direction {
-	if !g.BoundsCheck(x, y) || !g.tiles[x][y].IsBuildable() {
+	if !g.BoundsCheck(x, y) || !g.tiles[x][y].IsBuildable(g) {
 		return ""
 	}
 
@@ -191,7 +191,7 @@ func (g *Geography) placeRoadSegments(segments []Segment) {
 	for _, segment := range segments {
 		if segment.Direction == DirX {
 			for i := segment.Start.X; i <= segment.End.X; i++ {
-				if g.BoundsCheck(i, segment.Start.Y) && g.tiles[i][segment.Start.Y].IsBuildable() && !g.tiles[i][segment.Start.Y].IsBuilt() {
+				if g.BoundsCheck(i, segment.Start.Y) && g.tiles[i][segment.Start.Y].IsBuildable(g) && !g.tiles[i][segment.Start.Y].IsBuilt() {
 					g.tiles[i][segment.Start.Y].LandUse = TransportUse
 					g.tiles[i][segment.Start.Y].LandStatus = DevelopedStatus
 				}
@@ -200,7 +200,7 @@ func (g *Geography) placeRoadSegments(segments []Segment) {
 			g.setIntersectionType(segment.End.X, segment.Start.Y)
 		} else if segment.Direction == DirY {
 			for i := segment.Start.Y; i <= segment.End.Y; i++ {
-				if g.BoundsCheck(segment.Start.X, i) && g.tiles[segment.Start.X][i].IsBuildable() && !g.tiles[segment.Start.X][i].IsBuilt() {
+				if g.BoundsCheck(segment.Start.X, i) && g.tiles[segment.Start.X][i].IsBuildable(g) && !g.tiles[segment.Start.X][i].IsBuilt() {
 					g.tiles[segment.Start.X][i].LandUse = TransportUse
 					g.tiles[segment.Start.X][i].LandStatus = DevelopedStatus
 				}
@@ -250,11 +250,11 @@ func (g *Geography) ToggleRoundabout(x, y int) {
 }
 
 // get potential site to place a building
-func (g *Geography) GetPotentialSite(use LandUse) *Point {
+func (g *Geography) GetPotentialSite(rng *rand.Rand, use LandUse) *Point {
 	potentialSites := []*Point{}
 	for x := 0; x < g.Size; x++ {
 		for y := 0; y < g.Size; y++ {
-			if g.tiles[x][y].LandUse == use && Sim.Geography.tiles[x][y].LandStatus == UndevelopedStatus {
+			if g.tiles[x][y].LandUse == use && g.tiles[x][y].LandStatus == UndevelopedStatus {
 				roadDir := g.getAccessRoad(x, y)
 				if roadDir == "" {
 					continue
@@ -268,11 +268,11 @@ func (g *Geography) GetPotentialSite(use LandUse) *Point {
 		return nil
 	}
 
-	return potentialSites[rand.IntN(len(potentialSites))]
+	return potentialSites[rng.IntN(len(potentialSites))]
 }
 
 // NewGeography returns a new terrain map
-func NewGeography(mapSize, regionSize, maxElevation, SeaLevel, HillLevel int, peakProbability, rangeProbability, cliffProbability float64) *Geography {
+func NewGeography(rng *rand.Rand, mapSize, regionSize, maxElevation, SeaLevel, HillLevel int, peakProbability, rangeProbability, cliffProbability float64) *Geography {
 	tiles := make([][]Tile, mapSize)
 	for i := 0; i < mapSize; i++ {
 		tiles[i] = make([]Tile, mapSize)
@@ -290,7 +290,7 @@ func NewGeography(mapSize, regionSize, maxElevation, SeaLevel, HillLevel int, pe
 		Regions:          NewRegions(mapSize, regionSize),
 	}
 	// generate the terrain
-	geography.Generate()
+	geography.Generate(rng)
 
 	return geography
 }
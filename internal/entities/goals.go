@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// GoalStatus is whether a goal has been passed or failed yet
+type GoalStatus string
+
+const (
+	GoalInProgress GoalStatus = "In Progress"
+	GoalPassed     GoalStatus = "Passed"
+	GoalFailed     GoalStatus = "Failed"
+)
+
+var comparisons = map[string]func(a, b float64) bool{
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+}
+
+// Goal is an objective of a scenario: a condition on one of the city's figures that is checked every
+// month, such as population >= 5000 by 2035, or reserves >= 0 always
+type Goal struct {
+	Name       string
+	Statistic  string // name of the figure, as recorded in the city's statistics
+	Comparison string // one of <, <=, > or >=
+	Value      float64
+	Months     int    // number of months in a row the condition has to hold, 1 if zero
+	Always     bool   // the condition has to hold every month until the deadline
+	By         string // deadline as YYYY-MM-DD, after which the goal is failed, or passed if Always
+
+	// progress of the goal
+	Status    GoalStatus
+	Streak    int     // months in a row the condition has held
+	Current   float64 // value of the figure when it was last checked
+	DecidedOn time.Time
+}
+
+// Validate returns an error if the goal can't be checked
+func (g *Goal) Validate() error {
+	errs := []error{}
+	if !slices.ContainsFunc(statistics, func(st statistic) bool { return st.name == g.Statistic }) {
+		errs = append(errs, fmt.Errorf("goal %q has unknown statistic %q", g.Name, g.Statistic))
+	}
+	if _, exists := comparisons[g.Comparison]; !exists {
+		errs = append(errs, fmt.Errorf("goal %q has unknown comparison %q", g.Name, g.Comparison))
+	}
+	if g.Months < 0 {
+		errs = append(errs, fmt.Errorf("goal %q months %d is negative", g.Name, g.Months))
+	}
+	if _, err := g.deadline(); err != nil {
+		errs = append(errs, fmt.Errorf("goal %q deadline: %w", g.Name, err))
+	}
+	if g.Always && g.By == "" {
+		errs = append(errs, fmt.Errorf("goal %q has to hold always, but has no deadline", g.Name))
+	}
+	return errors.Join(errs...)
+}
+
+// deadline returns the date of the deadline, or the zero time if there is none
+func (g *Goal) deadline() (time.Time, error) {
+	if g.By == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", g.By)
+}
+
+// evaluate checks the goal against the current value of its figure, and returns true if it has just
+// been passed or failed
+func (g *Goal) evaluate(value float64, now time.Time) bool {
+	if g.Status != GoalInProgress && g.Status != "" {
+		return false
+	}
+	g.Current = value
+	met := comparisons[g.Comparison](value, g.Value)
+	deadline, _ := g.deadline()
+	deadlineReached := !deadline.IsZero() && !now.Before(deadline)
+
+	g.Status = GoalInProgress
+	if met {
+		g.Streak++
+	} else {
+		g.Streak = 0
+	}
+
+	switch {
+	case g.Always && !met:
+		g.Status = GoalFailed
+	case g.Always && deadlineReached:
+		g.Status = GoalPassed
+	case g.Always:
+	case g.Streak >= max(g.Months, 1):
+		g.Status = GoalPassed
+	case deadlineReached:
+		g.Status = GoalFailed
+	}
+
+	if g.Status == GoalInProgress {
+		return false
+	}
+	g.DecidedOn = now
+	return true
+}
+
+func (g *Goal) String() string {
+	condition := fmt.Sprintf("%s %s %g", g.Statistic, g.Comparison, g.Value)
+	switch {
+	case g.Always:
+		condition += " until " + g.By
+	case g.Months > 1 && g.By != "":
+		condition += fmt.Sprintf(" for %d months by %s", g.Months, g.By)
+	case g.Months > 1:
+		condition += fmt.Sprintf(" for %d months", g.Months)
+	case g.By != "":
+		condition += " by " + g.By
+	}
+
+	status := g.Status
+	if status == "" {
+		status = GoalInProgress
+	}
+	progress := fmt.Sprintf("%s, now %.2f", status, g.Current)
+	if !g.Always && g.Months > 1 && status == GoalInProgress {
+		progress += fmt.Sprintf(", %d/%d months", g.Streak, g.Months)
+	}
+	return fmt.Sprintf("%s: %s (%s)", g.Name, condition, progress)
+}
+
+// EvaluateGoals checks the goals that are in progress against the city, until the scenario has been
+// won or lost
+func (s *Simulation) EvaluateGoals() {
+	if len(s.Goals) == 0 || s.GoalsOutcome() != GoalInProgress {
+		return
+	}
+
+	for _, goal := range s.Goals {
+		value, _ := s.Statistic(goal.Statistic)
+		if goal.evaluate(value, s.Date) {
+			s.Events().Publish(GoalDecided{Name: goal.Name, Status: goal.Status})
+		}
+	}
+
+	if outcome := s.GoalsOutcome(); outcome != GoalInProgress {
+		s.Events().Publish(ScenarioEnded{Won: outcome == GoalPassed})
+	}
+}
+
+// GoalsOutcome returns GoalFailed if any goal has been failed, GoalPassed if every goal has been passed,
+// and otherwise GoalInProgress, which it also is if there are no goals
+func (s *Simulation) GoalsOutcome() GoalStatus {
+	if len(s.Goals) == 0 {
+		return GoalInProgress
+	}
+	if slices.ContainsFunc(s.Goals, func(g *Goal) bool { return g.Status == GoalFailed }) {
+		return GoalFailed
+	}
+	if !slices.ContainsFunc(s.Goals, func(g *Goal) bool { return g.Status != GoalPassed }) {
+		return GoalPassed
+	}
+	return GoalInProgress
+}
+
+func cloneGoals(goals []*Goal) []*Goal {
+	if goals == nil {
+		return nil
+	}
+	c := make([]*Goal, len(goals))
+	for i, goal := range goals {
+		g := *goal
+		c[i] = &g
+	}
+	return c
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGoalEvaluate(t *testing.T) {
+	months := func(n int) []time.Time {
+		dates := []time.Time{}
+		for i := range n {
+			dates = append(dates, time.Date(2030, time.Month(1+i), 1, 0, 0, 0, 0, time.UTC))
+		}
+		return dates
+	}
+
+	tests := []struct {
+		name   string
+		goal   Goal
+		values []float64
+		status GoalStatus
+		month  int // month the goal was decided in, counting from 1
+	}{
+		{"reached", Goal{Statistic: "Population", Comparison: ">=", Value: 100, By: "2030-06-01"}, []float64{50, 90, 100, 80}, GoalPassed, 3},
+		{"missed deadline", Goal{Statistic: "Population", Comparison: ">=", Value: 100, By: "2030-03-01"}, []float64{50, 90, 95, 120}, GoalFailed, 3},
+		{"sustained", Goal{Statistic: "Unemployment", Comparison: "<", Value: 5, Months: 3}, []float64{4, 6, 4, 3, 2, 7}, GoalPassed, 5},
+		{"not sustained", Goal{Statistic: "Unemployment", Comparison: "<", Value: 5, Months: 3}, []float64{4, 6, 4, 3, 6, 2}, GoalInProgress, 0},
+		{"always held", Goal{Statistic: "Reserves", Comparison: ">=", Value: 0, Always: true, By: "2030-04-01"}, []float64{10, 0, 5, 7, -1}, GoalPassed, 4},
+		{"always broken", Goal{Statistic: "Reserves", Comparison: ">=", Value: 0, Always: true, By: "2030-04-01"}, []float64{10, -1, 5, 7}, GoalFailed, 2},
+	}
+
+	for _, test := range tests {
+		goal := test.goal
+		if err := goal.Validate(); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		decided := 0
+		for i, date := range months(len(test.values)) {
+			if goal.evaluate(test.values[i], date) {
+				decided = i + 1
+			}
+		}
+		if goal.Status != test.status || decided != test.month {
+			t.Errorf("%s: expected %s in month %d, got %s in month %d", test.name, test.status, test.month, goal.Status, decided)
+		}
+	}
+}
+
+func TestGoalValidate(t *testing.T) {
+	invalid := []Goal{
+		{Statistic: "Happiness", Comparison: ">", Value: 1},
+		{Statistic: "Population", Comparison: "==", Value: 1},
+		{Statistic: "Population", Comparison: ">", Value: 1, By: "2030"},
+		{Statistic: "Reserves", Comparison: ">=", Value: 0, Always: true},
+		{Statistic: "Population", Comparison: ">", Value: 1, Months: -1},
+	}
+	for _, goal := range invalid {
+		if goal.Validate() == nil {
+			t.Errorf("invalid goal %v was valid", goal)
+		}
+	}
+}
+
+func TestEvaluateGoals(t *testing.T) {
+	sim := NewSimulation(2030, 1000, 1)
+	sim.Goals = []*Goal{
+		{Name: "Solvent", Statistic: "Reserves", Comparison: ">=", Value: 0, Always: true, By: "2030-03-01"},
+		{Name: "Rich", Statistic: "Reserves", Comparison: ">=", Value: 500},
+	}
+	ended := []ScenarioEnded{}
+	SubscribeTo(sim.Events(), func(e ScenarioEnded) { ended = append(ended, e) })
+
+	for range 4 {
+		sim.EvaluateGoals()
+		sim.Date = sim.Date.AddDate(0, 1, 0)
+	}
+	if sim.GoalsOutcome() != GoalPassed || len(ended) != 1 || !ended[0].Won {
+		t.Errorf("expected the scenario to be won once, got %s and %v", sim.GoalsOutcome(), ended)
+	}
+	if decided := sim.Goals[0].DecidedOn; !decided.Equal(time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the always goal to be passed on its deadline, got %s", decided)
+	}
+}
@@ -1,16 +1,13 @@
 package entities
 
 import (
-	"fmt"
 	"maps"
-	"time"
 
 	"github.com/janithl/citylyf/internal/utils"
 )
 
 type Government struct {
 	Reserves, CapEx                int
-	LastCalculationYear            int
 	CorporateTaxRate, SalesTaxRate float64              // Flat corporate tax rate and sales tax
 	IncomeTaxBrackets              []TaxBracket         // Progressive income tax brackets
 	Expenses                       map[CostType]float64 // Holds goverment expenses
@@ -26,52 +23,52 @@ type TaxBracket struct {
 }
 
 // CollectTaxes runs annually, collecting from companies and households
-func (g *Government) CollectTaxes() {
-	if g.LastCalculationYear >= Sim.Date.Year() { // has already run this year
-		return
-	}
-
+func (g *Government) CollectTaxes(sim *Simulation) {
 	// Collect household income taxes
 	personalTaxesCollected := 0
-	for household := range maps.Values(Sim.People.Households) {
-		householdTax := g.CalculateIncomeTax(household.AnnualIncome(false))
+	for household := range maps.Values(sim.People.Households) {
+		householdTax := g.CalculateIncomeTax(household.AnnualIncome(sim.People, sim.Date, false))
 
 		// Deduct tax from household wealth
 		household.Savings -= householdTax
 		personalTaxesCollected += householdTax
 	}
-	fmt.Printf("[  Tax ] Collected $%d in personal income taxes\n", personalTaxesCollected)
 
 	// Collect sales and corporate tax and reset tax payable account
 	salesTaxesCollected := 0
 	corporateTaxesCollected := 0
-	for id := range Sim.Companies {
-		corporateTaxesCollected += int(Sim.Companies[id].CorpTaxPayable)
-		salesTaxesCollected += int(Sim.Companies[id].SalesTaxPayable)
-		Sim.Companies[id].CorpTaxPayable = 0.0
-		Sim.Companies[id].SalesTaxPayable = 0.0
+	for id := range sim.Companies {
+		corporateTaxesCollected += int(sim.Companies[id].CorpTaxPayable)
+		salesTaxesCollected += int(sim.Companies[id].SalesTaxPayable)
+		sim.Companies[id].CorpTaxPayable = 0.0
+		sim.Companies[id].SalesTaxPayable = 0.0
 	}
-	fmt.Printf("[  Tax ] Collected $%d in sales taxes, and $%d corporate taxes\n", salesTaxesCollected, corporateTaxesCollected)
 
 	// add collected taxes to government income
 	totalTaxesCollected := personalTaxesCollected + corporateTaxesCollected + salesTaxesCollected
 	g.IncomeValues = utils.AddFifo(g.IncomeValues, totalTaxesCollected, 10)
 
 	// get opex
-	opEx := g.CalculateOpEx()
+	opEx := g.CalculateOpEx(sim.Geography.GetRoads())
 	g.OpExValues = utils.AddFifo(g.OpExValues, opEx, 10)
 
 	// calculate final reserves
 	g.Reserves = g.Reserves + totalTaxesCollected - g.CapEx - opEx
 	g.ReserveValues = utils.AddFifo(g.ReserveValues, g.Reserves, 10)
-	fmt.Printf("[  Tax ] %d: Income: $%d, CapEx: $%d, OpEx: $%d, Total Government Reserves: $%d\n",
-		g.LastCalculationYear, totalTaxesCollected, g.CapEx, opEx, g.Reserves)
+	sim.Events().Publish(TaxesCollected{
+		Year:      sim.Date.Year() - 1, // taxes are collected on the first of January for the year before
+		Personal:  personalTaxesCollected,
+		Sales:     salesTaxesCollected,
+		Corporate: corporateTaxesCollected,
+		CapEx:     g.CapEx,
+		OpEx:      opEx,
+		Reserves:  g.Reserves,
+	})
 
 	// revise government expenses
-	g.ReviseExpenses()
+	g.ReviseExpenses(sim.Market.InflationRate())
 
 	// reset capex spend
-	g.LastCalculationYear = Sim.Date.Year()
 	g.CapExValues = utils.AddFifo(g.CapExValues, g.CapEx, 10)
 	g.CapEx = 0
 }
@@ -96,13 +93,12 @@ func (g *Government) GetReservesAtHand() float64 {
 }
 
 // NewGovernment initializes the government system with reserves and progressive tax brackets
-func NewGovernment(reserves int, startDate time.Time) *Government {
+func NewGovernment(reserves int) *Government {
 	return &Government{
-		Reserves:            reserves,
-		CapEx:               0,
-		LastCalculationYear: startDate.Year(),
-		CorporateTaxRate:    9.5,
-		SalesTaxRate:        12.5,
+		Reserves:         reserves,
+		CapEx:            0,
+		CorporateTaxRate: 9.5,
+		SalesTaxRate:     12.5,
 		IncomeTaxBrackets: []TaxBracket{
 			{Threshold: 200000, Rate: 35}, // 35% for income above $200K
 			{Threshold: 100000, Rate: 25}, // 25% for income above $100K
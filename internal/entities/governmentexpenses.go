@@ -34,9 +34,9 @@ func (g *Government) AddCapEx(costType CostType, units int) {
 }
 
 // calculate annual government opex
-func (g *Government) CalculateOpEx() int {
+func (g *Government) CalculateOpEx(roads []*Road) int {
 	roadMaintenanceCost := 0
-	for _, r := range Sim.Geography.GetRoads() {
+	for _, r := range roads {
 		if r.Type == Asphalt {
 			roadMaintenanceCost += r.GetLength() * int(g.Expenses[AsphaltRoadMaintenance])
 		} else {
@@ -48,9 +48,9 @@ func (g *Government) CalculateOpEx() int {
 }
 
 // run annually to update expenses
-func (g *Government) ReviseExpenses() {
+func (g *Government) ReviseExpenses(inflationRate float64) {
 	for costType, unitCost := range g.Expenses {
-		g.Expenses[costType] += unitCost * Sim.Market.InflationRate() / 100
+		g.Expenses[costType] += unitCost * inflationRate / 100
 	}
 }
 
@@ -21,9 +21,9 @@ func (h *Household) Size() int {
 	return len(h.MemberIDs)
 }
 
-func (h *Household) FamilyName() string {
+func (h *Household) FamilyName(people *People) string {
 	if h.Size() > 0 {
-		p := Sim.People.GetPerson(h.MemberIDs[0])
+		p := people.GetPerson(h.MemberIDs[0])
 		if p != nil {
 			return p.FamilyName
 		}
@@ -31,10 +31,10 @@ func (h *Household) FamilyName() string {
 	return ""
 }
 
-func (h *Household) GetMembers() []*Person {
+func (h *Household) GetMembers(people *People) []*Person {
 	members := []*Person{}
 	for _, memberID := range h.MemberIDs {
-		p := Sim.People.GetPerson(memberID)
+		p := people.GetPerson(memberID)
 		if p != nil {
 			members = append(members, p)
 		}
@@ -48,12 +48,12 @@ func (h *Household) AddMember(personID int, savings int) {
 }
 
 // if potential = true, this returns the ideal annual income if all employeable people are employed
-func (h *Household) AnnualIncome(potential bool) int {
+func (h *Household) AnnualIncome(people *People, now time.Time, potential bool) int {
 	income := 0
 	for _, memberID := range h.MemberIDs {
-		p := Sim.People.GetPerson(memberID)
+		p := people.GetPerson(memberID)
 		if p != nil {
-			if potential && p.IsEmployable() {
+			if potential && p.IsEmployable(now) {
 				income += p.AnnualIncome
 			} else {
 				income += p.CurrentIncome()
@@ -64,11 +64,11 @@ func (h *Household) AnnualIncome(potential bool) int {
 }
 
 // eligible for move out if 1/4 years without income
-func (h *Household) IsEligibleForMoveOut() bool {
-	timeSinceMoveIn := Sim.Date.Sub(h.MoveInDate).Hours() / HoursPerYear
+func (h *Household) IsEligibleForMoveOut(sim *Simulation) bool {
+	timeSinceMoveIn := sim.Date.Sub(h.MoveInDate).Hours() / HoursPerYear
 	noIncome := true
 	for _, memberID := range h.MemberIDs {
-		p := Sim.People.GetPerson(memberID)
+		p := sim.People.GetPerson(memberID)
 		if p != nil && (p.IsEmployed() || (p.CareerLevel == Retired && h.Savings > 0)) {
 			noIncome = false
 		}
@@ -77,11 +77,11 @@ func (h *Household) IsEligibleForMoveOut() bool {
 }
 
 // calculate monthly budget
-func (h *Household) CalculateMonthlyBudget(addPayToPayroll func(companyID int, payAmount float64)) {
-	daysSinceLastPay := Sim.Date.Sub(h.LastPayDay).Hours() / HoursPerDay
+func (h *Household) CalculateMonthlyBudget(sim *Simulation, addPayToPayroll func(companyID int, payAmount float64)) {
+	daysSinceLastPay := sim.Date.Sub(h.LastPayDay).Hours() / HoursPerDay
 	pay := 0.0
 	for _, memberID := range h.MemberIDs {
-		p := Sim.People.GetPerson(memberID)
+		p := sim.People.GetPerson(memberID)
 		if p != nil {
 			memberPay := float64(p.CurrentIncome()) * daysSinceLastPay / DaysPerYear
 			p.Savings += int(memberPay)
@@ -89,12 +89,16 @@ func (h *Household) CalculateMonthlyBudget(addPayToPayroll func(companyID int, p
 			pay += memberPay
 		}
 	}
-	house, exists := Sim.Houses[h.HouseID]
+	house, exists := sim.Houses[h.HouseID]
 	if exists {
-		expenses := house.MonthlyRent // TODO: Expand this
+		expenses := 0 // households that own their house pay its mortgage to the bank instead of rent
+		if house.Owner != OwnerHousehold {
+			expenses = house.MonthlyRent // TODO: Expand this
+			house.PayRent(sim)
+		}
 		h.Savings += int(pay) - expenses
 		h.LastMonthExpenses = expenses
-		h.LastPayDay = Sim.Date
+		h.LastPayDay = sim.Date
 	}
 }
 
@@ -102,27 +106,27 @@ func (h *Household) GetID() int {
 	return h.ID
 }
 
-func (h *Household) GetStats() string {
-	return fmt.Sprintf("%-30s %02d/%02d   %s   %s", h.FamilyName()+" family", h.Size(), h.GetEmployedCount(),
+func (h *Household) GetStats(sim *Simulation) string {
+	return fmt.Sprintf("%-30s %02d/%02d   %s   %s", h.FamilyName(sim.People)+" family", h.Size(), h.GetEmployedCount(sim.People),
 		"Moved in "+h.MoveInDate.Format("2006-01-02"), utils.FormatCurrency(float64(h.Savings), "$"))
 }
 
-func (h *Household) GetMemberStats() string {
+func (h *Household) GetMemberStats(sim *Simulation) string {
 	stats := ""
 	for _, memberID := range h.MemberIDs {
-		p := Sim.People.GetPerson(memberID)
+		p := sim.People.GetPerson(memberID)
 		if p != nil {
-			stats += p.String() + "\n"
+			stats += p.GetStats(sim.Date) + "\n"
 		}
 	}
 	return stats
 }
 
 // GetEmployedCount returns the number of employed members of the household
-func (h *Household) GetEmployedCount() int {
+func (h *Household) GetEmployedCount(people *People) int {
 	employed := 0
 	for _, memberID := range h.MemberIDs {
-		p := Sim.People.GetPerson(memberID)
+		p := people.GetPerson(memberID)
 		if p != nil && p.IsEmployed() {
 			employed++
 		}
@@ -131,11 +135,11 @@ func (h *Household) GetEmployedCount() int {
 }
 
 // GetAdultCount returns the number of adult members of the household
-func (h *Household) GetAdultCount() int {
+func (h *Household) GetAdultCount(people *People, now time.Time) int {
 	adults := 0
 	for _, memberID := range h.MemberIDs {
-		p := Sim.People.GetPerson(memberID)
-		if p != nil && p.Age() >= AgeOfAdulthood {
+		p := people.GetPerson(memberID)
+		if p != nil && p.Age(now) >= AgeOfAdulthood {
 			adults++
 		}
 	}
@@ -164,13 +168,13 @@ func (h *Household) RemoveMember(person *Person) {
 	h.Savings -= person.Savings
 }
 
-// FindHousing assigns a house to a househld
-func (h *Household) FindHousing() int {
-	monthlyRentBudget := float64(h.AnnualIncome(true)) / (4 * 12)          // 25% of (potential) yearly income towards rent / 12
-	houseID := Sim.Houses.MoveIn(h.ID, int(monthlyRentBudget), h.Size()/2) // everyone gets to share a bedroom
+// FindHousing assigns a house to a househld, which it buys if it can and rents if it can't
+func (h *Household) FindHousing(sim *Simulation) int {
+	monthlyRentBudget := float64(h.AnnualIncome(sim.People, sim.Date, true)) / (4 * 12) // 25% of (potential) yearly income towards rent / 12
+	houseID := sim.Houses.MoveIn(sim, h, int(monthlyRentBudget), h.Size()/2)            // everyone gets to share a bedroom
 	if houseID > 0 {
 		h.HouseID = houseID
-		fmt.Printf("[ Move ] %s family has moved into house #%d, %d houses remain\n", h.FamilyName(), houseID, Sim.Houses.GetFreeHouses())
+		sim.Events().Publish(HouseholdMovedIn{HouseholdName: h.FamilyName(sim.People), HouseID: houseID, FreeHouses: sim.Houses.GetFreeHouses()})
 	}
 
 	return houseID
@@ -3,7 +3,6 @@ package entities
 import (
 	"maps"
 	"math"
-	"math/rand/v2"
 	"slices"
 	"time"
 
@@ -17,8 +16,27 @@ const (
 	HouseLarge HouseType = "house-large"
 )
 
+// Owner is who owns a house
+type Owner string
+
+const (
+	OwnerLandlord   Owner = "landlord"
+	OwnerGovernment Owner = "government"
+	OwnerHousehold  Owner = "household" // the household that lives in it
+)
+
+const (
+	RentalYieldSpread   = 2.0  // percentage points over the central bank's rate that buyers expect rents to yield
+	MinRentalYield      = 3.0  // lowest percentage of its price that buyers expect a house's rent to yield
+	DepositShare        = 0.1  // share of a house's price that buyers have to pay from their savings
+	SavingsBufferMonths = 3    // months of rent that buyers keep in their savings
+	MortgageRentPremium = 1.25 // most that buyers pay on a mortgage for every dollar of rent they'd pay instead
+)
+
 type House struct {
 	ID, HouseholdID, Bedrooms, MonthlyRent int
+	Owner                                  Owner
+	Price                                  int // what the house would sell for
 	HouseType                              HouseType
 	Location                               *Point
 	RoadDirection                          Direction
@@ -37,24 +55,123 @@ func (h Housing) GetIDs() []int {
 	return IDs
 }
 
-func (h Housing) MoveIn(householdID, budget, bedrooms int) int {
-	for _, house := range h {
-		if house.HouseholdID == 0 &&
-			house.Bedrooms >= bedrooms &&
-			house.MonthlyRent <= budget {
-			house.HouseholdID = householdID
-			house.LastRentRevision = Sim.Date // Lock in rents for 1 year
+// HousePrice returns what a house with a given rent sells for: the price at which its rent yields what
+// buyers expect at the central bank's rate
+func HousePrice(monthlyRent int, interestRate float64) int {
+	yield := max(interestRate+RentalYieldSpread, MinRentalYield) / 100
+	return int(float64(monthlyRent) * 12 / yield)
+}
+
+// MoveIn moves a household into the first free house with enough bedrooms that it can either buy or
+// rent on its budget, returning the house's ID. Households buy when they can borrow what they need
+// and the mortgage costs them little more than the rent.
+func (h Housing) MoveIn(sim *Simulation, household *Household, budget, bedrooms int) int {
+	for _, id := range h.GetIDs() {
+		house := h[id]
+		if house.HouseholdID != 0 || house.Bedrooms < bedrooms {
+			continue
+		}
+		if house.buy(sim, household, budget) || house.MonthlyRent <= budget {
+			house.HouseholdID = household.ID
+			house.LastRentRevision = sim.Date // Lock in rents for 1 year
 			return house.ID
 		}
 	}
 	return 0
 }
 
-func (h Housing) MoveOut(houseID int) {
+// MoveOut moves a household out of a house. Households that own their house sell it to the landlord,
+// or to the government if the landlord can't afford it, and pay off their mortgage with the proceeds.
+// If neither can afford it, the landlord buys it for all the cash it has.
+func (h Housing) MoveOut(sim *Simulation, houseID int) {
 	house, exists := h[houseID]
-	if exists {
-		house.HouseholdID = 0
+	if !exists {
+		return
 	}
+	if house.Owner == OwnerHousehold {
+		buyer, price := OwnerLandlord, house.Price
+		if sim.Landlord.Cash < float64(price) {
+			if sim.Government.GetReservesAtHand() >= float64(price) {
+				buyer = OwnerGovernment
+			} else {
+				price = max(int(sim.Landlord.Cash), 0)
+			}
+		}
+		house.sellTo(sim, buyer, price)
+
+		familyName := ""
+		if household, exists := sim.People.Households[house.HouseholdID]; exists {
+			sim.Bank.Repay(household, Mortgage)
+			familyName = household.FamilyName(sim.People)
+		}
+		sim.Events().Publish(HouseSold{FamilyName: familyName, HouseID: house.ID, Price: price, Buyer: buyer})
+	}
+	house.HouseholdID = 0
+}
+
+// buy buys a house for a household that has the deposit, after keeping some savings to get by on, that
+// the bank will lend the rest to, and whose mortgage payments fit its budget and aren't much over the rent
+func (house *House) buy(sim *Simulation, household *Household, budget int) bool {
+	price := float64(house.Price)
+	deposit := min(float64(household.Savings-house.MonthlyRent*SavingsBufferMonths), price)
+	if price <= 0 || deposit < price*DepositShare {
+		return false
+	}
+	payment := monthlyPayment(price-deposit, sim.Bank.Rate(sim, Mortgage), MortgageTermMonths)
+	if payment > float64(budget) || payment > float64(house.MonthlyRent)*MortgageRentPremium {
+		return false
+	}
+	if deposit < price {
+		if _, ok := sim.Bank.Lend(sim, household, Mortgage, price-deposit); !ok {
+			return false
+		}
+	}
+
+	house.credit(sim, house.Price)
+	house.Owner = OwnerHousehold
+	household.Savings -= house.Price
+	sim.Events().Publish(HouseSold{FamilyName: household.FamilyName(sim.People), HouseID: house.ID, Price: house.Price, Buyer: OwnerHousehold})
+	return true
+}
+
+// sellTo sells a house to a new owner for a price
+func (house *House) sellTo(sim *Simulation, buyer Owner, price int) {
+	house.credit(sim, price)
+	house.Owner = buyer
+	house.credit(sim, -price)
+}
+
+// credit pays an amount to a house's owner, or takes it from them if it is negative
+func (house *House) credit(sim *Simulation, amount int) {
+	switch house.Owner {
+	case OwnerLandlord:
+		sim.Landlord.Cash += float64(amount)
+	case OwnerGovernment:
+		sim.Government.Reserves += amount
+	case OwnerHousehold:
+		if household, exists := sim.People.Households[house.HouseholdID]; exists {
+			household.Savings += amount
+		}
+	}
+}
+
+// PayRent pays a month's rent on a house to its owner
+func (house *House) PayRent(sim *Simulation) {
+	house.credit(sim, house.MonthlyRent)
+	if house.Owner == OwnerLandlord {
+		sim.Landlord.RentIncome += float64(house.MonthlyRent)
+	}
+}
+
+// GetOwnedHouses returns the number of houses an owner owns
+func (h Housing) GetOwnedHouses(owner Owner) int {
+	owned := 0
+	for _, house := range h {
+		if house.Owner == owner {
+			owned++
+		}
+	}
+	return owned
 }
 
 func (h Housing) GetFreeHouses() int {
@@ -87,6 +204,26 @@ func (h Housing) GetAverageMonthlyRent() float64 {
 	return rent / float64(len(h))
 }
 
+func (h Housing) GetAverageHousePrice() float64 {
+	if len(h) == 0 {
+		return 0.0
+	}
+
+	price := 0.0
+	for _, house := range h {
+		price += float64(house.Price)
+	}
+	return price / float64(len(h))
+}
+
+// RevalueHouses prices every house at what its rent sells for at the central bank's rate
+func (h Housing) RevalueHouses(sim *Simulation) {
+	for _, house := range h {
+		house.Price = HousePrice(house.MonthlyRent, sim.Market.InterestRate())
+	}
+	sim.Market.History.AverageHousePrice = utils.AddFifo(sim.Market.History.AverageHousePrice, h.GetAverageHousePrice(), 20)
+}
+
 // GetCostOfLivingFactor returns a multiplier based on the change in average rent
 func (h Housing) GetCostOfLivingFactor() float64 {
 	costOfLivingFactor := h.GetAverageMonthlyRent() / float64(h.GetBaselineMonthlyRent(3))
@@ -97,9 +234,9 @@ func (h Housing) VacancyRate() float64 {
 	return float64(h.GetFreeHouses()) / float64(len(h))
 }
 
-func (h Housing) ReviseRents() {
+func (h Housing) ReviseRents(sim *Simulation) {
 	// Base adjustment using interest rate
-	adjustmentFactor := Sim.Market.InterestRate() / 100
+	adjustmentFactor := sim.Market.InterestRate() / 100
 
 	// Adjust based on vacancy rate (high vacancy → reduce rent, low vacancy → normal increase)
 	if h.VacancyRate() > 0.15 { // 15% vacancy threshold
@@ -109,33 +246,33 @@ func (h Housing) ReviseRents() {
 	}
 
 	// Income & Inflation considerations: If income isn't rising, slow rent increases
-	incomeFactor := Sim.People.AverageWageGrowthRate() - Sim.Market.InflationRate()
+	incomeFactor := sim.People.AverageWageGrowthRate() - sim.Market.InflationRate()
 	if incomeFactor < 0 {
 		adjustmentFactor *= 0.8 // Reduce rent increase if incomes are stagnating
 	}
 
 	// Apply the adjusted rent increase
 	for _, house := range h {
-		if Sim.Date.Sub(house.LastRentRevision).Hours() > HoursPerYear { // Revise rents every year
+		if sim.Date.Sub(house.LastRentRevision).Hours() > HoursPerYear { // Revise rents every year
 			currentRent := float64(house.MonthlyRent)
 			rentChange := currentRent * adjustmentFactor
 			rentChange = utils.Clamp(rentChange, -0.05*currentRent, 0.10*currentRent) // Clamp change between -5% and +10%
 
 			house.MonthlyRent += int(rentChange)
-			house.LastRentRevision = Sim.Date
+			house.LastRentRevision = sim.Date
 		}
 	}
 
-	Sim.Market.History.AverageRent = utils.AddFifo(Sim.Market.History.AverageRent, h.GetAverageMonthlyRent(), 20)
+	sim.Market.History.AverageRent = utils.AddFifo(sim.Market.History.AverageRent, h.GetAverageMonthlyRent(), 20)
 }
 
 // AverageRentGrowthRate returns the percentage growth rate of the AverageMonthlyRent
-func (h Housing) AverageRentGrowthRate() float64 {
-	if len(Sim.Market.History.AverageRent) == 0 {
+func (h Housing) AverageRentGrowthRate(market *Market) float64 {
+	if len(market.History.AverageRent) == 0 {
 		return 0.0
 	}
 
-	lastAverageRentValue := utils.GetLastValue(Sim.Market.History.AverageRent)
+	lastAverageRentValue := utils.GetLastValue(market.History.AverageRent)
 	if lastAverageRentValue == 0 {
 		return 0.0
 	}
@@ -152,33 +289,36 @@ func (h Housing) GetLocationHouse(x, y int) *House {
 	return nil
 }
 
-func (h Housing) PlaceHousing() {
-	if Sim.Market.HousingDemand < 0.05 && h.GetFreeHouses() > 3 { // low demand and enough free houses, no need to place more
+func (h Housing) PlaceHousing(sim *Simulation) {
+	if sim.Market.HousingDemand < 0.05 && h.GetFreeHouses() > 3 { // low demand and enough free houses, no need to place more
 		return
 	}
 
-	bedrooms := 2 + rand.IntN(3)
-	site := Sim.Geography.GetPotentialSite(ResidentialUse)
+	bedrooms := 2 + sim.rng.IntN(3)
+	site := sim.Geography.GetPotentialSite(sim.rng, ResidentialUse)
 	if site == nil { // no suitable sites
 		return
 	}
 
-	Sim.Geography.tiles[site.X][site.Y].LandStatus = DevelopedStatus
+	sim.Geography.tiles[site.X][site.Y].LandStatus = DevelopedStatus
 
-	houseID := Sim.GetNextID()
+	houseID := sim.GetNextID()
 	houseType := HouseSmall
 	if bedrooms > 3 {
 		houseType = HouseLarge
 	}
 
-	h[houseID] = &House{
+	monthlyRent := int(h.GetCostOfLivingFactor() * float64(h.GetBaselineMonthlyRent(bedrooms)))
+	h[houseID] = &House{ // houses are built by the landlord, which rents them out or sells them
 		ID:               houseID,
 		HouseholdID:      0,
 		Bedrooms:         bedrooms,
-		MonthlyRent:      int(h.GetCostOfLivingFactor() * float64(h.GetBaselineMonthlyRent(bedrooms))),
+		MonthlyRent:      monthlyRent,
+		Owner:            OwnerLandlord,
+		Price:            HousePrice(monthlyRent, sim.Market.InterestRate()),
 		HouseType:        houseType,
 		Location:         site,
-		RoadDirection:    Sim.Geography.getAccessRoad(site.X, site.Y),
-		LastRentRevision: Sim.Date,
+		RoadDirection:    sim.Geography.getAccessRoad(site.X, site.Y),
+		LastRentRevision: sim.Date,
 	}
 }
@@ -0,0 +1,69 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// TestHomeOwnership checks that house prices follow rates, that households buy when they can afford
+// to and rent when they can't or the mortgage would cost them too much more than the rent, that rents
+// are paid to landlords, and that owners sell up as they leave, without the government overspending
+func TestHomeOwnership(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	rate := sim.Market.InterestRate()
+	if entities.HousePrice(1500, rate+1) >= entities.HousePrice(1500, rate) {
+		t.Error("expected house prices to fall as rates rise")
+	}
+
+	for id := 1; id <= 2; id++ {
+		sim.Houses[id] = &entities.House{ID: id, Bedrooms: 2, MonthlyRent: 1500, Owner: entities.OwnerLandlord}
+	}
+	sim.Houses.RevalueHouses(sim)
+	price := sim.Houses[1].Price
+	if price != entities.HousePrice(1500, rate) {
+		t.Fatalf("expected houses to be priced at %d, got %d", entities.HousePrice(1500, rate), price)
+	}
+
+	buyer := newBankHousehold(sim, 80000, price/5)
+	if houseID := sim.Houses.MoveIn(sim, buyer, 1700, 1); houseID != 1 || sim.Houses[1].Owner != entities.OwnerHousehold {
+		t.Fatalf("expected a household with a deposit to buy house 1, got house %d owned by the %s", houseID, sim.Houses[1].Owner)
+	}
+	buyer.HouseID = 1
+	loans := sim.Bank.HouseholdLoans(buyer.ID)
+	if len(loans) != 1 || loans[0].Kind != entities.Mortgage || sim.Landlord.Cash != float64(price) {
+		t.Fatalf("expected the purchase to be financed by a mortgage and paid to the landlord, got %d loans and %.0f", len(loans), sim.Landlord.Cash)
+	}
+
+	renter := newBankHousehold(sim, 80000, 0)
+	if houseID := sim.Houses.MoveIn(sim, renter, 1700, 1); houseID != 2 || sim.Houses[2].Owner != entities.OwnerLandlord {
+		t.Fatalf("expected a household without a deposit to rent house 2, got house %d owned by the %s", houseID, sim.Houses[2].Owner)
+	}
+	renter.HouseID = 2
+
+	renter.CalculateMonthlyBudget(sim, func(int, float64) {})
+	buyer.CalculateMonthlyBudget(sim, func(int, float64) {})
+	if sim.Landlord.RentIncome != 1500 || buyer.LastMonthExpenses != 0 {
+		t.Errorf("expected rent from the renter alone to be paid to the landlord, got %.0f", sim.Landlord.RentIncome)
+	}
+
+	sim.Houses.MoveOut(sim, 1)
+	if sim.Houses[1].Owner != entities.OwnerLandlord || sim.Houses[1].HouseholdID != 0 || len(sim.Bank.HouseholdLoans(buyer.ID)) != 0 {
+		t.Errorf("expected the owner to sell to the landlord and pay off its mortgage, got house owned by the %s", sim.Houses[1].Owner)
+	}
+
+	sim.Houses[1].Price = price * 2 // a house whose rent yields too little for a mortgage on it to be worth it
+	stretched := newBankHousehold(sim, 80000, price)
+	if houseID := sim.Houses.MoveIn(sim, stretched, 1700, 1); houseID != 1 || sim.Houses[1].Owner != entities.OwnerLandlord {
+		t.Fatalf("expected a household that could borrow to rent when the mortgage costs more than the rent, got house %d owned by the %s", houseID, sim.Houses[1].Owner)
+	}
+
+	seller := newBankHousehold(sim, 80000, 0)
+	sim.Houses[3] = &entities.House{ID: 3, HouseholdID: seller.ID, Bedrooms: 2, MonthlyRent: 1500, Owner: entities.OwnerHousehold, Price: price}
+	sim.Landlord.Cash, sim.Government.Reserves = float64(price/2), price/4
+	sim.Houses.MoveOut(sim, 3)
+	if sim.Houses[3].Owner != entities.OwnerLandlord || sim.Government.Reserves != price/4 || sim.Landlord.Cash != 0 || seller.Savings != price/2 {
+		t.Errorf("expected a house neither can afford to be sold to the landlord for its cash, got it owned by the %s for %d", sim.Houses[3].Owner, seller.Savings)
+	}
+}
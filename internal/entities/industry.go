@@ -1,7 +1,5 @@
 package entities
 
-import "math/rand"
-
 // Industry defines the industry of the business
 type Industry string
 
@@ -18,11 +16,3 @@ const (
 	Technology         Industry = "Technology"
 	Telecommunications Industry = "Telecommunications"
 )
-
-var industries = []Industry{
-	Agriculture, Automobile, Construction, Education, Energy, Finance, Healthcare, Retail, Technology, Telecommunications,
-}
-
-func GetRandomIndustry() Industry {
-	return industries[rand.Intn(len(industries))]
-}
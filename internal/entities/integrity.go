@@ -0,0 +1,215 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// IntegrityIssue is a reference between entities that is dangling or only goes one way
+type IntegrityIssue struct {
+	Entity   string // type of the entity holding the reference, such as "household"
+	ID       int    // ID of the entity holding the reference
+	Problem  string
+	Repaired bool
+}
+
+func (i IntegrityIssue) String() string {
+	status := "not repaired"
+	if i.Repaired {
+		status = "repaired"
+	}
+	return fmt.Sprintf("%s %d: %s (%s)", i.Entity, i.ID, i.Problem, status)
+}
+
+// IntegrityReport lists the issues found when checking the references between entities
+type IntegrityReport struct {
+	Issues []IntegrityIssue
+}
+
+// OK returns true if no issues were found
+func (r *IntegrityReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Unrepaired returns the issues that could not be safely repaired
+func (r *IntegrityReport) Unrepaired() []IntegrityIssue {
+	return slices.DeleteFunc(slices.Clone(r.Issues), func(i IntegrityIssue) bool { return i.Repaired })
+}
+
+func (r *IntegrityReport) String() string {
+	if r.OK() {
+		return "no integrity issues"
+	}
+	lines := []string{fmt.Sprintf("%d integrity issues, %d not repaired:", len(r.Issues), len(r.Unrepaired()))}
+	for _, issue := range r.Issues {
+		lines = append(lines, "  "+issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *IntegrityReport) add(entity string, id int, repaired bool, format string, args ...any) {
+	r.Issues = append(r.Issues, IntegrityIssue{Entity: entity, ID: id, Problem: fmt.Sprintf(format, args...), Repaired: repaired})
+}
+
+// CheckIntegrity checks that the IDs entities refer to each other by exist and agree with each
+// other, and if repair is true fixes the ones it safely can. Households without members are
+// removed, as are employees and members that no longer exist, and one-sided links between
+// households and houses, and people and their employers, are either completed or cut.
+func (s *Simulation) CheckIntegrity(repair bool) *IntegrityReport {
+	report := &IntegrityReport{}
+	s.checkHouseholdMembers(report, repair)
+	s.checkHouseholdHouses(report, repair)
+	s.checkHouses(report, repair)
+	s.checkEmployers(report, repair)
+	s.checkEmployees(report, repair)
+	return report
+}
+
+// checkHouseholdMembers checks that every person is in exactly one household
+func (s *Simulation) checkHouseholdMembers(report *IntegrityReport, repair bool) {
+	householdOf := map[int]int{}
+	for _, id := range s.People.GetHouseholdIDs() {
+		household := s.People.Households[id]
+		members := []int{}
+		for _, memberID := range household.MemberIDs {
+			switch otherID, inOther := householdOf[memberID]; {
+			case s.People.GetPerson(memberID) == nil:
+				report.add("household", id, repair, "member %d does not exist", memberID)
+			case inOther && otherID == id:
+				report.add("household", id, repair, "member %d is listed more than once", memberID)
+			case inOther:
+				report.add("household", id, repair, "member %d is also in household %d", memberID, otherID)
+			default:
+				householdOf[memberID] = id
+				members = append(members, memberID)
+			}
+		}
+		if repair && len(members) != len(household.MemberIDs) {
+			household.MemberIDs = members
+		}
+
+		if len(members) == 0 {
+			report.add("household", id, repair, "has no members")
+			if repair {
+				s.Houses.MoveOut(s, household.HouseID)
+				delete(s.People.Households, id)
+			}
+		}
+	}
+
+	for _, id := range s.People.GetPersonIDs() {
+		if _, inHousehold := householdOf[id]; !inHousehold {
+			report.add("person", id, false, "is not in a household")
+		}
+	}
+}
+
+// checkHouseholdHouses checks that every household's house exists and is lived in by them
+func (s *Simulation) checkHouseholdHouses(report *IntegrityReport, repair bool) {
+	for _, id := range s.People.GetHouseholdIDs() {
+		household := s.People.Households[id]
+		if household.HouseID == 0 {
+			continue
+		}
+
+		house, exists := s.Houses[household.HouseID]
+		switch {
+		case !exists:
+			report.add("household", id, repair, "house %d does not exist", household.HouseID)
+			if repair {
+				household.HouseID = 0
+			}
+		case house.HouseholdID == 0:
+			report.add("household", id, repair, "house %d is empty", household.HouseID)
+			if repair {
+				house.HouseholdID = id
+			}
+		case house.HouseholdID != id:
+			report.add("household", id, repair, "house %d is lived in by household %d", household.HouseID, house.HouseholdID)
+			if repair {
+				household.HouseID = 0
+			}
+		}
+	}
+}
+
+// checkHouses checks that every lived in house's household exists and lives there
+func (s *Simulation) checkHouses(report *IntegrityReport, repair bool) {
+	for _, id := range s.Houses.GetIDs() {
+		house := s.Houses[id]
+		if house.HouseholdID == 0 {
+			if house.Owner == OwnerHousehold {
+				report.add("house", id, repair, "is empty but owned by the household that lived in it")
+				if repair {
+					house.Owner = OwnerGovernment
+				}
+			}
+			continue
+		}
+
+		household, exists := s.People.Households[house.HouseholdID]
+		switch {
+		case !exists:
+			report.add("house", id, repair, "household %d does not exist", house.HouseholdID)
+		case household.HouseID != id:
+			report.add("house", id, repair, "household %d lives in house %d", house.HouseholdID, household.HouseID)
+		default:
+			continue
+		}
+		if repair {
+			house.HouseholdID = 0
+			if house.Owner == OwnerHousehold { // houses left without their owners pass to the government
+				house.Owner = OwnerGovernment
+			}
+		}
+	}
+}
+
+// checkEmployers checks that every employed person's employer exists and employs them
+func (s *Simulation) checkEmployers(report *IntegrityReport, repair bool) {
+	for _, id := range s.People.GetPersonIDs() {
+		person := s.People.People[id]
+		if !person.IsEmployed() {
+			continue
+		}
+
+		company, exists := s.Companies[person.EmployerID]
+		switch {
+		case !exists:
+			report.add("person", id, repair, "employer %d does not exist", person.EmployerID)
+			if repair {
+				person.EmployerID = 0
+			}
+		case !slices.Contains(company.Employees, id):
+			report.add("person", id, repair, "is not an employee of employer %d", person.EmployerID)
+			if repair {
+				company.AddEmployee(id)
+			}
+		}
+	}
+}
+
+// checkEmployees checks that every company's employees exist and work there
+func (s *Simulation) checkEmployees(report *IntegrityReport, repair bool) {
+	for _, id := range s.Companies.GetIDs() {
+		company := s.Companies[id]
+		employees := []int{}
+		for _, employeeID := range company.Employees {
+			person := s.People.GetPerson(employeeID)
+			switch {
+			case person == nil:
+				report.add("company", id, repair, "employee %d does not exist", employeeID)
+			case person.EmployerID != id:
+				report.add("company", id, repair, "employee %d works for employer %d", employeeID, person.EmployerID)
+			case slices.Contains(employees, employeeID):
+				report.add("company", id, repair, "employee %d is listed more than once", employeeID)
+			default:
+				employees = append(employees, employeeID)
+			}
+		}
+		if repair && len(employees) != len(company.Employees) {
+			company.Employees = employees
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package entities_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1000000, 1)
+	sim.People.People = map[int]*entities.Person{
+		1001: {ID: 1001, EmployerID: 2001}, // employed, but not on the company's books
+		1002: {ID: 1002, EmployerID: 2999}, // employer does not exist
+		1003: {ID: 1003},                   // not in any household
+	}
+	sim.People.Households = map[int]*entities.Household{
+		3001: {ID: 3001, HouseID: 4001, MemberIDs: []int{1001, 1001, 1999}}, // listed twice, and a member who does not exist
+		3002: {ID: 3002, HouseID: 4002, MemberIDs: []int{1002, 1001}},       // member also in household 3001
+		3003: {ID: 3003, HouseID: 4003, MemberIDs: []int{1998}},             // nobody in the household exists
+	}
+	sim.Houses = entities.Housing{
+		4001: {ID: 4001, HouseholdID: 3001},
+		4002: {ID: 4002},                    // empty, though household 3002 lives here
+		4003: {ID: 4003, HouseholdID: 3003}, // household will be removed
+		4004: {ID: 4004, HouseholdID: 3999}, // household does not exist
+	}
+	sim.Companies = entities.Companies{
+		2001: {ID: 2001, Employees: []int{1002, 1997}}, // employees who work elsewhere or do not exist
+	}
+
+	report := sim.CheckIntegrity(false)
+	if len(report.Issues) != 12 || len(report.Unrepaired()) != 12 {
+		t.Fatalf("expected 12 unrepaired issues when checking, got %s", report)
+	}
+	if len(sim.People.Households[3001].MemberIDs) != 3 {
+		t.Fatal("expected checking without repairing to leave the simulation alone")
+	}
+
+	report = sim.CheckIntegrity(true)
+	if unrepaired := report.Unrepaired(); len(unrepaired) != 1 || unrepaired[0].Entity != "person" || unrepaired[0].ID != 1003 {
+		t.Errorf("expected only the person without a household to be unrepaired, got %s", report)
+	}
+	if members := sim.People.Households[3001].MemberIDs; !slices.Equal(members, []int{1001}) {
+		t.Errorf("expected household 3001 to have member 1001 only, got %v", members)
+	}
+	if members := sim.People.Households[3002].MemberIDs; !slices.Equal(members, []int{1002}) {
+		t.Errorf("expected household 3002 to have member 1002 only, got %v", members)
+	}
+	if _, exists := sim.People.Households[3003]; exists || sim.Houses[4003].HouseholdID != 0 {
+		t.Error("expected the empty household to be removed and its house vacated")
+	}
+	if sim.Houses[4002].HouseholdID != 3002 || sim.Houses[4004].HouseholdID != 0 {
+		t.Error("expected houses to be lived in by the households that live in them")
+	}
+	if sim.People.People[1002].EmployerID != 0 || !slices.Equal(sim.Companies[2001].Employees, []int{1001}) {
+		t.Errorf("expected employment to be repaired, got employees %v", sim.Companies[2001].Employees)
+	}
+
+	if report := sim.CheckIntegrity(true); len(report.Issues) != 1 {
+		t.Errorf("expected a repaired simulation to only have the unrepairable issue left, got %s", report)
+	}
+}
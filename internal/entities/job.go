@@ -1,27 +1,4 @@
 package entities
 
-// Job defines the different jobs
+// Job defines the different jobs, which are listed in the catalogue
 type Job string
-
-const (
-	AIResearcher         Job = "AI Researcher"
-	Artist               Job = "Artist"
-	CivilEngineer        Job = "Civil Engineer"
-	CybersecurityAnalyst Job = "Cybersecurity Analyst"
-	Doctor               Job = "Doctor"
-	ElectricalEngineer   Job = "Electrical Engineer"
-	FarmManager          Job = "Farm Manager"
-	FinancialAnalyst     Job = "Financial Analyst"
-	Geologist            Job = "Geologist"
-	MechanicalEngineer   Job = "Mechanical Engineer"
-	NetworkEngineer      Job = "Network Engineer"
-	Nurse                Job = "Nurse"
-	Paramedic            Job = "Paramedic"
-	QualityEngineer      Job = "Quality Engineer"
-	SoftwareEngineer     Job = "Software Engineer"
-	StoreManager         Job = "Store Manager"
-	SupplyChainManager   Job = "Supply Chain Manager"
-	RetailSalesAssociate Job = "RetailSalesAssociate"
-	StockClerk           Job = "StockClerk"
-	Teacher              Job = "Teacher"
-)
@@ -0,0 +1,24 @@
+package entities
+
+// Landlord owns the city's rented houses that the government doesn't, and collects their rents
+type Landlord struct {
+	Cash                       float64 // rents and sales, less what it has paid for houses
+	RentIncome, LastRentIncome float64 // rents collected this month, and last month
+}
+
+// CalculateMonthlyAccounts closes the landlord's month, buying the government's houses with the cash it
+// has to spare
+func (l *Landlord) CalculateMonthlyAccounts(sim *Simulation) {
+	l.LastRentIncome, l.RentIncome = l.RentIncome, 0
+	for _, id := range sim.Houses.GetIDs() {
+		house := sim.Houses[id]
+		if house.Owner == OwnerGovernment && house.Price > 0 && l.Cash >= float64(house.Price) {
+			house.sellTo(sim, OwnerLandlord, house.Price)
+		}
+	}
+}
+
+func (l *Landlord) clone() *Landlord {
+	c := *l
+	return &c
+}
@@ -30,9 +30,17 @@ const (
 	ReserveUse     LandUse = "reserve"
 	TransportUse   LandUse = "transport"
 	AgricultureUse LandUse = "agriculture"
+	CommercialUse  LandUse = "commercial"
+	IndustrialUse  LandUse = "industrial"
 	NoUse          LandUse = ""
 )
 
+// ZoningUses are the land uses tiles can be zoned for
+var ZoningUses = []LandUse{ResidentialUse, RetailUse, AgricultureUse, CommercialUse, IndustrialUse}
+
+// CompanyUses are the land uses companies are sited on
+var CompanyUses = []LandUse{RetailUse, AgricultureUse, CommercialUse, IndustrialUse}
+
 // LandStatus defines at which stage of development a tile is at
 type LandStatus string
 
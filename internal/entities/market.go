@@ -1,9 +1,8 @@
 package entities
 
 import (
-	"fmt"
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"time"
 
 	"github.com/janithl/citylyf/internal/utils"
@@ -22,11 +21,13 @@ const ( // Constants for economic behavior
 
 type MarketHistory struct { // tracking last 12 months data
 	MarketValue, InflationRate, InterestRate, MarketGrowthRate, MarketSentiment, CompanyProfits, AverageRent []float64
+	CompanyClosures, Layoffs                                                                                 []float64
+	BusinessInvestment, CorporateDebt                                                                        []float64
+	AverageHousePrice                                                                                        []float64
 }
 
 // Market tracks economic cycles and financial conditions
 type Market struct {
-	NextRateRevision            time.Time
 	History                     MarketHistory
 	MonthsOfNegativeGrowth      int
 	InRecession, InBoom         bool
@@ -46,35 +47,37 @@ func (m *Market) MarketValue() float64 {
 }
 
 // MarketSentiment adjusts sentiment based on boom/bust cycles
-func (m *Market) MarketSentiment() float64 {
-	baseSentiment := (rand.Float64() * 4) - 2 // Random factor (-2% to +2%)
+func (m *Market) MarketSentiment(rng *rand.Rand, bank *Bank) float64 {
+	baseSentiment := (rng.Float64() * 4) - 2 // Random factor (-2% to +2%)
 
 	if m.InRecession { // Modify Sentiment Based on Boom/Bust Cycle
-		baseSentiment -= (rand.Float64() * 2) // Negative bias (-0% to -2% extra)
+		baseSentiment -= (rng.Float64() * 2) // Negative bias (-0% to -2% extra)
 	} else if m.InBoom {
-		baseSentiment += (rand.Float64() * 2) // Positive bias (+0% to +2% extra)
+		baseSentiment += (rng.Float64() * 2) // Positive bias (+0% to +2% extra)
 	}
 
+	baseSentiment += bank.Confidence() // defaults at the bank shake confidence
+
 	baseSentiment = utils.Clamp(baseSentiment, -3, 3) // Clamp sentiment to a reasonable range**
 	m.History.MarketSentiment = utils.AddFifo(m.History.MarketSentiment, baseSentiment, 10)
 	return baseSentiment
 }
 
 // SupplyShock applies supply-chain disruptions (0% - 3%)
-func (m *Market) SupplyShock() float64 {
-	return rand.Float64() * 3
+func (m *Market) SupplyShock(rng *rand.Rand) float64 {
+	return rng.Float64() * 3
 }
 
 // MoneySupplyGrowth calculates money supply changes
-func (m *Market) MoneySupplyGrowth() float64 {
+func (m *Market) MoneySupplyGrowth(sim *Simulation) float64 {
 	interestImpact := -math.Pow(m.InterestRate()/5, 1.2)           // High rates slow money supply
 	inflationImpact := -math.Pow((m.InflationRate()-2)/4, 2)       // High inflation slows supply
-	spendingImpact := Sim.Government.GetGovernmentSpending() * 0.5 // More spending increases supply
-	confidenceImpact := m.MarketSentiment() * 0.3                  // Market sentiment effect
+	spendingImpact := sim.Government.GetGovernmentSpending() * 0.5 // More spending increases supply
+	confidenceImpact := m.MarketSentiment(sim.rng, sim.Bank) * 0.3 // Market sentiment effect
 
 	// Wage and rent growth impacts to money supply
-	wageGrowth := Sim.People.AverageWageGrowthRate() / 100
-	rentGrowth := Sim.Houses.AverageRentGrowthRate() / 100
+	wageGrowth := sim.People.AverageWageGrowthRate() / 100
+	rentGrowth := sim.Houses.AverageRentGrowthRate(m) / 100
 	wageImpact := math.Min(0.3*wageGrowth, 2.0)                // Higher wages increase money supply
 	rentImpact := math.Min(-0.2*(rentGrowth-wageGrowth), -2.0) // If rents grow faster than wages, money supply contracts
 
@@ -84,13 +87,13 @@ func (m *Market) MoneySupplyGrowth() float64 {
 }
 
 // CalculateInflation calculates inflation considering money supply, interest rates, and supply shocks
-func (m *Market) CalculateInflation(populationGrowth float64) {
-	moneyImpact := math.Log(m.MoneySupplyGrowth()+1) * 1.5                  // More money = higher inflation
-	interestImpact := -math.Pow(m.InterestRate()/3, 1.5)                    // Higher rates reduce inflation
-	demandImpact := math.Max(populationGrowth*0.5, 0.0)                     // Higher demand pushes inflation up
-	supplyImpact := m.SupplyShock() * 1.2                                   // Supply disruptions worsen inflation
-	wageImpact := math.Min(0.4*Sim.People.AverageWageGrowthRate()/100, 2.5) // Wages rising faster than productivity = inflation
-	rentImpact := math.Min(0.3*Sim.Houses.AverageRentGrowthRate()/100, 2.0) // Rising rents increase CPI, especially housing costs)
+func (m *Market) CalculateInflation(sim *Simulation, populationGrowth float64) {
+	moneyImpact := math.Log(m.MoneySupplyGrowth(sim)+1) * 1.5                // More money = higher inflation
+	interestImpact := -math.Pow(m.InterestRate()/3, 1.5)                     // Higher rates reduce inflation
+	demandImpact := math.Max(populationGrowth*0.5, 0.0)                      // Higher demand pushes inflation up
+	supplyImpact := m.SupplyShock(sim.rng) * 1.2                             // Supply disruptions worsen inflation
+	wageImpact := math.Min(0.4*sim.People.AverageWageGrowthRate()/100, 2.5)  // Wages rising faster than productivity = inflation
+	rentImpact := math.Min(0.3*sim.Houses.AverageRentGrowthRate(m)/100, 2.0) // Rising rents increase CPI, especially housing costs)
 	totalInflation := BaseInflation + moneyImpact + interestImpact + demandImpact + supplyImpact + wageImpact + rentImpact
 
 	totalInflation = utils.Clamp(totalInflation, -1, 15) // Cap deflation at -1% and hyperinflation at 15%
@@ -98,13 +101,13 @@ func (m *Market) CalculateInflation(populationGrowth float64) {
 }
 
 // CalculateMarketGrowth calculates stock index growth with boom/bust cycle logic
-func (m *Market) CalculateMarketGrowth() float64 {
+func (m *Market) CalculateMarketGrowth(sim *Simulation) float64 {
 	lastMarketGrowthRate := utils.GetLastValue(m.History.MarketGrowthRate)
 
 	interestImpact := -math.Pow(m.InterestRate()/8, 2)                     // Higher rates slow growth
 	inflationImpact := -math.Pow((m.InflationRate()-5)/3, 2)               // Inflation impact (good at 3-5%, bad above 6%)
-	unemploymentImpact := -Sim.People.UnemploymentRate() / 25              // High unemployment reduces spending
-	taxImpact := -(Sim.Government.CorporateTaxRate) / 30                   // Higher taxes = lower market growth
+	unemploymentImpact := -sim.People.UnemploymentRate() / 25              // High unemployment reduces spending
+	taxImpact := -(sim.Government.CorporateTaxRate) / 30                   // Higher taxes = lower market growth
 	marketSentimentImpact := utils.GetLastValue(m.History.MarketSentiment) // External random factors
 	profitImpact := m.calculateProfitImpact()                              // Effect of corporate profits
 
@@ -129,10 +132,10 @@ func (m *Market) CalculateMarketGrowth() float64 {
 
 	cycleImpact := 0.0
 	if m.InRecession {
-		cycleImpact = -1.0 + (rand.Float64() * 1.5) // Mild drag with jitter
+		cycleImpact = -1.0 + (sim.rng.Float64() * 1.5) // Mild drag with jitter
 	}
 	if m.InBoom {
-		cycleImpact = 2.5 + (rand.Float64() * 1.0) // Strong boost with jitter
+		cycleImpact = 2.5 + (sim.rng.Float64() * 1.0) // Strong boost with jitter
 	}
 
 	longTermCorrection := (BaseMarketGrowth - lastMarketGrowthRate) * 0.1 // correction to avoid market collapse
@@ -180,10 +183,23 @@ func (m *Market) ReportCompanyProfits(profits float64) {
 	m.History.CompanyProfits = utils.AddFifo(m.History.CompanyProfits, profits, 10)
 }
 
-// ReviseInterestRate updates interest rate based on inflation
-func (m *Market) ReviseInterestRate() {
-	if Sim.Date.Before(m.NextRateRevision) || len(m.History.InflationRate) < 3 {
-		return // rate revisions only happen once a quarter + we need historical inflation data to do a rates revision
+// ReportCompanyClosures records the number of companies that closed and employees that were laid off
+// in the last month
+func (m *Market) ReportCompanyClosures(closures, layoffs int) {
+	m.History.CompanyClosures = utils.AddFifo(m.History.CompanyClosures, float64(closures), 10)
+	m.History.Layoffs = utils.AddFifo(m.History.Layoffs, float64(layoffs), 10)
+}
+
+// ReportBusinessInvestment records what companies invested in the last month, and what they owe
+func (m *Market) ReportBusinessInvestment(investment, debt float64) {
+	m.History.BusinessInvestment = utils.AddFifo(m.History.BusinessInvestment, investment, 10)
+	m.History.CorporateDebt = utils.AddFifo(m.History.CorporateDebt, debt, 10)
+}
+
+// ReviseInterestRate updates interest rate based on inflation, and runs quarterly
+func (m *Market) ReviseInterestRate(sim *Simulation) {
+	if len(m.History.InflationRate) < 3 {
+		return // we need historical inflation data to do a rates revision
 	}
 	averageInflationRate := 0.0
 	for i := len(m.History.InflationRate) - 3; i < len(m.History.InflationRate); i++ {
@@ -223,28 +239,25 @@ func (m *Market) ReviseInterestRate() {
 	}
 
 	m.History.InterestRate = utils.AddFifo(m.History.InterestRate, newInterestRate, 20)
-	m.NextRateRevision = Sim.Date.AddDate(0, 3, 0) // next rate revision in 3 months
-
-	if interestRateChange > 0 {
-		fmt.Printf("[ Rate ] Avg. inflation at %.2f%%, above target range. Interest rate raised by %.2f%% to", averageInflationRate, interestRateChange)
-	} else if interestRateChange < 0 {
-		fmt.Printf("[ Rate ] Avg. inflation at %.2f%%, within or below target range. Interest rate lowered by %.2f%% to", averageInflationRate, interestRateChange)
-	} else {
-		fmt.Printf("[ Rate ] Avg. inflation at %.2f%%, within the target range. Interest rates held steady at", averageInflationRate)
-	}
-	fmt.Printf(" %.2f%%. Next rates revision on %s\n", newInterestRate, m.NextRateRevision.Format("2006-01-02"))
+
+	sim.Events().Publish(RateRevised{
+		AverageInflation: averageInflationRate,
+		Change:           interestRateChange,
+		NewRate:          newInterestRate,
+		NextRevision:     time.Date(sim.Date.Year(), (sim.Date.Month()-1)/3*3+4, 1, 0, 0, 0, 0, time.UTC), // rates are revised every quarter
+	})
 }
 
-func (m *Market) CalculateHousingAndRetailDemand(totalHouses, vacantHouses int) {
+func (m *Market) CalculateHousingAndRetailDemand(sim *Simulation, totalHouses, vacantHouses int) {
 	housingDemand, retailDemand := 0.5, 0.5 // Neutral starting point
 
 	// **Housing Demand Factors**
-	populationGrowth := Sim.People.PopulationGrowthRate()            // Higher population growth increases housing demand
-	unemploymentRate := Sim.People.UnemploymentRate()                // Higher unemployment reduces ability to afford housing
-	incomeGrowth := Sim.Market.CalculateMarketGrowth() * 0.2         // Higher market growth generally leads to better wages
-	interestRate := Sim.Market.InterestRate()                        // Higher rates make mortgages more expensive
+	populationGrowth := sim.People.PopulationGrowthRate()            // Higher population growth increases housing demand
+	unemploymentRate := sim.People.UnemploymentRate()                // Higher unemployment reduces ability to afford housing
+	incomeGrowth := sim.Market.CalculateMarketGrowth(sim) * 0.2      // Higher market growth generally leads to better wages
+	interestRate := sim.Market.InterestRate()                        // Higher rates make mortgages more expensive
 	vacancyImpact := float64(vacantHouses) / float64(totalHouses+1)  // Housing Availability Impact, +1 prevents div by zero
-	minDemandBoost := 1.0 / (1.0 + float64(Sim.People.Population())) // Creates demand when population is near 0
+	minDemandBoost := 1.0 / (1.0 + float64(sim.People.Population())) // Creates demand when population is near 0
 
 	housingDemand += (populationGrowth * 2)  // Direct impact of growth
 	housingDemand -= (unemploymentRate / 20) // Unemployment suppresses demand
@@ -254,11 +267,11 @@ func (m *Market) CalculateHousingAndRetailDemand(totalHouses, vacantHouses int)
 	housingDemand += minDemandBoost          // Ensures some demand at low population
 
 	// **Retail Demand Factors**
-	disposableIncome := Sim.People.AverageMonthlyDisposableIncome()     // Higher disposable income increases retail demand
-	consumerConfidence := utils.GetLastValue(m.History.MarketSentiment) // Market sentiment affects spending habits
-	taxImpact := -Sim.Government.SalesTaxRate / 20                      // Higher sales tax slightly reduces demand
-	unemploymentImpact := -(unemploymentRate / 15)                      // Unemployment reduces disposable income
-	profitImpact := m.calculateProfitImpact()                           // Stronger corporate profits usually reflect strong consumer demand
+	disposableIncome := sim.People.AverageMonthlyDisposableIncome(sim.Date) // Higher disposable income increases retail demand
+	consumerConfidence := utils.GetLastValue(m.History.MarketSentiment)     // Market sentiment affects spending habits
+	taxImpact := -sim.Government.SalesTaxRate / 20                          // Higher sales tax slightly reduces demand
+	unemploymentImpact := -(unemploymentRate / 15)                          // Unemployment reduces disposable income
+	profitImpact := m.calculateProfitImpact()                               // Stronger corporate profits usually reflect strong consumer demand
 
 	retailDemand += float64(disposableIncome / 50000) // Normalize disposable income impact
 	retailDemand += (consumerConfidence / 10)         // Positive market sentiment encourages spending
@@ -269,45 +282,45 @@ func (m *Market) CalculateHousingAndRetailDemand(totalHouses, vacantHouses int)
 	// Clamp values between 0 and 1
 	m.HousingDemand = utils.Clamp(housingDemand, 0, 1)
 	m.RetailDemand = utils.Clamp(retailDemand, 0, 1)
-	fmt.Printf("[ Econ ] Housing demand is at %.2f and Retail demand is at %.2f\n", m.HousingDemand, m.RetailDemand)
+	sim.Events().Publish(DemandCalculated{HousingDemand: m.HousingDemand, RetailDemand: m.RetailDemand})
 }
 
 // CalculateGDP computes total GDP from wages, business profits, and government spending.
-func (m *Market) CalculateGDP() float64 {
+func (m *Market) CalculateGDP(sim *Simulation) float64 {
 	totalPersonalIncome := 0.0
-	for _, person := range Sim.People.People {
+	for _, person := range sim.People.People {
 		totalPersonalIncome += float64(person.CurrentIncome())
 	}
 
 	totalProfits := 0.0
-	for _, company := range Sim.Companies {
-		totalProfits += company.LastProfit
+	for _, id := range sim.Companies.GetIDs() {
+		totalProfits += sim.Companies[id].LastProfit
 	}
 
 	// get government spending (in millions) and multiply by a million
-	totalGovernmentSpending := Sim.Government.GetGovernmentSpending() * 1e6
+	totalGovernmentSpending := sim.Government.GetGovernmentSpending() * 1e6
 
 	gdp := totalPersonalIncome + totalProfits + totalGovernmentSpending
 	return gdp
 }
 
 // CalculatePerCapitaGDP computes GDP per person.
-func (m *Market) CalculatePerCapitaGDP() float64 {
-	population := float64(len(Sim.People.People))
+func (m *Market) CalculatePerCapitaGDP(sim *Simulation) float64 {
+	population := float64(len(sim.People.People))
 	if population == 0 {
 		return 0 // Prevent division by zero
 	}
 
-	return m.CalculateGDP() / population
+	return m.CalculateGDP(sim) / population
 }
 
 // CalculateTaxToGDPRatio computes tax revenue as a percentage of GDP.
-func (m *Market) CalculateTaxToGDPRatio() float64 {
-	gdp := m.CalculateGDP()
+func (m *Market) CalculateTaxToGDPRatio(sim *Simulation) float64 {
+	gdp := m.CalculateGDP(sim)
 	if gdp == 0 {
 		return 0 // Prevent division by zero
 	}
 
-	totalTaxes := float64(utils.GetLastValue(Sim.Government.IncomeValues))
+	totalTaxes := float64(utils.GetLastValue(sim.Government.IncomeValues))
 	return (totalTaxes / gdp) * 100
 }
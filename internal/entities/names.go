@@ -1,26 +1,41 @@
 package entities
 
 import (
+	_ "embed"
+	"encoding/json"
+	"log"
 	"math/rand/v2"
 	"slices"
 
-	"github.com/janithl/citylyf/internal/ui/assets"
 	"github.com/janithl/citylyf/internal/utils"
 )
 
+//go:embed names.json
+var namesJSON []byte
+
+// names holds the name lists loaded from names.json
+var names map[string][]string
+
+func init() {
+	if err := json.Unmarshal(namesJSON, &names); err != nil {
+		log.Fatal("Failed to parse names JSON:", err)
+	}
+}
+
 type NameService struct {
 	LastTenNames     []string
 	LastTenFamilies  []string
 	LastTenCompanies []string
 	LastTenPlaces    []string
+	rng              *rand.Rand
 }
 
 func (ns *NameService) getFamilyName() string {
 	familyName := ""
 	for familyName == "" || slices.Contains(ns.LastTenFamilies, familyName) { // prevent repeating names
-		familyNames, exists := assets.Assets.Names["familyNames"]
+		familyNames, exists := names["familyNames"]
 		if exists {
-			familyName = familyNames[rand.IntN(len(familyNames))]
+			familyName = familyNames[ns.rng.IntN(len(familyNames))]
 		}
 	}
 	ns.LastTenFamilies = utils.AddFifo(ns.LastTenFamilies, familyName, 10)
@@ -29,10 +44,10 @@ func (ns *NameService) getFamilyName() string {
 
 func (ns *NameService) GetPlaceName() string {
 	placeName := ""
-	placeNames, exists := assets.Assets.Names["placeNames"]
+	placeNames, exists := names["placeNames"]
 	for placeName == "" || slices.Contains(ns.LastTenPlaces, placeName) { // prevent repeating names
 		if exists {
-			placeName = placeNames[rand.IntN(len(placeNames))]
+			placeName = placeNames[ns.rng.IntN(len(placeNames))]
 		} else {
 			return ""
 		}
@@ -45,12 +60,12 @@ func (ns *NameService) GetRoadName() string {
 	roadName := ""
 	suffix := ""
 
-	roadSuffixes, exists := assets.Assets.Names["roadSuffixes"]
+	roadSuffixes, exists := names["roadSuffixes"]
 	if exists {
-		suffix = roadSuffixes[rand.IntN(len(roadSuffixes))]
+		suffix = roadSuffixes[ns.rng.IntN(len(roadSuffixes))]
 	}
 
-	randomNumber := rand.Float32()
+	randomNumber := ns.rng.Float32()
 	switch {
 	case randomNumber < 0.7: // 70% of roads have place names
 		roadName = ns.GetPlaceName()
@@ -66,26 +81,26 @@ func (ns *NameService) GetPersonName(gender Gender) (string, string) {
 	for firstName == "" || slices.Contains(ns.LastTenNames, firstName) { // prevent repeating names
 		switch gender {
 		case Male:
-			maleNames, exists := assets.Assets.Names["maleNames"]
+			maleNames, exists := names["maleNames"]
 			if exists {
-				firstName = maleNames[rand.IntN(len(maleNames))]
+				firstName = maleNames[ns.rng.IntN(len(maleNames))]
 			}
 		case Female:
-			femaleNames, exists := assets.Assets.Names["femaleNames"]
+			femaleNames, exists := names["femaleNames"]
 			if exists {
-				firstName = femaleNames[rand.IntN(len(femaleNames))]
+				firstName = femaleNames[ns.rng.IntN(len(femaleNames))]
 			}
 		default:
-			otherNames, exists := assets.Assets.Names["otherNames"]
+			otherNames, exists := names["otherNames"]
 			if exists {
-				firstName = otherNames[rand.IntN(len(otherNames))]
+				firstName = otherNames[ns.rng.IntN(len(otherNames))]
 			}
 		}
 	}
 
 	ns.LastTenNames = utils.AddFifo(ns.LastTenNames, firstName, 10)
 
-	if rand.Float32() < 0.1 { // 10% of surnames are double‑barrelled
+	if ns.rng.Float32() < 0.1 { // 10% of surnames are double‑barrelled
 		return firstName, ns.getFamilyName() + "-" + ns.getFamilyName()
 	}
 
@@ -96,20 +111,20 @@ func (ns *NameService) GetCompanyName() string {
 	companyName := ""
 	suffix := ""
 
-	companySuffixes, exists := assets.Assets.Names["companySuffixes"]
+	companySuffixes, exists := names["companySuffixes"]
 	if exists {
-		suffix = companySuffixes[rand.IntN(len(companySuffixes))]
+		suffix = companySuffixes[ns.rng.IntN(len(companySuffixes))]
 	} else {
 		return ""
 	}
 
-	companyNames, exists := assets.Assets.Names["companyNames"]
+	companyNames, exists := names["companyNames"]
 	for companyName == "" || slices.Contains(ns.LastTenCompanies, companyName) { // prevent repeating names
-		randomNumber := rand.Float32()
+		randomNumber := ns.rng.Float32()
 		switch {
 		case randomNumber < 0.65: // 65% are generic company names
 			if exists {
-				companyName = companyNames[rand.IntN(len(companyNames))]
+				companyName = companyNames[ns.rng.IntN(len(companyNames))]
 			} else {
 				return ""
 			}
@@ -124,11 +139,12 @@ func (ns *NameService) GetCompanyName() string {
 	return companyName + " " + suffix
 }
 
-func NewNameService() *NameService {
+func NewNameService(rng *rand.Rand) *NameService {
 	return &NameService{
 		LastTenNames:     make([]string, 10),
 		LastTenFamilies:  make([]string, 10),
 		LastTenCompanies: make([]string, 10),
 		LastTenPlaces:    make([]string, 10),
+		rng:              rng,
 	}
 }
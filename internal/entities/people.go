@@ -4,6 +4,7 @@ import (
 	"maps"
 	"math"
 	"slices"
+	"time"
 
 	"github.com/janithl/citylyf/internal/utils"
 )
@@ -69,6 +70,16 @@ func (p *People) RemovePerson(personID int) {
 	delete(p.People, personID)
 }
 
+// GetPersonIDs returns a sorted list of person IDs
+func (p *People) GetPersonIDs() []int {
+	IDs := []int{}
+	for person := range maps.Values(p.People) {
+		IDs = append(IDs, person.ID)
+	}
+	slices.Sort(IDs)
+	return IDs
+}
+
 // GetHouseholdIDs returns a sorted list of household IDs
 func (p *People) GetHouseholdIDs() []int {
 	IDs := []int{}
@@ -99,7 +110,7 @@ func (p *People) GetSpouse(personID int) *Person {
 	for _, memberID := range household.MemberIDs {
 		if memberID != personID {
 			person := p.GetPerson(memberID)
-			if person.Relationship == Married {
+			if person != nil && person.Relationship == Married {
 				return person
 			}
 		}
@@ -109,10 +120,10 @@ func (p *People) GetSpouse(personID int) *Person {
 }
 
 // calculate the unemployed and the total labour force
-func (p *People) CalculateUnemployment() {
+func (p *People) CalculateUnemployment(now time.Time) {
 	labourforce, unemployed := 0, 0
 	for _, person := range p.People {
-		if person.IsEmployable() {
+		if person.IsEmployable(now) {
 			labourforce += 1
 			if !person.IsEmployed() {
 				unemployed += 1
@@ -130,14 +141,14 @@ func (p *People) UpdatePopulationValues() {
 }
 
 // calculate the age groups of the population
-func (p *People) CalculateAgeGroups() {
+func (p *People) CalculateAgeGroups(now time.Time) {
 	groups := make(map[int]AgeGroup)
 	for i := 0; i < 120; i += AgeGroupSize {
 		groups[i] = AgeGroup{}
 	}
 
 	for _, person := range p.People {
-		ageGroup := AgeGroupSize * (person.Age() / AgeGroupSize)
+		ageGroup := AgeGroupSize * (person.Age(now) / AgeGroupSize)
 		if group, ok := groups[ageGroup]; ok {
 			switch person.Gender {
 			case Male:
@@ -154,14 +165,15 @@ func (p *People) CalculateAgeGroups() {
 }
 
 // AverageMonthlyDisposableIncome returns the monthly disposable income very household has
-func (p *People) AverageMonthlyDisposableIncome() int {
+func (p *People) AverageMonthlyDisposableIncome(now time.Time) int {
 	if len(p.Households) == 0 {
 		return 0 // Avoid division by zero
 	}
 
 	totalDisposableIncome := 0.0
-	for _, household := range p.Households {
-		disposable := float64(household.AnnualIncome(false))/12.0 - float64(household.LastMonthExpenses)
+	for _, id := range p.GetHouseholdIDs() {
+		household := p.Households[id]
+		disposable := float64(household.AnnualIncome(p, now, false))/12.0 - float64(household.LastMonthExpenses)
 		if disposable < 0 {
 			disposable = 0
 		}
@@ -44,13 +44,13 @@ type Person struct {
 	Relationship          RelationshipStatus
 }
 
-func (p *Person) Age() int {
-	duration := Sim.Date.Sub(p.Birthdate)
+func (p *Person) Age(now time.Time) int {
+	duration := now.Sub(p.Birthdate)
 	return int(duration.Hours() / HoursPerYear)
 }
 
-func (p *Person) IsEmployable() bool {
-	return p.Age() >= AgeOfAdulthood && p.CareerLevel != Retired
+func (p *Person) IsEmployable(now time.Time) bool {
+	return p.Age(now) >= AgeOfAdulthood && p.CareerLevel != Retired
 }
 
 func (p *Person) IsEmployed() bool {
@@ -64,6 +64,6 @@ func (p *Person) CurrentIncome() int {
 	return 0
 }
 
-func (p *Person) String() string {
-	return fmt.Sprintf("%-20s%-20s%3d (%4d) %6s %10s %15s %20s %25s %5d %10d/yearly", p.FirstName, p.FamilyName, p.Age(), p.Birthdate.Year(), p.Gender, p.Relationship, p.EducationLevel, p.CareerLevel, p.Occupation, p.EmployerID, p.AnnualIncome)
+func (p *Person) GetStats(now time.Time) string {
+	return fmt.Sprintf("%-20s%-20s%3d (%4d) %6s %10s %15s %20s %25s %5d %10d/yearly", p.FirstName, p.FamilyName, p.Age(now), p.Birthdate.Year(), p.Gender, p.Relationship, p.EducationLevel, p.CareerLevel, p.Occupation, p.EmployerID, p.AnnualIncome)
 }
@@ -24,12 +24,12 @@ type Region struct {
 	Size, Population, Shops, Jobs int
 }
 
-func (r *Region) GetRegionalRoad() *Point {
-	tiles := Sim.Geography.GetTiles()
+func (r *Region) GetRegionalRoad(g *Geography) *Point {
+	tiles := g.GetTiles()
 	centre := Point{X: r.Start.X + r.Size/2, Y: r.Start.Y + r.Size/2}
 	for d := range r.Size / 2 {
 		for _, neighbour := range centre.GetNeighbours(d, false) {
-			if Sim.Geography.BoundsCheck(neighbour.X, neighbour.Y) && tiles[neighbour.X][neighbour.Y].LandUse == TransportUse {
+			if g.BoundsCheck(neighbour.X, neighbour.Y) && tiles[neighbour.X][neighbour.Y].LandUse == TransportUse {
 				return neighbour
 			}
 		}
@@ -37,18 +37,18 @@ func (r *Region) GetRegionalRoad() *Point {
 	return nil
 }
 
-func (r *Region) GetRegionalShops() []*Company {
+func (r *Region) GetRegionalShops(sim *Simulation) []*Company {
 	shops := []*Company{}
-	tiles := Sim.Geography.GetTiles()
+	tiles := sim.Geography.GetTiles()
 	for x := r.Start.X; x < r.Start.X+r.Size; x++ {
 		for y := r.Start.Y; y < r.Start.Y+r.Size; y++ {
-			if !Sim.Geography.BoundsCheck(x, y) {
+			if !sim.Geography.BoundsCheck(x, y) {
 				continue
 			}
 
 			switch {
 			case tiles[x][y].LandUse == RetailUse:
-				company := Sim.Companies.GetLocationCompany(x, y)
+				company := sim.Companies.GetLocationCompany(x, y)
 				if company != nil {
 					shops = append(shops, company)
 				}
@@ -60,29 +60,34 @@ func (r *Region) GetRegionalShops() []*Company {
 
 type Regions []*Region
 
-func (r Regions) CalculateRegionalStats() {
-	tiles := Sim.Geography.GetTiles()
+func (r Regions) CalculateRegionalStats(sim *Simulation) {
+	tiles := sim.Geography.GetTiles()
 	for _, region := range r {
 		region.Shops = 0
 		region.Jobs = 0
 		region.Population = 0
 		for x := region.Start.X; x < region.Start.X+region.Size; x++ {
 			for y := region.Start.Y; y < region.Start.Y+region.Size; y++ {
-				if !Sim.Geography.BoundsCheck(x, y) {
+				if !sim.Geography.BoundsCheck(x, y) {
 					continue
 				}
 
 				switch {
 				case tiles[x][y].LandUse == RetailUse:
 					region.Shops += 1 // TODO: Check if shop is active
-					company := Sim.Companies.GetLocationCompany(x, y)
+					company := sim.Companies.GetLocationCompany(x, y)
+					if company != nil {
+						region.Jobs += company.GetNumberOfEmployees()
+					}
+				case tiles[x][y].LandUse == CommercialUse || tiles[x][y].LandUse == IndustrialUse:
+					company := sim.Companies.GetLocationCompany(x, y)
 					if company != nil {
 						region.Jobs += company.GetNumberOfEmployees()
 					}
 				case tiles[x][y].LandUse == ResidentialUse:
-					house := Sim.Houses.GetLocationHouse(x, y)
+					house := sim.Houses.GetLocationHouse(x, y)
 					if house != nil && house.HouseholdID != 0 {
-						household, exists := Sim.People.Households[house.HouseholdID]
+						household, exists := sim.People.Households[house.HouseholdID]
 						if exists {
 							region.Population += household.Size()
 						}
@@ -91,23 +96,23 @@ func (r Regions) CalculateRegionalStats() {
 			}
 		}
 	}
-	r.CalculateRegionalTraffic()
-	r.CalculateRegionalSales()
+	r.CalculateRegionalTraffic(sim.Geography)
+	r.CalculateRegionalSales(sim)
 }
 
-func (r Regions) CalculateRegionalTraffic() {
+func (r Regions) CalculateRegionalTraffic(g *Geography) {
 	for _, r1 := range r {
 		r1.Trips = []*Trip{}
 		for _, r2 := range r {
 			if r1.ID == r2.ID {
 				continue // Ignore same-region trips
 			}
-			r1road, r2road := r1.GetRegionalRoad(), r2.GetRegionalRoad()
+			r1road, r2road := r1.GetRegionalRoad(g), r2.GetRegionalRoad(g)
 			if r1road == nil || r2road == nil {
 				continue
 			}
 
-			path := Sim.Geography.FindPath(r1road, r2road)
+			path := g.FindPath(r1road, r2road)
 			if path == nil {
 				continue
 			}
@@ -127,25 +132,25 @@ func (r Regions) CalculateRegionalTraffic() {
 	}
 }
 
-func (r Regions) CalculateRegionalSales() {
+func (r Regions) CalculateRegionalSales(sim *Simulation) {
 	for _, r1 := range r {
 		if r1.Shops == 0 { // check if there are shops
 			continue
 		}
 
 		population := float64(r1.Population)
-		avgIncome := Sim.People.AverageMonthlyDisposableIncome()
-		unemploymentRate := Sim.People.UnemploymentRate()
-		consumerConfidence := utils.GetLastValue(Sim.Market.History.MarketSentiment)
-		taxImpact := -Sim.Government.SalesTaxRate / 20
-		inflationImpact := -math.Pow((Sim.Market.InflationRate()-5)/3, 2)
+		avgIncome := sim.People.AverageMonthlyDisposableIncome(sim.Date)
+		unemploymentRate := sim.People.UnemploymentRate()
+		consumerConfidence := utils.GetLastValue(sim.Market.History.MarketSentiment)
+		taxImpact := -sim.Government.SalesTaxRate / 20
+		inflationImpact := -math.Pow((sim.Market.InflationRate()-5)/3, 2)
 
 		// Base spending power calculation
 		effectiveSpendingPower := float64(avgIncome) * (1 - unemploymentRate/1000) * (1 + consumerConfidence/10)
 		effectiveSpendingPower = math.Max(0, effectiveSpendingPower) // Prevent negative values
 
 		// Total demand within region
-		regionRetailDemand := Sim.Market.RetailDemand * population * effectiveSpendingPower
+		regionRetailDemand := sim.Market.RetailDemand * population * effectiveSpendingPower
 		regionRetailDemand *= (1 + taxImpact + inflationImpact) // Adjust for macroeconomic factors
 
 		// Add demand from outside the region
@@ -171,7 +176,7 @@ func (r Regions) CalculateRegionalSales() {
 		avgSalesPerShop := totalRetailDemand / float64(r1.Shops)
 
 		// Distribute sales among shops based on shop productivity
-		for _, shop := range r1.GetRegionalShops() {
+		for _, shop := range r1.GetRegionalShops(sim) {
 			shop.RetailSales = shop.GetProductivity() * avgSalesPerShop
 		}
 	}
@@ -1,7 +1,7 @@
 package entities
 
 import (
-	"math/rand"
+	"math/rand/v2"
 )
 
 // RelationshipStatus defines if the person is married etc.
@@ -14,12 +14,12 @@ const (
 	Widowed  RelationshipStatus = "Widowed"
 )
 
-func GetRelationshipStatus(age int) RelationshipStatus {
+func GetRelationshipStatus(rng *rand.Rand, age int) RelationshipStatus {
 	if age < AgeOfAdulthood {
 		return Single
 	}
 
-	randomRelationship := rand.Intn(100)
+	randomRelationship := rng.IntN(100)
 	var status RelationshipStatus
 	switch {
 	case randomRelationship < 45:
@@ -1,8 +1,6 @@
 package entities
 
 import (
-	"fmt"
-
 	"github.com/janithl/citylyf/internal/utils"
 )
 
@@ -70,17 +68,17 @@ func (r *Road) AddSegments(segments []Segment, start bool) {
 	}
 }
 
-func PlaceRoad(start, end Point, roadType RoadType) {
+func PlaceRoad(sim *Simulation, start, end Point, roadType RoadType) {
 	if start.X == end.Y && start.Y == end.Y {
 		return
 	}
 
 	var road *Road
 	var roadStart bool
-	if r, index := Sim.Geography.GetRoadByStartEnd(roadType, start.X, start.Y); r != nil {
+	if r, index := sim.Geography.GetRoadByStartEnd(roadType, start.X, start.Y); r != nil {
 		road = r
 		roadStart = index == 0
-	} else if r, index := Sim.Geography.GetRoadByStartEnd(roadType, end.X, end.Y); r != nil {
+	} else if r, index := sim.Geography.GetRoadByStartEnd(roadType, end.X, end.Y); r != nil {
 		road = r
 		roadStart = index == 0
 	}
@@ -123,18 +121,18 @@ func PlaceRoad(start, end Point, roadType RoadType) {
 		roadLength = road.GetLength() - oldLength
 		roadType = road.Type
 
-		Sim.Geography.placeRoadSegments(segments)
-		fmt.Printf("[ Road ] %s extended!\n", road.Name)
+		sim.Geography.placeRoadSegments(segments)
+		sim.Events().Publish(RoadExtended{Name: road.Name, Type: road.Type})
 	} else {
 		road = &Road{
-			Name:     Sim.NameService.GetRoadName(),
+			Name:     sim.NameService.GetRoadName(),
 			Type:     roadType,
 			Segments: segments,
 		}
 
-		Sim.Geography.addRoad(road)
+		sim.Geography.addRoad(road)
 		roadLength = road.GetLength()
-		fmt.Printf("[ Road ] %s opened!\n", road.Name)
+		sim.Events().Publish(RoadOpened{Name: road.Name, Type: road.Type})
 	}
 
 	// track road cost
@@ -142,5 +140,5 @@ func PlaceRoad(start, end Point, roadType RoadType) {
 	if roadType == Asphalt {
 		roadCostType = AsphaltRoadConstruction
 	}
-	Sim.Government.AddCapEx(roadCostType, roadLength)
+	sim.Government.AddCapEx(roadCostType, roadLength)
 }
@@ -2,6 +2,8 @@ package entities
 
 import (
 	"fmt"
+	"log"
+	"math/rand/v2"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -17,6 +19,16 @@ const (
 	Slow  SimulationSpeed = 1600
 	Mid   SimulationSpeed = 400
 	Fast  SimulationSpeed = 100
+	Ultra SimulationSpeed = -1 // not a tick interval: the game loop runs as many days as fit between ticks, see IsRunningFlatOut
+)
+
+const MaxSnapshots = 12 // number of automatic snapshots kept to roll back to
+
+// terrain generation probabilities for new simulations
+const (
+	DefaultPeakProbability  = 0.0015
+	DefaultRangeProbability = 0.005
+	DefaultCliffProbability = 0.01
 )
 
 type Simulation struct {
@@ -28,20 +40,81 @@ type Simulation struct {
 	Houses          Housing
 	Companies       Companies
 	Market          *Market
+	Bank            *Bank
+	Landlord        *Landlord
+	Catalogue       *Catalogue // industries and jobs of the city, which don't change once it is founded
 	Geography       *Geography
 	tickNumber      int
 	lastID          atomic.Uint32
 	CityName        string
 	NameService     *NameService
+	Seed            uint64
+	Statistics      *Statistics
+	Goals           []*Goal // goals of the scenario the city was started from, if any
+	rngSource       *rand.PCG
+	rng             *rand.Rand
+	stats           *EventBus // publishes the latest stats
+	events          *EventBus
+	snapshots       *SnapshotHistory
+	journal         *Journal
+	skipFrom        time.Time
+	skipUntil       time.Time
+	playTime        time.Duration
 }
 
+// Tick moves the simulation on a day every few ticks at its speed. Simulations running flat out are
+// moved on by the game loop instead.
 func (s *Simulation) Tick(dailyActivity func()) {
+	if s.SimulationSpeed == Pause || s.IsRunningFlatOut() { // days aren't ticked by, see IsRunningFlatOut
+		return
+	}
 	s.tickNumber = (s.tickNumber + 100) % 1600
 	if s.tickNumber%int(s.SimulationSpeed) == 0 {
-		nextDate := s.Date.AddDate(0, 0, 1)
-		s.Date = nextDate
-		dailyActivity()
+		s.NextDay(dailyActivity)
+	}
+}
+
+// NextDay moves the simulation on by a day and runs the daily activity
+func (s *Simulation) NextDay(dailyActivity func()) {
+	s.Date = s.Date.AddDate(0, 0, 1)
+	dailyActivity()
+}
+
+// SkipAhead asks for the simulation to be run as fast as possible until the given date
+func (s *Simulation) SkipAhead(until time.Time) {
+	if !s.IsSkippingAhead() {
+		s.skipFrom = s.Date
 	}
+	s.skipUntil = until
+}
+
+// IsSkippingAhead returns true if the simulation is being run as fast as possible to a date
+func (s *Simulation) IsSkippingAhead() bool {
+	return s.Date.Before(s.skipUntil)
+}
+
+// IsRunningFlatOut returns true if the simulation should be run as fast as the CPU allows rather than
+// ticked, at Ultra speed or while skipping ahead
+func (s *Simulation) IsRunningFlatOut() bool {
+	return s.SimulationSpeed == Ultra || s.IsSkippingAhead()
+}
+
+// SkipAheadProgress returns how far along skipping ahead is, from 0 to 1, and the date being skipped to
+func (s *Simulation) SkipAheadProgress() (float64, time.Time) {
+	if !s.IsSkippingAhead() {
+		return 1, s.skipUntil
+	}
+	return float64(s.Date.Sub(s.skipFrom)) / float64(s.skipUntil.Sub(s.skipFrom)), s.skipUntil
+}
+
+// AddPlayTime adds to the time spent playing the city
+func (s *Simulation) AddPlayTime(d time.Duration) {
+	s.playTime += d
+}
+
+// PlayTime returns the time spent playing the city
+func (s *Simulation) PlayTime() time.Duration {
+	return s.playTime
 }
 
 func (s *Simulation) ChangeSimulationSpeed() {
@@ -51,6 +124,8 @@ func (s *Simulation) ChangeSimulationSpeed() {
 	case Mid:
 		s.SimulationSpeed = Fast
 	case Fast:
+		s.SimulationSpeed = Ultra
+	case Ultra:
 		s.SimulationSpeed = Pause
 	default:
 		s.SimulationSpeed = Slow
@@ -61,39 +136,146 @@ func (s *Simulation) PauseSimulation() {
 	s.SimulationSpeed = Pause
 }
 
-func (s *Simulation) GetStats() string {
+// Stats is a point-in-time snapshot of the headline figures of the simulation
+type Stats struct {
+	Date                      time.Time
+	Reserves                  float64
+	Population                int
+	PopulationGrowth          float64
+	Houses, FreeHouses        int
+	Unemployment              float64
+	Companies                 int
+	MarketValue, MarketGrowth float64
+	Inflation, InterestRate   float64
+}
+
+// GetStatsSnapshot returns the current headline figures of the simulation
+func (s *Simulation) GetStatsSnapshot() Stats {
+	return Stats{
+		Date:             s.Date,
+		Reserves:         s.Government.GetReservesAtHand(),
+		Population:       s.People.Population(),
+		PopulationGrowth: s.People.PopulationGrowthRate(),
+		Houses:           len(s.Houses),
+		FreeHouses:       s.Houses.GetFreeHouses(),
+		Unemployment:     s.People.UnemploymentRate(),
+		Companies:        len(s.Companies),
+		MarketValue:      s.Market.MarketValue(),
+		MarketGrowth:     utils.GetLastValue(s.Market.History.MarketGrowthRate),
+		Inflation:        s.Market.InflationRate(),
+		InterestRate:     s.Market.InterestRate(),
+	}
+}
+
+func (stats Stats) String() string {
 	return fmt.Sprintf("%s | Reserves: %s | Population: %d (%+06.2f%%) | Houses: %d (%d Free) | "+
 		"Unemployment: %05.2f%% | Companies: %d | Market Value: %.2f (%+06.2f%%) | Inflation: %05.2f%% | IntRate: %05.2f%%",
-		s.Date.Format("2006-01-02"), utils.FormatCurrency(s.Government.GetReservesAtHand(), "$"), s.People.Population(),
-		s.People.PopulationGrowthRate(), len(s.Houses), s.Houses.GetFreeHouses(), s.People.UnemploymentRate(),
-		len(s.Companies), s.Market.MarketValue(), utils.GetLastValue(s.Market.History.MarketGrowthRate),
-		s.Market.InflationRate(), s.Market.InterestRate())
+		stats.Date.Format("2006-01-02"), utils.FormatCurrency(stats.Reserves, "$"), stats.Population,
+		stats.PopulationGrowth, stats.Houses, stats.FreeHouses, stats.Unemployment,
+		stats.Companies, stats.MarketValue, stats.MarketGrowth, stats.Inflation, stats.InterestRate)
+}
+
+func (s *Simulation) GetStats() string {
+	return s.GetStatsSnapshot().String()
+}
+
+// Rand returns the simulation's random number generator, which every
+// part of the simulation should draw from to keep runs reproducible
+func (s *Simulation) Rand() *rand.Rand {
+	return s.rng
+}
+
+// GetRNGState returns the current state of the random number generator
+func (s *Simulation) GetRNGState() []byte {
+	state, err := s.rngSource.MarshalBinary()
+	if err != nil {
+		log.Println(err)
+	}
+	return state
+}
+
+// seedRNG sets up the random number generator from the seed, and restores
+// its state if one is given
+func (s *Simulation) seedRNG(state []byte) {
+	s.rngSource = rand.NewPCG(s.Seed, s.Seed^0x9e3779b97f4a7c15)
+	if len(state) > 0 {
+		if err := s.rngSource.UnmarshalBinary(state); err != nil {
+			log.Println(err)
+		}
+	}
+	s.rng = rand.New(s.rngSource)
 }
 
 func (s *Simulation) GetNextID() int {
 	return int(s.lastID.Add(1))
 }
 
+// SendStats publishes the latest stats to their subscribers
 func (s *Simulation) SendStats() {
-	select {
-	case SimStats <- s.GetStats():
-	default:
-	}
+	s.stats.Publish(s.GetStatsSnapshot())
+}
+
+// Events returns the bus that simulation events are published on
+func (s *Simulation) Events() *EventBus {
+	return s.events
+}
+
+// Snapshots returns the history of automatic snapshots the simulation can be rolled back to
+func (s *Simulation) Snapshots() *SnapshotHistory {
+	return s.snapshots
 }
 
+// StatsEvents returns the bus the latest stats are published on, kept apart from the simulation events
+// as they're sent on every tick
+func (s *Simulation) StatsEvents() *EventBus {
+	return s.stats
+}
+
+// RegenerateMap generates new terrain of the same size and elevations, with the given probabilities
 func (s *Simulation) RegenerateMap(peakProb, rangeProb, cliffProb float64) {
-	s.Geography = NewGeography(64, 8, 8, 3, 7, peakProb, rangeProb, cliffProb)
+	g := s.Geography
+	regionSize := DefaultMapSettings.RegionSize
+	if len(g.Regions) > 0 {
+		regionSize = g.Regions[0].Size
+	}
+	s.Geography = NewGeography(s.rng, g.Size, regionSize, g.MaxElevation, g.SeaLevel, g.HillLevel, peakProb, rangeProb, cliffProb)
+}
+
+// NewSeed returns a random seed for a new simulation
+func NewSeed() uint64 {
+	return rand.Uint64()
+}
+
+// MapSettings are the parameters the terrain map is generated with
+type MapSettings struct {
+	Size, RegionSize, MaxElevation, SeaLevel, HillLevel int
+	PeakProbability, RangeProbability, CliffProbability float64
+}
+
+// DefaultMapSettings are the map parameters of a new game
+var DefaultMapSettings = MapSettings{
+	Size:             64,
+	RegionSize:       8,
+	MaxElevation:     8,
+	SeaLevel:         3,
+	HillLevel:        7,
+	PeakProbability:  DefaultPeakProbability,
+	RangeProbability: DefaultRangeProbability,
+	CliffProbability: DefaultCliffProbability,
 }
 
-var Sim *Simulation
-var SimStats chan string
+func NewSimulation(startYear, governmentReserves int, seed uint64) *Simulation {
+	return NewSimulationWithMap(startYear, governmentReserves, seed, DefaultMapSettings)
+}
 
-func NewSimulation(startYear, governmentReserves int) *Simulation {
+// NewSimulationWithMap returns a new simulation with a map generated with the given settings
+func NewSimulationWithMap(startYear, governmentReserves int, seed uint64, m MapSettings) *Simulation {
 	startDate := time.Date(startYear, time.January, 1, 0, 0, 0, 0, time.UTC)
 	sim := &Simulation{
 		SimulationSpeed: Pause,
 		Date:            startDate,
-		Government:      NewGovernment(governmentReserves, startDate),
+		Seed:            seed,
+		Government:      NewGovernment(governmentReserves),
 		People: &People{
 			LabourForce:            0,
 			Unemployed:             0,
@@ -103,10 +285,10 @@ func NewSimulation(startYear, governmentReserves int) *Simulation {
 			People:                 make(map[int]*Person),
 			Households:             make(map[int]*Household),
 		},
-		Houses:    make(map[int]*House),
-		Companies: make(map[int]*Company),
+		Houses:     make(map[int]*House),
+		Companies:  make(map[int]*Company),
+		Statistics: NewStatistics(),
 		Market: &Market{
-			NextRateRevision:       startDate.AddDate(0, 3, 0),
 			MonthsOfNegativeGrowth: 0,
 			History: MarketHistory{
 				MarketValue:      []float64{1000},
@@ -118,25 +300,104 @@ func NewSimulation(startYear, governmentReserves int) *Simulation {
 				AverageRent:      []float64{0.0},
 			},
 		},
-		Geography:   NewGeography(64, 8, 8, 3, 7, 0.0015, 0.005, 0.01),
-		NameService: NewNameService(),
+		Bank:      NewBank(),
+		Landlord:  &Landlord{},
+		Catalogue: DefaultCatalogue(),
 	}
-	sim.lastID.Store(10000)         // start IDs at 10000
-	SimStats = make(chan string, 1) // create the stats channel
+	sim.seedRNG(nil)
+	sim.Geography = NewGeography(sim.rng, m.Size, m.RegionSize, m.MaxElevation, m.SeaLevel, m.HillLevel, m.PeakProbability, m.RangeProbability, m.CliffProbability)
+	sim.NameService = NewNameService(sim.rng)
+	sim.lastID.Store(10000) // start IDs at 10000
+	sim.stats = NewEventBus()
+	sim.events = NewEventBus()
+	sim.snapshots = NewSnapshotHistory(MaxSnapshots)
+	sim.journal = &Journal{Seed: sim.Seed}
 
 	return sim
 }
 
-func LoadSimulationFromSave(sim *Simulation, lastID uint32, tiles [][]Tile, roads []*Road) {
-	Sim = sim
-	Sim.lastID.Store(lastID)
+// SaveState is the unexported state of a simulation, which is stored alongside it in save files
+type SaveState struct {
+	LastID                                              uint32
+	RNGState                                            []byte
+	TickNumber                                          int
+	PeakProbability, RangeProbability, CliffProbability float64
+	Tiles                                               [][]Tile
+	Roads                                               []*Road
+	PlayTime                                            time.Duration
+}
 
-	Sim.Geography.tiles = tiles
-	Sim.Geography.roads = roads
-	SimStats = make(chan string, 1)
+// GetSaveState returns the unexported state of the simulation for saving
+func (s *Simulation) GetSaveState() SaveState {
+	return SaveState{
+		LastID:           s.lastID.Load(),
+		RNGState:         s.GetRNGState(),
+		TickNumber:       s.tickNumber,
+		PeakProbability:  s.Geography.peakProbability,
+		RangeProbability: s.Geography.rangeProbability,
+		CliffProbability: s.Geography.cliffProbability,
+		Tiles:            s.Geography.GetTiles(),
+		Roads:            s.Geography.GetRoads(),
+		PlayTime:         s.playTime,
+	}
 }
 
-func StartNewSim() {
-	Sim = NewSimulation(2020, 1000000)
-	Sim.SendStats()
+// LoadSimulationFromSave restores the unexported state of a simulation read from a save file
+func LoadSimulationFromSave(sim *Simulation, state SaveState) {
+	sim.lastID.Store(state.LastID)
+	sim.seedRNG(state.RNGState)
+	sim.NameService.rng = sim.rng
+	sim.tickNumber = state.TickNumber
+	sim.playTime = state.PlayTime
+
+	sim.Geography.peakProbability = state.PeakProbability
+	sim.Geography.rangeProbability = state.RangeProbability
+	sim.Geography.cliffProbability = state.CliffProbability
+	sim.Geography.tiles = state.Tiles
+	sim.Geography.roads = state.Roads
+
+	// some encodings leave out empty maps, which have to be written to
+	if sim.Houses == nil {
+		sim.Houses = make(Housing)
+	}
+	if sim.Companies == nil {
+		sim.Companies = make(Companies)
+	}
+	for _, company := range sim.Companies {
+		if company.JobOpenings == nil {
+			company.JobOpenings = make(map[CareerLevel]int)
+		}
+	}
+	if sim.People.People == nil {
+		sim.People.People = make(map[int]*Person)
+	}
+	if sim.People.Households == nil {
+		sim.People.Households = make(map[int]*Household)
+	}
+	if sim.Government.Expenses == nil {
+		sim.Government.Expenses = make(map[CostType]float64)
+	}
+	if sim.Bank == nil { // saves from before the city had a bank
+		sim.Bank = NewBank()
+	} else if sim.Bank.Loans == nil {
+		sim.Bank.Loans = make(map[int]*Loan)
+	}
+	if sim.Landlord == nil { // saves from before houses had owners
+		sim.Landlord = &Landlord{}
+	}
+	if sim.SimulationSpeed == 25 { // saves from when Ultra speed was a tick interval
+		sim.SimulationSpeed = Ultra
+	}
+	if sim.Catalogue == nil { // saves from before cities kept their catalogue
+		sim.Catalogue = DefaultCatalogue()
+	}
+	if sim.Statistics == nil { // saves from before statistics were recorded
+		sim.Statistics = NewStatistics()
+	} else if sim.Statistics.Series == nil {
+		sim.Statistics.Series = make(map[string][]float64)
+	}
+	sim.stats = NewEventBus()
+	sim.events = NewEventBus()
+	sim.snapshots = NewSnapshotHistory(MaxSnapshots)
+	sim.journal = &Journal{Seed: sim.Seed}
 }
@@ -0,0 +1,232 @@
+package entities
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Snapshot is a deep copy of the simulation at a point in time, that it can be restored to
+type Snapshot struct {
+	Date time.Time
+	sim  *Simulation
+}
+
+// Snapshot returns a deep copy of the current state of the simulation
+func (s *Simulation) Snapshot() *Snapshot {
+	return &Snapshot{Date: s.Date, sim: s.Clone()}
+}
+
+// Restore rolls the simulation back to a snapshot. The snapshot is left untouched,
+// so it can be restored again. Event subscribers, the snapshot history and play time are kept, and
+// the journal goes back to the commands that had been given when the snapshot was taken.
+func (s *Simulation) Restore(snapshot *Snapshot) {
+	c := snapshot.sim.Clone()
+
+	s.Date = c.Date
+	s.CityName = c.CityName
+	s.Seed = c.Seed
+	s.tickNumber = c.tickNumber
+	s.lastID.Store(c.lastID.Load())
+	s.seedRNG(snapshot.sim.GetRNGState())
+	s.skipFrom, s.skipUntil = time.Time{}, time.Time{}
+	s.journal = c.journal
+	s.Goals = c.Goals
+	s.Catalogue = c.Catalogue
+
+	// restore in place, as the UI holds on to some of these
+	*s.Government = *c.Government
+	*s.People = *c.People
+	*s.Market = *c.Market
+	*s.Bank = *c.Bank
+	*s.Landlord = *c.Landlord
+	*s.NameService = *c.NameService
+	*s.Statistics = *c.Statistics
+	s.NameService.rng = s.rng
+	clear(s.Houses)
+	maps.Copy(s.Houses, c.Houses)
+	clear(s.Companies)
+	maps.Copy(s.Companies, c.Companies)
+
+	regions := s.Geography.Regions
+	*s.Geography = *c.Geography
+	if len(regions) == len(c.Geography.Regions) {
+		for i := range regions {
+			*regions[i] = *c.Geography.Regions[i]
+		}
+		s.Geography.Regions = regions
+	}
+}
+
+// Clone returns a deep copy of the simulation state, without its event subscribers and history,
+// for saving or inspecting the simulation while it carries on running
+func (s *Simulation) Clone() *Simulation {
+	c := &Simulation{
+		SimulationSpeed: s.SimulationSpeed,
+		Date:            s.Date,
+		Government:      s.Government.clone(),
+		People:          s.People.clone(),
+		Houses:          s.Houses.clone(),
+		Companies:       s.Companies.clone(),
+		Market:          s.Market.clone(),
+		Bank:            s.Bank.clone(),
+		Landlord:        s.Landlord.clone(),
+		Catalogue:       s.Catalogue, // never changed, so it can be shared
+		Geography:       s.Geography.clone(),
+		tickNumber:      s.tickNumber,
+		CityName:        s.CityName,
+		NameService:     s.NameService.clone(),
+		Seed:            s.Seed,
+		Statistics:      s.Statistics.clone(),
+		Goals:           cloneGoals(s.Goals),
+		journal:         s.journal.clone(),
+		playTime:        s.playTime,
+	}
+	c.lastID.Store(s.lastID.Load())
+	c.seedRNG(s.GetRNGState())
+	c.NameService.rng = c.rng
+	return c
+}
+
+func (g *Government) clone() *Government {
+	c := *g
+	c.IncomeTaxBrackets = slices.Clone(g.IncomeTaxBrackets)
+	c.Expenses = maps.Clone(g.Expenses)
+	c.ReserveValues = slices.Clone(g.ReserveValues)
+	c.IncomeValues = slices.Clone(g.IncomeValues)
+	c.CapExValues = slices.Clone(g.CapExValues)
+	c.OpExValues = slices.Clone(g.OpExValues)
+	return &c
+}
+
+func (p *People) clone() *People {
+	c := *p
+	c.PopulationValues = slices.Clone(p.PopulationValues)
+	c.UnemploymentRateValues = slices.Clone(p.UnemploymentRateValues)
+	c.AverageWageValues = slices.Clone(p.AverageWageValues)
+	c.AgeGroups = maps.Clone(p.AgeGroups)
+	c.People = make(map[int]*Person, len(p.People))
+	for id, person := range p.People {
+		personCopy := *person
+		c.People[id] = &personCopy
+	}
+	c.Households = make(map[int]*Household, len(p.Households))
+	for id, household := range p.Households {
+		householdCopy := *household
+		householdCopy.MemberIDs = slices.Clone(household.MemberIDs)
+		c.Households[id] = &householdCopy
+	}
+	return &c
+}
+
+func (h Housing) clone() Housing {
+	c := make(Housing, len(h))
+	for id, house := range h {
+		houseCopy := *house
+		houseCopy.Location = clonePoint(house.Location)
+		c[id] = &houseCopy
+	}
+	return c
+}
+
+func (cs Companies) clone() Companies {
+	c := make(Companies, len(cs))
+	for id, company := range cs {
+		companyCopy := *company
+		companyCopy.Location = clonePoint(company.Location)
+		companyCopy.JobOpenings = maps.Clone(company.JobOpenings)
+		companyCopy.Employees = slices.Clone(company.Employees)
+		c[id] = &companyCopy
+	}
+	return c
+}
+
+func (m *Market) clone() *Market {
+	c := *m
+	c.History = MarketHistory{
+		MarketValue:        slices.Clone(m.History.MarketValue),
+		InflationRate:      slices.Clone(m.History.InflationRate),
+		InterestRate:       slices.Clone(m.History.InterestRate),
+		MarketGrowthRate:   slices.Clone(m.History.MarketGrowthRate),
+		MarketSentiment:    slices.Clone(m.History.MarketSentiment),
+		CompanyProfits:     slices.Clone(m.History.CompanyProfits),
+		AverageRent:        slices.Clone(m.History.AverageRent),
+		CompanyClosures:    slices.Clone(m.History.CompanyClosures),
+		Layoffs:            slices.Clone(m.History.Layoffs),
+		BusinessInvestment: slices.Clone(m.History.BusinessInvestment),
+		CorporateDebt:      slices.Clone(m.History.CorporateDebt),
+		AverageHousePrice:  slices.Clone(m.History.AverageHousePrice),
+	}
+	return &c
+}
+
+func (g *Geography) clone() *Geography {
+	c := *g
+	c.tiles = make([][]Tile, len(g.tiles))
+	for x := range g.tiles {
+		c.tiles[x] = slices.Clone(g.tiles[x])
+	}
+	c.roads = make([]*Road, len(g.roads))
+	for i, road := range g.roads {
+		roadCopy := *road
+		roadCopy.Segments = slices.Clone(road.Segments)
+		c.roads[i] = &roadCopy
+	}
+	c.Regions = make(Regions, len(g.Regions))
+	for i, region := range g.Regions {
+		regionCopy := *region
+		regionCopy.Trips = make([]*Trip, len(region.Trips))
+		for j, trip := range region.Trips {
+			tripCopy := *trip
+			tripCopy.Start = clonePoint(trip.Start)
+			tripCopy.End = clonePoint(trip.End)
+			regionCopy.Trips[j] = &tripCopy
+		}
+		c.Regions[i] = &regionCopy
+	}
+	return &c
+}
+
+func (ns *NameService) clone() *NameService {
+	return &NameService{
+		LastTenNames:     slices.Clone(ns.LastTenNames),
+		LastTenFamilies:  slices.Clone(ns.LastTenFamilies),
+		LastTenCompanies: slices.Clone(ns.LastTenCompanies),
+		LastTenPlaces:    slices.Clone(ns.LastTenPlaces),
+		rng:              ns.rng,
+	}
+}
+
+func clonePoint(p *Point) *Point {
+	if p == nil {
+		return nil
+	}
+	c := *p
+	return &c
+}
+
+// SnapshotHistory keeps a bounded number of the latest snapshots
+type SnapshotHistory struct {
+	snapshots []*Snapshot
+	capacity  int
+}
+
+// NewSnapshotHistory returns a history that keeps up to capacity snapshots
+func NewSnapshotHistory(capacity int) *SnapshotHistory {
+	return &SnapshotHistory{capacity: capacity}
+}
+
+// Add adds a snapshot, dropping the oldest one if the history is full
+func (h *SnapshotHistory) Add(snapshot *Snapshot) {
+	h.snapshots = append(h.snapshots, snapshot)
+	if len(h.snapshots) > h.capacity {
+		h.snapshots = slices.Delete(h.snapshots, 0, len(h.snapshots)-h.capacity)
+	}
+}
+
+// List returns the snapshots in the history, newest first
+func (h *SnapshotHistory) List() []*Snapshot {
+	list := slices.Clone(h.snapshots)
+	slices.Reverse(list)
+	return list
+}
@@ -0,0 +1,174 @@
+package entities
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/janithl/citylyf/internal/utils"
+)
+
+// statistic is a metric of the city that is recorded over time
+type statistic struct {
+	name   string
+	sample func(s *Simulation) float64
+}
+
+// statistics are the headline figures and the figures shown in the graph windows
+var statistics = []statistic{
+	{"Reserves", func(s *Simulation) float64 { return s.Government.GetReservesAtHand() }},
+	{"GovernmentIncome", func(s *Simulation) float64 { return float64(utils.GetLastValue(s.Government.IncomeValues)) }},
+	{"Population", func(s *Simulation) float64 { return float64(s.People.Population()) }},
+	{"PopulationGrowth", func(s *Simulation) float64 { return s.People.PopulationGrowthRate() }},
+	{"Households", func(s *Simulation) float64 { return float64(len(s.People.Households)) }},
+	{"Houses", func(s *Simulation) float64 { return float64(len(s.Houses)) }},
+	{"FreeHouses", func(s *Simulation) float64 { return float64(s.Houses.GetFreeHouses()) }},
+	{"Unemployment", func(s *Simulation) float64 { return s.People.UnemploymentRate() }},
+	{"AverageWage", func(s *Simulation) float64 { return s.People.AverageWage() }},
+	{"AverageRent", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.AverageRent) }},
+	{"RentToIncome", rentToIncome},
+	{"AverageHousePrice", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.AverageHousePrice) }},
+	{"HomeOwnership", homeOwnership},
+	{"LandlordRentIncome", func(s *Simulation) float64 { return s.Landlord.LastRentIncome }},
+	{"Companies", func(s *Simulation) float64 { return float64(len(s.Companies)) }},
+	{"CompanyProfits", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.CompanyProfits) }},
+	{"CompanyClosures", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.CompanyClosures) }},
+	{"Layoffs", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.Layoffs) }},
+	{"BusinessInvestment", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.BusinessInvestment) }},
+	{"CorporateDebt", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.CorporateDebt) }},
+	{"BankProfit", func(s *Simulation) float64 { return s.Bank.LastProfit }},
+	{"BankDeposits", func(s *Simulation) float64 { return utils.GetLastValue(s.Bank.History.Deposits) }},
+	{"LoanBook", func(s *Simulation) float64 { return s.Bank.LoanBook() }},
+	{"DefaultRate", func(s *Simulation) float64 { return s.Bank.DefaultRate() }},
+	{"MarketValue", func(s *Simulation) float64 { return s.Market.MarketValue() }},
+	{"MarketGrowth", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.MarketGrowthRate) }},
+	{"MarketSentiment", func(s *Simulation) float64 { return utils.GetLastValue(s.Market.History.MarketSentiment) }},
+	{"Inflation", func(s *Simulation) float64 { return s.Market.InflationRate() }},
+	{"InterestRate", func(s *Simulation) float64 { return s.Market.InterestRate() }},
+	{"GDP", func(s *Simulation) float64 { return s.Market.CalculateGDP(s) }},
+	{"Recession", func(s *Simulation) float64 { return recession(s) }},
+}
+
+// recession returns 100 if the market is in recession and 0 if not, so that its average over a
+// period is the percentage of the period spent in recession
+func recession(s *Simulation) float64 {
+	if s.Market.InRecession {
+		return 100
+	}
+	return 0
+}
+
+// rentToIncome returns the rent households that rent pay as a percentage of their income
+func rentToIncome(s *Simulation) float64 {
+	rent, income := 0, 0
+	for _, id := range s.People.GetHouseholdIDs() {
+		household := s.People.Households[id]
+		if house, exists := s.Houses[household.HouseID]; exists && house.Owner != OwnerHousehold {
+			rent += house.MonthlyRent * 12
+			income += household.AnnualIncome(s.People, s.Date, false)
+		}
+	}
+	if income == 0 {
+		return 0
+	}
+	return 100 * float64(rent) / float64(income)
+}
+
+// homeOwnership returns the percentage of households that own their house
+func homeOwnership(s *Simulation) float64 {
+	if len(s.People.Households) == 0 {
+		return 0
+	}
+	return 100 * float64(s.Houses.GetOwnedHouses(OwnerHousehold)) / float64(len(s.People.Households))
+}
+
+// Statistic returns the current value of one of the city's recorded figures, and false if there is
+// no figure of that name
+func (s *Simulation) Statistic(name string) (float64, bool) {
+	for _, stat := range statistics {
+		if stat.name == name {
+			return stat.sample(s), true
+		}
+	}
+	return 0, false
+}
+
+// Statistics is the full history of the city's figures, unlike the short histories kept for graphs
+type Statistics struct {
+	Dates  []time.Time
+	Series map[string][]float64 // values of each figure, one for each date
+}
+
+// NewStatistics returns an empty statistics history
+func NewStatistics() *Statistics {
+	return &Statistics{Series: make(map[string][]float64)}
+}
+
+// Record samples every figure of the simulation on its current date
+func (st *Statistics) Record(s *Simulation) {
+	for _, stat := range statistics {
+		series := st.Series[stat.name]
+		for len(series) < len(st.Dates) { // figures added since the city was started have no earlier values
+			series = append(series, 0)
+		}
+		st.Series[stat.name] = append(series, stat.sample(s))
+	}
+	st.Dates = append(st.Dates, s.Date)
+}
+
+// Names returns the names of the recorded figures, in the order they are exported
+func (st *Statistics) Names() []string {
+	names := []string{}
+	for _, stat := range statistics {
+		names = append(names, stat.name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(st.Series)) { // figures no longer recorded go last
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Value returns a figure on the date at index i, or 0 if it was not recorded then
+func (st *Statistics) Value(name string, i int) float64 {
+	if series := st.Series[name]; i < len(series) {
+		return series[i]
+	}
+	return 0
+}
+
+// WriteCSV writes the statistics as CSV, with a row for each date
+func (st *Statistics) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	names := st.Names()
+	cw.Write(append([]string{"Date"}, names...))
+	for i, date := range st.Dates {
+		row := []string{date.Format("2006-01-02")}
+		for _, name := range names {
+			row = append(row, strconv.FormatFloat(st.Value(name, i), 'f', 4, 64))
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes the statistics as JSON, with the dates and a series for each figure
+func (st *Statistics) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(st)
+}
+
+func (st *Statistics) clone() *Statistics {
+	c := &Statistics{Dates: slices.Clone(st.Dates), Series: make(map[string][]float64, len(st.Series))}
+	for name, series := range st.Series {
+		c.Series[name] = slices.Clone(series)
+	}
+	return c
+}
@@ -0,0 +1,50 @@
+package entities_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+func TestStatistics(t *testing.T) {
+	t.Parallel()
+	sim := entities.NewSimulation(2020, 1000000, 1)
+	stats := sim.Statistics
+	stats.Series["Retired"] = []float64{7} // a figure that is no longer recorded
+	stats.Dates = append(stats.Dates, sim.Date.AddDate(0, -1, 0))
+	for range 36 { // longer than any of the graph histories
+		stats.Record(sim)
+		sim.Date = sim.Date.AddDate(0, 1, 0)
+	}
+
+	if len(stats.Dates) != 37 || len(stats.Series["Population"]) != 37 || stats.Value("Reserves", 36) != 1000000 {
+		t.Errorf("expected 37 samples of every figure, got %d dates and %d population values", len(stats.Dates), len(stats.Series["Population"]))
+	}
+	if names := stats.Names(); names[0] != "Reserves" || names[len(names)-1] != "Retired" {
+		t.Errorf("expected figures in recording order, then ones no longer recorded, got %v", names)
+	}
+
+	var csvOut bytes.Buffer
+	if err := stats.WriteCSV(&csvOut); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 38 || !strings.HasPrefix(lines[0], "Date,Reserves,") || !strings.HasSuffix(lines[1], ",7.0000") {
+		t.Errorf("unexpected CSV export, starting with: %v", lines[:2])
+	}
+
+	var jsonOut bytes.Buffer
+	if err := stats.WriteJSON(&jsonOut); err != nil {
+		t.Fatal(err)
+	}
+	decoded := entities.Statistics{}
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Dates) != 37 || len(decoded.Series["Inflation"]) != 37 {
+		t.Error("expected the JSON export to have every sample")
+	}
+}
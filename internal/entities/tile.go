@@ -10,10 +10,10 @@ type Tile struct {
 	LandStatus   LandStatus
 }
 
-func (t *Tile) IsBuildable() bool { // buildable on sealevel if flat land
-	return (t.Elevation > Sim.Geography.SeaLevel ||
-		(t.Elevation == Sim.Geography.SeaLevel && t.LandSlope == Flat)) &&
-		t.Elevation < Sim.Geography.HillLevel && t.LandUse != ReserveUse
+func (t *Tile) IsBuildable(g *Geography) bool { // buildable on sealevel if flat land
+	return (t.Elevation > g.SeaLevel ||
+		(t.Elevation == g.SeaLevel && t.LandSlope == Flat)) &&
+		t.Elevation < g.HillLevel && t.LandUse != ReserveUse
 }
 
 func (t *Tile) IsBuilt() bool {
@@ -0,0 +1,107 @@
+package gamefile
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// Autosave is a scheduler system that saves the city to a rotating set of slots. The city
+// is copied while the simulation is locked, and written in the background by a single writer,
+// which skips copies that a newer one has replaced before it got to them.
+type Autosave struct {
+	Dir     string // directory the autosaves are written to
+	Slots   int    // number of autosaves kept per city
+	writing sync.Mutex
+	pending sync.WaitGroup
+
+	queueMutex sync.Mutex           // guards the queued copy and whether the writer is running
+	queued     *entities.Simulation // latest copy of the city waiting to be written
+	running    bool
+}
+
+// NewAutosave returns an autosave system that keeps slots autosaves per city in dir
+func NewAutosave(dir string, slots int) *Autosave {
+	return &Autosave{Dir: dir, Slots: max(slots, 1)}
+}
+
+func (a *Autosave) Name() string {
+	return "autosave"
+}
+
+func (a *Autosave) Update(sim *entities.Simulation) {
+	if sim.CityName == "" || a.Dir == "" { // nothing to name the autosave after, or nowhere to put it
+		return
+	}
+
+	a.queueMutex.Lock()
+	defer a.queueMutex.Unlock()
+	a.queued = sim.Clone()
+	if !a.running {
+		a.running = true
+		a.pending.Add(1)
+		go a.write()
+	}
+}
+
+// write saves queued copies of the city in the order they were made, until there are none left
+func (a *Autosave) write() {
+	defer a.pending.Done()
+	for {
+		a.queueMutex.Lock()
+		sim := a.queued
+		a.queued = nil
+		a.running = sim != nil
+		a.queueMutex.Unlock()
+		if sim == nil {
+			return
+		}
+		if _, err := a.Save(sim); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// Save writes the city to its next autosave slot, overwriting the oldest autosave, and returns its path
+func (a *Autosave) Save(sim *entities.Simulation) (string, error) {
+	a.writing.Lock()
+	defer a.writing.Unlock()
+
+	path := a.nextSlot(sim.CityName)
+	if err := SaveTo(sim, path); err != nil {
+		return "", fmt.Errorf("could not autosave: %w", err)
+	}
+	return path, nil
+}
+
+// Wait waits for autosaves being written in the background to finish
+func (a *Autosave) Wait() {
+	a.pending.Wait()
+}
+
+// nextSlot returns the path of the first unused autosave slot, or else the oldest one
+func (a *Autosave) nextSlot(cityName string) string {
+	next := AutosavePath(a.Dir, cityName, 1)
+	var oldest os.FileInfo
+	for slot := 1; slot <= a.Slots; slot++ {
+		path := AutosavePath(a.Dir, cityName, slot)
+		info, err := os.Stat(path)
+		if err != nil {
+			return path
+		}
+		if oldest == nil || info.ModTime().Before(oldest.ModTime()) {
+			next, oldest = path, info
+		}
+	}
+	return next
+}
+
+// AutosavePath returns the path of a city's autosave slot, counting from 1
+func AutosavePath(dir, cityName string, slot int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.autosave%d.citylyf", strings.ToLower(cityName), slot))
+}
@@ -0,0 +1,186 @@
+package gamefile
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// binaryMagic starts every binary save file, and is followed by a byte giving its layout
+var binaryMagic = []byte("CITYLYF")
+
+const (
+	layoutPlain      byte = 0 // gzipped gob data follows straight away
+	layoutWithHeader byte = 1 // a length-prefixed JSON SaveHeader comes before the gzipped gob data
+)
+
+// binarySaveGame is a SaveGame laid out for gob, which cannot encode the simulation's mutex
+type binarySaveGame struct {
+	Version int
+	Sim     binarySimulation
+	entities.SaveState
+}
+
+// binarySimulation holds the exported state of a simulation
+type binarySimulation struct {
+	SimulationSpeed entities.SimulationSpeed
+	Date            time.Time
+	Government      *entities.Government
+	People          *entities.People
+	Houses          entities.Housing
+	Companies       entities.Companies
+	Market          *entities.Market
+	Bank            *entities.Bank
+	Landlord        *entities.Landlord
+	Catalogue       *entities.Catalogue
+	Geography       *entities.Geography
+	CityName        string
+	NameService     *entities.NameService
+	Seed            uint64
+	Statistics      *entities.Statistics
+	Goals           []*entities.Goal
+}
+
+// isBinary returns true if the file data is a binary save file
+func isBinary(data []byte) bool {
+	return len(data) > len(binaryMagic) && bytes.HasPrefix(data, binaryMagic)
+}
+
+// splitBinary returns the JSON header and the gzipped gob data of a binary save file,
+// reading no more of it than needed. The header is nil for saves written without one.
+func splitBinary(r io.Reader) ([]byte, io.Reader, error) {
+	prefix := make([]byte, len(binaryMagic)+1)
+	if _, err := io.ReadFull(r, prefix); err != nil || !bytes.HasPrefix(prefix, binaryMagic) {
+		return nil, nil, fmt.Errorf("not a binary save file")
+	}
+
+	switch layout := prefix[len(binaryMagic)]; layout {
+	case layoutPlain:
+		return nil, r, nil
+	case layoutWithHeader:
+		var length uint32
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return nil, nil, err
+		}
+		if length > maxHeaderSize {
+			return nil, nil, fmt.Errorf("save header is too large")
+		}
+		header := make([]byte, length)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, nil, err
+		}
+		return header, r, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown save file layout %d", layout)
+	}
+}
+
+// encodeBinary writes a save game in the binary format, with its header first
+func encodeBinary(w io.Writer, saveGame SaveGame, header SaveHeader) error {
+	sim := saveGame.Sim
+	binarySave := binarySaveGame{
+		Version: saveGame.Version,
+		Sim: binarySimulation{
+			SimulationSpeed: sim.SimulationSpeed,
+			Date:            sim.Date,
+			Government:      sim.Government,
+			People:          sim.People,
+			Houses:          sim.Houses,
+			Companies:       sim.Companies,
+			Market:          sim.Market,
+			Bank:            sim.Bank,
+			Landlord:        sim.Landlord,
+			Catalogue:       sim.Catalogue,
+			Geography:       sim.Geography,
+			CityName:        sim.CityName,
+			NameService:     sim.NameService,
+			Seed:            sim.Seed,
+			Statistics:      sim.Statistics,
+			Goals:           sim.Goals,
+		},
+		SaveState: saveGame.SaveState,
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(binaryMagic); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{layoutWithHeader}); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(headerJSON))); err != nil {
+		return err
+	}
+	if _, err := w.Write(headerJSON); err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(w)
+	if err := gob.NewEncoder(zw).Encode(binarySave); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// decodeBinary reads a save game in the binary format. Save games from older versions
+// are returned as JSON, so they can go through the same migrations as JSON save files.
+func decodeBinary(data []byte) (*SaveGame, []byte, error) {
+	_, body, err := splitBinary(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer zr.Close()
+
+	binarySave := &binarySaveGame{}
+	if err := gob.NewDecoder(bufio.NewReader(zr)).Decode(binarySave); err != nil {
+		return nil, nil, err
+	}
+	if binarySave.Version > CurrentVersion {
+		return nil, nil, fmt.Errorf("%w: version %d", ErrNewerVersion, binarySave.Version)
+	}
+	if binarySave.Sim.Geography == nil || binarySave.Sim.People == nil {
+		return nil, nil, fmt.Errorf("save file has no simulation")
+	}
+
+	b := binarySave.Sim
+	sim := &entities.Simulation{
+		SimulationSpeed: b.SimulationSpeed,
+		Date:            b.Date,
+		Government:      b.Government,
+		People:          b.People,
+		Houses:          b.Houses,
+		Companies:       b.Companies,
+		Market:          b.Market,
+		Bank:            b.Bank,
+		Landlord:        b.Landlord,
+		Catalogue:       b.Catalogue,
+		Geography:       b.Geography,
+		CityName:        b.CityName,
+		NameService:     b.NameService,
+		Seed:            b.Seed,
+		Statistics:      b.Statistics,
+		Goals:           b.Goals,
+	}
+	saveGame := &SaveGame{Version: binarySave.Version, Sim: sim, SaveState: binarySave.SaveState}
+	if saveGame.Version == CurrentVersion {
+		return saveGame, nil, nil
+	}
+
+	jsonData, err := json.Marshal(saveGame)
+	return nil, jsonData, err
+}
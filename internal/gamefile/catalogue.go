@@ -0,0 +1,54 @@
+package gamefile
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// GetCataloguePath returns the path of the file that overrides the default industries and jobs
+func GetCataloguePath() string {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		log.Println(err)
+		return ""
+	}
+	return homedir + "/.citylyf/catalogue.json"
+}
+
+// LoadCatalogue returns the default catalogue of industries and jobs, overridden by the ones in the
+// JSON file at path
+func LoadCatalogue(path string) (*entities.Catalogue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	override := &entities.Catalogue{}
+	if err := json.Unmarshal(data, override); err != nil {
+		return nil, fmt.Errorf("catalogue %s: %w", path, err)
+	}
+
+	c := entities.DefaultCatalogue().Override(override)
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("catalogue %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// FindCatalogue returns the catalogue overridden by the file at path if there is one, or nil for new
+// games to use the default catalogue if there isn't or it can't be loaded
+func FindCatalogue(path string) *entities.Catalogue {
+	if path == "" || !CheckExists(path) {
+		return nil
+	}
+	c, err := LoadCatalogue(path)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+	log.Println("using the industries and jobs in", path)
+	return c
+}
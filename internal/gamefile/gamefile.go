@@ -1,67 +1,152 @@
 package gamefile
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/janithl/citylyf/internal/entities"
 )
 
+// SaveGame is the contents of a save file
 type SaveGame struct {
-	Sim    *entities.Simulation
-	LastID int
-	Tiles  [][]entities.Tile
-	Roads  []*entities.Road
+	Version int
+	Sim     *entities.Simulation
+	entities.SaveState
 }
 
-// Save saves the current game state to a file at the specified path
-func Save() {
-	var f *os.File
-	var err error
-	var saveGameJSON []byte
+// Save saves the game state to the saves directory, named after the city
+func Save(sim *entities.Simulation) error {
+	return SaveTo(sim, SavePath(sim))
+}
 
-	path := GetSavesDir() + "/" + strings.ToLower(entities.Sim.CityName) + ".json"
-	if f, err = os.Create(path); err != nil {
-		log.Println(err)
-		return
+// SavePath returns the path Save saves the city to
+func SavePath(sim *entities.Simulation) string {
+	return GetSavesDir() + "/" + strings.ToLower(sim.CityName) + ".citylyf"
+}
+
+// SaveTo saves the game state to a file at the specified path, in the compressed binary format,
+// with the journal of commands given to the simulation next to it
+func SaveTo(sim *entities.Simulation, path string) error {
+	var buf bytes.Buffer
+	if err := encodeBinary(&buf, newSaveGame(sim), newSaveHeader(sim)); err != nil {
+		return fmt.Errorf("could not encode save game: %w", err)
 	}
-	defer f.Close()
 
-	saveGame := SaveGame{
-		Sim:    entities.Sim,
-		LastID: entities.Sim.GetNextID(),
-		Tiles:  entities.Sim.Geography.GetTiles(),
-		Roads:  entities.Sim.Geography.GetRoads(),
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("could not write save file: %w", err)
 	}
+	return SaveJournal(sim.Journal(), JournalPath(path))
+}
 
-	if saveGameJSON, err = json.Marshal(saveGame); err != nil {
-		log.Println(err)
-		return
+// ExportJSON saves the game state to a file at the specified path as JSON, which Load can also read
+func ExportJSON(sim *entities.Simulation, path string) error {
+	saveGameJSON, err := json.Marshal(newSaveGame(sim))
+	if err != nil {
+		return fmt.Errorf("could not encode save game: %w", err)
 	}
 
-	if _, err := f.Write(saveGameJSON); err != nil {
-		log.Println(err)
-		return
+	if err := writeFileAtomic(path, saveGameJSON); err != nil {
+		return fmt.Errorf("could not write save file: %w", err)
 	}
+	return SaveJournal(sim.Journal(), JournalPath(path))
 }
 
-// Load loads the game state from a file at the specified path
-// and initializes the simulation with the loaded data
-func Load(path string) {
-	var fileData []byte
-	var err error
-	if fileData, err = os.ReadFile(path); err != nil {
-		log.Println(err)
-		return
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// so that path is either left as it was or completely written, even if the game crashes
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // clean up if the rename doesn't happen
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func newSaveGame(sim *entities.Simulation) SaveGame {
+	return SaveGame{
+		Version:   CurrentVersion,
+		Sim:       sim,
+		SaveState: sim.GetSaveState(),
+	}
+}
+
+// Load loads the game state from a binary or JSON file at the specified path, migrating
+// older save files, and returns the simulation initialized with the loaded data. Broken
+// references between entities are repaired, and logged.
+func Load(path string) (*entities.Simulation, error) {
+	sim, report, err := LoadWithReport(path)
+	if err == nil && !report.OK() {
+		log.Println(report)
+	}
+	return sim, err
+}
+
+// LoadWithReport loads the game state like Load, and returns a report of the broken
+// references between entities that were found and repaired
+func LoadWithReport(path string) (*entities.Simulation, *entities.IntegrityReport, error) {
+	sim, err := load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sim, sim.CheckIntegrity(true), nil
+}
+
+func load(path string) (*entities.Simulation, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read save file: %w", err)
 	}
 
-	jsonDecoder := json.NewDecoder(strings.NewReader(string(fileData)))
 	saveGame := &SaveGame{}
-	jsonDecoder.Decode(saveGame)
+	if isBinary(fileData) {
+		if saveGame, fileData, err = decodeBinary(fileData); err != nil {
+			return nil, fmt.Errorf("could not decode save file %s: %w", path, err)
+		}
+	}
+
+	if fileData != nil { // JSON, or an older binary save converted to JSON
+		if fileData, err = migrate(fileData); err != nil {
+			return nil, fmt.Errorf("could not migrate save file %s: %w", path, err)
+		}
 
-	entities.LoadSimulationFromSave(saveGame.Sim, uint32(saveGame.LastID), saveGame.Tiles, saveGame.Roads)
+		saveGame = &SaveGame{}
+		if err := json.Unmarshal(fileData, saveGame); err != nil {
+			return nil, fmt.Errorf("could not decode save file %s: %w", path, err)
+		}
+	}
+	if saveGame.Sim == nil {
+		return nil, fmt.Errorf("save file %s has no simulation", path)
+	}
+
+	entities.LoadSimulationFromSave(saveGame.Sim, saveGame.SaveState)
+	if journalPath := findJournal(path); CheckExists(journalPath) { // carry on the journal, if the save has one
+		journal, err := LoadJournal(journalPath)
+		if err != nil {
+			return nil, err
+		}
+		saveGame.Sim.SetJournal(journal)
+	}
+	return saveGame.Sim, nil
 }
 
 func CheckExists(path string) bool {
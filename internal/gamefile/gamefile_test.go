@@ -0,0 +1,471 @@
+package gamefile_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/janithl/citylyf/internal"
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/gamefile"
+	"github.com/janithl/citylyf/internal/scheduler"
+)
+
+// newCity returns a runner with a city that has been running for a while
+func newCity() *internal.SimRunner {
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	sim.CityName = "Testville"
+	sim.Goals = []*entities.Goal{{Name: "Grow", Statistic: "Population", Comparison: ">=", Value: 5000, Months: 2, Status: entities.GoalInProgress}}
+	sim.RegenerateMap(0.002, 0.004, 0.02)
+	for i := 0; i < 4; i++ {
+		x, y := 8+i*6, 8+i*6
+		entities.PlaceRoad(sim, entities.Point{X: x - 4, Y: y}, entities.Point{X: x + 4, Y: y}, entities.Asphalt)
+		use := entities.ResidentialUse
+		if i == 3 {
+			use = entities.RetailUse
+		}
+		sim.Geography.PlaceLandUse(entities.Point{X: x - 4, Y: y - 2}, entities.Point{X: x + 4, Y: y + 2}, use)
+	}
+	simRunner.Advance(400)
+	return simRunner
+}
+
+// state returns everything about a simulation that should survive a save and load,
+// treating empty and missing values alike as some encodings leave them out
+func state(t *testing.T, sim *entities.Simulation) []byte {
+	data, err := json.Marshal(struct {
+		Sim   *entities.Simulation
+		State entities.SaveState
+	}{sim, sim.GetSaveState()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if data, err = json.Marshal(normalise(decoded)); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func normalise(value any) any {
+	switch v := value.(type) {
+	case float64:
+		if v == 0 { // gob does not keep negative zeros
+			return 0.0
+		}
+	case map[string]any:
+		if len(v) == 0 {
+			return nil
+		}
+		for key := range v {
+			v[key] = normalise(v[key])
+		}
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+		for i := range v {
+			v[i] = normalise(v[i])
+		}
+	}
+	return value
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	t.Parallel()
+	formats := map[string]func(*entities.Simulation, string) error{
+		"testville.citylyf": gamefile.SaveTo,
+		"testville.json":    gamefile.ExportJSON,
+	}
+
+	for name, save := range formats {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			original := newCity()
+			path := filepath.Join(t.TempDir(), name)
+			if err := save(original.Sim(), path); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := &internal.SimRunner{EventLog: io.Discard}
+			if err := loaded.NewGame(&path); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(state(t, original.Sim()), state(t, loaded.Sim())) {
+				t.Fatal("loaded simulation is different to the saved one")
+			}
+
+			// the loaded city should carry on exactly as the original does
+			original.Advance(100)
+			loaded.Advance(100)
+			if !bytes.Equal(state(t, original.Sim()), state(t, loaded.Sim())) {
+				t.Error("loaded simulation carried on differently to the saved one")
+			}
+		})
+	}
+}
+
+func TestBinarySaveIsSmaller(t *testing.T) {
+	t.Parallel()
+	sim := newCity().Sim()
+	dir := t.TempDir()
+	binaryPath, jsonPath := filepath.Join(dir, "city.citylyf"), filepath.Join(dir, "city.json")
+	if err := gamefile.SaveTo(sim, binaryPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := gamefile.ExportJSON(sim, jsonPath); err != nil {
+		t.Fatal(err)
+	}
+
+	binaryInfo, _ := os.Stat(binaryPath)
+	jsonInfo, _ := os.Stat(jsonPath)
+	if binaryInfo.Size()*2 > jsonInfo.Size() {
+		t.Errorf("expected the binary save (%d bytes) to be much smaller than JSON (%d bytes)", binaryInfo.Size(), jsonInfo.Size())
+	}
+}
+
+func TestLoadMigratesVersion1(t *testing.T) {
+	t.Parallel()
+	sim := newCity().Sim()
+	saveState := sim.GetSaveState()
+
+	// version 1 save files had no version, tick number or terrain probabilities, and their cities had
+	// no bank or landlord
+	simData, err := json.Marshal(sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1Sim := map[string]any{}
+	if err := json.Unmarshal(simData, &v1Sim); err != nil {
+		t.Fatal(err)
+	}
+	delete(v1Sim, "Bank")
+	delete(v1Sim, "Landlord")
+	v1, err := json.Marshal(map[string]any{
+		"Sim":      v1Sim,
+		"LastID":   saveState.LastID,
+		"RNGState": saveState.RNGState,
+		"Tiles":    saveState.Tiles,
+		"Roads":    saveState.Roads,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "v1.json")
+	if err := os.WriteFile(path, v1, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := gamefile.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadedState := loaded.GetSaveState()
+	if loadedState.PeakProbability != entities.DefaultPeakProbability || loadedState.LastID != saveState.LastID ||
+		len(loaded.People.People) != len(sim.People.People) {
+		t.Error("version 1 save file was not migrated correctly")
+	}
+	for _, company := range loaded.Companies {
+		if company.Cash != company.FixedCosts*entities.CashReserveMonths {
+			t.Errorf("expected %s to be given %d months of cash, got %.0f", company.Name, entities.CashReserveMonths, company.Cash)
+		}
+		if company.Assets <= 0 || company.CapitalPerJob <= 0 || company.JobCapacity() < company.GetNumberOfEmployees() {
+			t.Errorf("expected %s to be given the assets to employ its staff, got %.0f", company.Name, company.Assets)
+		}
+	}
+	for _, house := range loaded.Houses {
+		if house.Owner != entities.OwnerLandlord || house.Price != entities.HousePrice(house.MonthlyRent, loaded.Market.InterestRate()) {
+			t.Errorf("expected house %d to be handed to the landlord at its price, got %s at %d", house.ID, house.Owner, house.Price)
+		}
+	}
+
+	// the migrated city should carry on running through its monthly accounts
+	runner := &internal.SimRunner{EventLog: io.Discard}
+	if err := runner.NewGame(&path); err != nil {
+		t.Fatal(err)
+	}
+	runner.Advance(40)
+	if runner.Sim().Landlord == nil || runner.Sim().Bank == nil || runner.Sim().Date.Sub(loaded.Date).Hours() < 40*entities.HoursPerDay {
+		t.Error("expected the migrated city to be given a bank and landlord, and to carry on")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{
+		"corrupt.json": `{"Sim": {`,
+		"empty.json":   `{"Version": 2}`,
+		"future.json":  `{"Version": 999, "Sim": {}}`,
+		"truncated":    "CITYLYF\x00\x1f\x8b",
+		"no-header":    "CITYLYF\x01\x00\x00\x10\x00{",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{"missing.json", "corrupt.json", "empty.json", "future.json", "truncated", "no-header"} {
+		if sim, err := gamefile.Load(filepath.Join(dir, name)); err == nil || sim != nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := gamefile.Load(filepath.Join(dir, "future.json")); !errors.Is(err, gamefile.ErrNewerVersion) {
+		t.Errorf("expected a newer version error, got %v", err)
+	}
+}
+
+// TestAutosave checks that autosaves fill their slots and then overwrite the oldest one
+func TestAutosave(t *testing.T) {
+	dir := t.TempDir()
+	autosave := gamefile.NewAutosave(dir, 3)
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard, Autosave: autosave, AutosaveOn: scheduler.Monthly}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	sim.CityName = "Autoville"
+	for range 3 { // on to the first of February, March and April
+		simRunner.Advance(32)
+		autosave.Wait()
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 6 { // a save and a journal in each slot, and no temporary files left behind
+		t.Errorf("expected 3 autosaves and their journals, got %d files", len(files))
+	}
+	for slot := 1; slot <= 3; slot++ {
+		if !gamefile.CheckExists(gamefile.AutosavePath(dir, "Autoville", slot)) {
+			t.Errorf("expected autosave slot %d to be used", slot)
+		}
+	}
+
+	oldest := gamefile.AutosavePath(dir, "Autoville", 2)
+	if err := os.Chtimes(oldest, time.Time{}, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	path, err := autosave.Save(sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != oldest {
+		t.Errorf("expected the oldest autosave %s to be overwritten, got %s", oldest, path)
+	}
+	loaded, err := gamefile.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Date.Equal(sim.Date) {
+		t.Errorf("expected the autosave to be from %s, got %s", sim.Date.Format("2006-01-02"), loaded.Date.Format("2006-01-02"))
+	}
+}
+
+// TestAutosaveOrder checks that autosaves made in quick succession are written in order, so the
+// newest slot always has the latest city
+func TestAutosaveOrder(t *testing.T) {
+	dir := t.TempDir()
+	autosave := gamefile.NewAutosave(dir, 1)
+	sim := newCity().Sim()
+	sim.CityName = "Quickville"
+	for range 20 {
+		sim.Date = sim.Date.AddDate(0, 0, 1)
+		autosave.Update(sim)
+	}
+	autosave.Wait()
+
+	loaded, err := gamefile.Load(gamefile.AutosavePath(dir, "Quickville", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Date.Equal(sim.Date) {
+		t.Errorf("expected the last autosave to be from %s, got %s", sim.Date.Format("2006-01-02"), loaded.Date.Format("2006-01-02"))
+	}
+}
+
+// TestJournalPerSave checks that saves of the same name in different formats keep their own journals
+func TestJournalPerSave(t *testing.T) {
+	t.Parallel()
+	sim := newCity().Sim()
+	dir := t.TempDir()
+	binaryPath, jsonPath := filepath.Join(dir, "city.citylyf"), filepath.Join(dir, "city.json")
+	sim.Execute(entities.NameCityCommand{Name: "Binaryville"})
+	if err := gamefile.SaveTo(sim, binaryPath); err != nil {
+		t.Fatal(err)
+	}
+	sim.Execute(entities.NameCityCommand{Name: "Jsonville"})
+	if err := gamefile.ExportJSON(sim, jsonPath); err != nil {
+		t.Fatal(err)
+	}
+
+	for path, entries := range map[string]int{binaryPath: 1, jsonPath: 2} {
+		loaded, err := gamefile.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(loaded.Journal().Entries); got != entries {
+			t.Errorf("expected %s to have a journal of %d commands, got %d", filepath.Base(path), entries, got)
+		}
+	}
+}
+
+func TestSaveHeader(t *testing.T) {
+	t.Parallel()
+	sim := newCity().Sim()
+	sim.AddPlayTime(90 * time.Minute)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "testville.citylyf")
+	if err := gamefile.SaveTo(sim, path); err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := gamefile.ReadHeader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.CityName != "Testville" || !header.Date.Equal(sim.Date) || header.Population != sim.People.Population() ||
+		header.Reserves != sim.Government.Reserves || header.PlayTime != 90*time.Minute || time.Since(header.SavedAt) > time.Minute {
+		t.Errorf("header does not describe the saved city: %+v", header)
+	}
+	thumbnail, err := png.Decode(bytes.NewReader(header.Thumbnail))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := thumbnail.Bounds().Dx(); size == 0 || size > 64 {
+		t.Errorf("expected a thumbnail up to 64 pixels wide, got %d", size)
+	}
+
+	jsonPath := filepath.Join(dir, "testville.json")
+	if err := gamefile.ExportJSON(sim, jsonPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gamefile.ReadHeader(jsonPath); !errors.Is(err, gamefile.ErrNoHeader) {
+		t.Errorf("expected JSON saves to have no header, got %v", err)
+	}
+}
+
+// TestSaveBrowsing checks listing, sorting, duplicating and deleting saves
+func TestSaveBrowsing(t *testing.T) {
+	t.Parallel()
+	simRunner := newCity()
+	sim := simRunner.Sim()
+	dir := t.TempDir()
+	older := filepath.Join(dir, "older.citylyf")
+	if err := gamefile.SaveTo(sim, older); err != nil {
+		t.Fatal(err)
+	}
+	simRunner.Advance(30)
+	newer := filepath.Join(dir, "newer.citylyf")
+	if err := gamefile.SaveTo(sim, newer); err != nil {
+		t.Fatal(err)
+	}
+	if err := gamefile.ExportJSON(sim, filepath.Join(dir, "export.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(saves []gamefile.SaveFile) []string {
+		list := []string{}
+		for _, save := range saves {
+			list = append(list, save.Name)
+		}
+		return list
+	}
+	saves := gamefile.ListSaves(dir)
+	gamefile.SortSaves(saves, gamefile.CityDate)
+	if got := names(saves); !slices.Equal(got, []string{"newer.citylyf", "older.citylyf", "export.json"}) {
+		t.Errorf("unexpected saves sorted by city date: %v", got)
+	}
+
+	duplicate, err := gamefile.DuplicateSave(older)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(duplicate) != "older copy.citylyf" || !gamefile.CheckExists(gamefile.JournalPath(duplicate)) {
+		t.Errorf("expected the save and its journal to be copied, got %s", duplicate)
+	}
+	saves = gamefile.ListSaves(dir)
+	gamefile.SortSaves(saves, gamefile.LastSaved)
+	if got := names(saves); got[0] != "export.json" || len(got) != 4 {
+		t.Errorf("unexpected saves sorted by when they were saved: %v", got)
+	}
+
+	if err := gamefile.DeleteSave(older); err != nil {
+		t.Fatal(err)
+	}
+	if gamefile.CheckExists(older) || gamefile.CheckExists(gamefile.JournalPath(older)) {
+		t.Error("expected the save and its journal to be deleted")
+	}
+}
+
+// TestLoadRepairsReferences checks that broken references in a save are repaired when it is loaded
+func TestLoadRepairsReferences(t *testing.T) {
+	t.Parallel()
+	simRunner := newCity()
+	sim := simRunner.Sim()
+	householdID := sim.People.GetHouseholdIDs()[0]
+	household := sim.People.Households[householdID]
+	for _, memberID := range household.MemberIDs {
+		delete(sim.People.People, memberID) // members and employees that no longer exist
+	}
+	sim.Houses[household.HouseID].HouseholdID = householdID + 1000000 // a household that does not exist
+
+	path := filepath.Join(t.TempDir(), "broken.citylyf")
+	if err := gamefile.SaveTo(sim, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, report, err := gamefile.LoadWithReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() || len(report.Unrepaired()) > 0 {
+		t.Errorf("expected the broken references to be found and repaired, got %s", report)
+	}
+	if _, exists := loaded.People.Households[householdID]; exists {
+		t.Error("expected the household without members to be removed")
+	}
+	if report := loaded.CheckIntegrity(false); !report.OK() {
+		t.Errorf("expected the loaded city to have no issues left, got %s", report)
+	}
+}
+
+// TestLoadCatalogue checks that a catalogue file adds to the default industries and jobs, and is validated
+func TestLoadCatalogue(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "catalogue.json")
+	mining := `{"Industries": ["Mining"], "Jobs": [{"Industry": "Mining", "Job": "Miner", "EducationLevels": ["Unqualified"],
+		"SalaryRange": {"Entry Level": [50000, 60000], "Mid Level": [60000, 70000], "Senior Level": [70000, 80000], "Executive Level": [80000, 90000]},
+		"JobAbundance": 3}]}`
+	if err := os.WriteFile(path, []byte(mining), 0644); err != nil {
+		t.Fatal(err)
+	}
+	catalogue, err := gamefile.LoadCatalogue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(catalogue.Industries, "Mining") || len(catalogue.Jobs) != len(entities.DefaultCatalogue().Jobs)+1 {
+		t.Errorf("mining was not added to the catalogue: %v", catalogue.Industries)
+	}
+
+	overlapping := strings.Replace(mining, "[60000, 70000]", "[55000, 70000]", 1)
+	if err := os.WriteFile(path, []byte(overlapping), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gamefile.LoadCatalogue(path); err == nil {
+		t.Error("catalogue with overlapping salary ranges was loaded")
+	}
+}
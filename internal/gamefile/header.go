@@ -0,0 +1,230 @@
+package gamefile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// ErrNoHeader is returned when reading the header of a save file written without one, such as a JSON save
+var ErrNoHeader = errors.New("save file has no header")
+
+const (
+	maxHeaderSize = 1 << 20 // headers are small, so anything bigger is a corrupt file
+	thumbnailSize = 64      // width and height of the minimap thumbnail in pixels
+)
+
+// SaveHeader describes a save, and is stored at the start of binary save files so that
+// it can be read without decoding the rest of the file
+type SaveHeader struct {
+	CityName   string
+	Date       time.Time
+	Population int
+	Reserves   int
+	PlayTime   time.Duration
+	SavedAt    time.Time
+	Thumbnail  []byte // minimap of the city as a PNG
+}
+
+func newSaveHeader(sim *entities.Simulation) SaveHeader {
+	return SaveHeader{
+		CityName:   sim.CityName,
+		Date:       sim.Date,
+		Population: sim.People.Population(),
+		Reserves:   sim.Government.Reserves,
+		PlayTime:   sim.PlayTime(),
+		SavedAt:    time.Now(),
+		Thumbnail:  thumbnail(sim.Geography),
+	}
+}
+
+// thumbnail draws a minimap of the land use on the map, scaled down to fit the thumbnail
+func thumbnail(g *entities.Geography) []byte {
+	tiles := g.GetTiles()
+	size := min(g.Size, thumbnailSize)
+	if size == 0 || len(tiles) < g.Size {
+		return nil
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for x := range size {
+		for y := range size {
+			img.Set(x, y, tileColour(g, tiles[x*g.Size/size][y*g.Size/size]))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+func tileColour(g *entities.Geography, tile entities.Tile) color.RGBA {
+	switch {
+	case tile.LandUse == entities.TransportUse:
+		return color.RGBA{64, 64, 64, 255}
+	case tile.LandUse == entities.ResidentialUse:
+		return color.RGBA{220, 200, 80, 255}
+	case tile.LandUse == entities.RetailUse:
+		return color.RGBA{80, 140, 220, 255}
+	case tile.LandUse == entities.AgricultureUse:
+		return color.RGBA{170, 150, 90, 255}
+	case tile.LandUse == entities.CommercialUse:
+		return color.RGBA{150, 110, 200, 255}
+	case tile.LandUse == entities.IndustrialUse:
+		return color.RGBA{190, 100, 60, 255}
+	case tile.LandUse == entities.ReserveUse:
+		return color.RGBA{30, 100, 40, 255}
+	case tile.Elevation < g.SeaLevel:
+		return color.RGBA{40, 90, 170, 255}
+	case tile.Elevation >= g.HillLevel:
+		return color.RGBA{130, 130, 120, 255}
+	default:
+		return color.RGBA{90, 160, 70, 255}
+	}
+}
+
+// ReadHeader reads the header at the start of a save file, without decoding the rest of it
+func ReadHeader(path string) (*SaveHeader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read save file: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	if prefix, _ := r.Peek(len(binaryMagic) + 1); !isBinary(prefix) {
+		return nil, ErrNoHeader
+	}
+	headerJSON, _, err := splitBinary(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read save file %s: %w", path, err)
+	}
+	if headerJSON == nil {
+		return nil, ErrNoHeader
+	}
+
+	header := &SaveHeader{}
+	if err := json.Unmarshal(headerJSON, header); err != nil {
+		return nil, fmt.Errorf("could not decode save header %s: %w", path, err)
+	}
+	return header, nil
+}
+
+// SaveFile is a save file in the saves directory, with its header if it has one
+type SaveFile struct {
+	Name    string
+	Path    string
+	ModTime time.Time
+	Header  *SaveHeader
+}
+
+// SavedAt returns when the save was written
+func (s SaveFile) SavedAt() time.Time {
+	if s.Header != nil {
+		return s.Header.SavedAt
+	}
+	return s.ModTime
+}
+
+// ListSaves returns the save files in a directory, reading only their headers
+func ListSaves(dir string) []SaveFile {
+	saves := []SaveFile{}
+	for _, name := range GetDirFiles(dir) {
+		if filepath.Ext(name) == ".journal" || filepath.Ext(name) == ".tmp" {
+			continue // journals and unfinished saves are stored next to the saves
+		}
+
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		save := SaveFile{Name: name, Path: path, ModTime: info.ModTime()}
+		if header, err := ReadHeader(path); err == nil {
+			save.Header = header
+		}
+		saves = append(saves, save)
+	}
+	return saves
+}
+
+// SaveOrder is an order that saves can be listed in
+type SaveOrder int
+
+const (
+	LastSaved SaveOrder = iota // most recently saved first
+	CityDate                   // furthest into the game first, with saves without headers last
+)
+
+func (o SaveOrder) String() string {
+	return [...]string{"Last Saved", "City Date"}[o]
+}
+
+// SortSaves sorts saves in the given order
+func SortSaves(saves []SaveFile, order SaveOrder) {
+	slices.SortStableFunc(saves, func(a, b SaveFile) int {
+		if order == CityDate {
+			var aDate, bDate time.Time
+			if a.Header != nil {
+				aDate = a.Header.Date
+			}
+			if b.Header != nil {
+				bDate = b.Header.Date
+			}
+			if c := bDate.Compare(aDate); c != 0 {
+				return c
+			}
+		}
+		return b.SavedAt().Compare(a.SavedAt())
+	})
+}
+
+// DeleteSave deletes a save file and its journal
+func DeleteSave(path string) error {
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("could not delete save file: %w", err)
+	}
+	if err := os.Remove(JournalPath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not delete journal: %w", err)
+	}
+	return nil
+}
+
+// DuplicateSave copies a save file and its journal to a new name next to it, and returns the copy's path
+func DuplicateSave(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read save file: %w", err)
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext) + " copy"
+	copyPath := base + ext
+	for i := 2; CheckExists(copyPath); i++ {
+		copyPath = fmt.Sprintf("%s %d%s", base, i, ext)
+	}
+
+	if err := writeFileAtomic(copyPath, data); err != nil {
+		return "", fmt.Errorf("could not write save file: %w", err)
+	}
+	if journal, err := os.ReadFile(findJournal(path)); err == nil {
+		if err := writeFileAtomic(JournalPath(copyPath), journal); err != nil {
+			return "", fmt.Errorf("could not write journal: %w", err)
+		}
+	}
+	return copyPath, nil
+}
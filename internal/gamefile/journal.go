@@ -0,0 +1,53 @@
+package gamefile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// JournalPath returns the path of the journal stored next to a save file, named after the whole
+// save file so that saves differing only in their extension keep their own journals
+func JournalPath(savePath string) string {
+	return savePath + ".journal"
+}
+
+// findJournal returns the path of a save file's journal, which saves from before journals were named
+// after the whole save file have in place of its extension
+func findJournal(savePath string) string {
+	if path := JournalPath(savePath); CheckExists(path) {
+		return path
+	}
+	return strings.TrimSuffix(savePath, filepath.Ext(savePath)) + ".journal"
+}
+
+// SaveJournal writes a journal of commands to a file at the specified path
+func SaveJournal(journal *entities.Journal, path string) error {
+	journalJSON, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode journal: %w", err)
+	}
+
+	if err := writeFileAtomic(path, journalJSON); err != nil {
+		return fmt.Errorf("could not write journal: %w", err)
+	}
+	return nil
+}
+
+// LoadJournal reads a journal of commands from a file at the specified path
+func LoadJournal(path string) (*entities.Journal, error) {
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read journal: %w", err)
+	}
+
+	journal := &entities.Journal{}
+	if err := json.Unmarshal(fileData, journal); err != nil {
+		return nil, fmt.Errorf("could not decode journal %s: %w", path, err)
+	}
+	return journal, nil
+}
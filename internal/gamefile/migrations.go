@@ -0,0 +1,154 @@
+package gamefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// CurrentVersion is the version of the save file format written by Save
+const CurrentVersion = 6
+
+// ErrNewerVersion is returned when loading a save file written by a newer version of the game
+var ErrNewerVersion = errors.New("save file is from a newer version of the game")
+
+// migrations upgrade a decoded save file from version i+1 to version i+2.
+// Save files from before versioning have no version, and are version 1.
+var migrations = []func(save map[string]any) error{
+	migrateV1toV2,
+	migrateV2toV3,
+	migrateV3toV4,
+	migrateV4toV5,
+	migrateV5toV6,
+}
+
+// migrate upgrades save file data to the current version
+func migrate(data []byte) ([]byte, error) {
+	save := map[string]any{}
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, err
+	}
+
+	version := 1
+	if v, ok := save["Version"].(float64); ok {
+		version = int(v)
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrNewerVersion, version)
+	}
+	if version == CurrentVersion {
+		return data, nil
+	}
+
+	for ; version < CurrentVersion; version++ {
+		if err := migrations[version-1](save); err != nil {
+			return nil, fmt.Errorf("migrating from version %d: %w", version, err)
+		}
+	}
+	save["Version"] = CurrentVersion
+	return json.Marshal(save)
+}
+
+// migrateV1toV2 adds the tick number and terrain generation probabilities
+func migrateV1toV2(save map[string]any) error {
+	if _, ok := save["Sim"].(map[string]any); !ok {
+		return errors.New("save file has no simulation")
+	}
+	save["TickNumber"] = 0
+	save["PeakProbability"] = entities.DefaultPeakProbability
+	save["RangeProbability"] = entities.DefaultRangeProbability
+	save["CliffProbability"] = entities.DefaultCliffProbability
+	return nil
+}
+
+// migrateV2toV3 gives every company the cash a new company starts with
+func migrateV2toV3(save map[string]any) error {
+	sim, ok := save["Sim"].(map[string]any)
+	if !ok {
+		return errors.New("save file has no simulation")
+	}
+	companies, _ := sim["Companies"].(map[string]any)
+	for _, c := range companies {
+		if company, ok := c.(map[string]any); ok {
+			fixedCosts, _ := company["FixedCosts"].(float64)
+			company["Cash"] = fixedCosts * entities.CashReserveMonths
+		}
+	}
+	return nil
+}
+
+// migrateV3toV4 gives every company the assets a new company starts with, enough to employ its staff
+// and fill its openings
+func migrateV3toV4(save map[string]any) error {
+	sim, ok := save["Sim"].(map[string]any)
+	if !ok {
+		return errors.New("save file has no simulation")
+	}
+	companies, _ := sim["Companies"].(map[string]any)
+	for _, c := range companies {
+		company, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		revenue, _ := company["LastRevenue"].(float64)
+		if revenue <= 0 {
+			revenue, _ = company["FixedCosts"].(float64)
+		}
+		employees, _ := company["Employees"].([]any)
+		jobs := len(employees)
+		openings, _ := company["JobOpenings"].(map[string]any)
+		for _, o := range openings {
+			if n, ok := o.(float64); ok {
+				jobs += int(n)
+			}
+		}
+		assets := revenue * entities.AssetMonths
+		company["Assets"] = assets
+		company["CapitalPerJob"] = assets / float64(max(jobs, 1))
+	}
+	return nil
+}
+
+// migrateV4toV5 hands every house to the landlord, priced at what its rent sells for at the city's
+// interest rate
+func migrateV4toV5(save map[string]any) error {
+	sim, ok := save["Sim"].(map[string]any)
+	if !ok {
+		return errors.New("save file has no simulation")
+	}
+	interestRate := 0.0
+	market, _ := sim["Market"].(map[string]any)
+	history, _ := market["History"].(map[string]any)
+	if rates, _ := history["InterestRate"].([]any); len(rates) > 0 {
+		interestRate, _ = rates[len(rates)-1].(float64)
+	}
+	houses, _ := sim["Houses"].(map[string]any)
+	for _, h := range houses {
+		house, ok := h.(map[string]any)
+		if !ok {
+			continue
+		}
+		rent, _ := house["MonthlyRent"].(float64)
+		house["Owner"] = entities.OwnerLandlord
+		house["Price"] = entities.HousePrice(int(rent), interestRate)
+	}
+	return nil
+}
+
+// migrateV5toV6 drops the dates of the next rate revision and the last tax collection, as rates are now
+// revised every quarter and taxes collected every first of January
+func migrateV5toV6(save map[string]any) error {
+	sim, ok := save["Sim"].(map[string]any)
+	if !ok {
+		return errors.New("save file has no simulation")
+	}
+	if market, ok := sim["Market"].(map[string]any); ok {
+		delete(market, "NextRateRevision")
+	}
+	if government, ok := sim["Government"].(map[string]any); ok {
+		delete(government, "LastCalculationYear")
+	}
+	return nil
+}
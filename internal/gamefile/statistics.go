@@ -0,0 +1,65 @@
+package gamefile
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// ExportStatistics writes the city's statistics to a file at the specified path,
+// as JSON if the path ends in .json and as CSV otherwise
+func ExportStatistics(statistics *entities.Statistics, path string) error {
+	var buf bytes.Buffer
+	write := statistics.WriteCSV
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		write = statistics.WriteJSON
+	}
+	if err := write(&buf); err != nil {
+		return fmt.Errorf("could not encode statistics: %w", err)
+	}
+
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return fmt.Errorf("could not write statistics: %w", err)
+	}
+	return nil
+}
+
+// ExportCityStatistics writes the city's statistics as CSV and JSON to the exports
+// directory, named after the city, and returns the paths written to
+func ExportCityStatistics(sim *entities.Simulation) ([]string, error) {
+	dir := GetExportsDir()
+	if dir == "" {
+		return nil, fmt.Errorf("no exports directory")
+	}
+
+	paths := []string{}
+	for _, ext := range []string{".csv", ".json"} {
+		path := filepath.Join(dir, strings.ToLower(sim.CityName)+"-statistics"+ext)
+		if err := ExportStatistics(sim.Statistics, path); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// GetExportsDir returns the directory where exported statistics are stored.
+func GetExportsDir() string {
+	exportsdir := ""
+	if homedir, err := os.UserHomeDir(); err == nil {
+		exportsdir = homedir + "/.citylyf/exports"
+		if err := os.MkdirAll(exportsdir, os.ModePerm); err != nil {
+			log.Println(err)
+			exportsdir = ""
+		}
+	} else {
+		log.Println(err)
+	}
+
+	return exportsdir
+}
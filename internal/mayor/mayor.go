@@ -0,0 +1,127 @@
+package mayor
+
+import (
+	"slices"
+	"time"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// Mayor plays the city, deciding every month which commands to give it
+type Mayor interface {
+	Name() string
+	Decide(view *View) []entities.Command
+}
+
+// View is a read-only copy of the parts of the city a mayor can see, so that a mayor can't change
+// the city other than through its commands
+type View struct {
+	Date                        time.Time
+	Stats                       entities.Stats
+	HousingDemand, RetailDemand float64
+	Unsited                     map[entities.LandUse]int // companies waiting for premises in each zone
+	Taxes                       entities.SetTaxRatesCommand
+	Size, SeaLevel, HillLevel   int
+	Tiles                       [][]entities.Tile
+}
+
+// NewView copies the parts of the city a mayor can see
+func NewView(sim *entities.Simulation) *View {
+	tiles := sim.Geography.GetTiles()
+	view := &View{
+		Date:          sim.Date,
+		Stats:         sim.GetStatsSnapshot(),
+		HousingDemand: sim.Market.HousingDemand,
+		RetailDemand:  sim.Market.RetailDemand,
+		Unsited:       sim.Companies.Unsited(sim),
+		Taxes: entities.SetTaxRatesCommand{
+			CorporateTaxRate:  sim.Government.CorporateTaxRate,
+			SalesTaxRate:      sim.Government.SalesTaxRate,
+			IncomeTaxBrackets: slices.Clone(sim.Government.IncomeTaxBrackets),
+		},
+		Size:      sim.Geography.Size,
+		SeaLevel:  sim.Geography.SeaLevel,
+		HillLevel: sim.Geography.HillLevel,
+		Tiles:     make([][]entities.Tile, len(tiles)),
+	}
+	for x := range tiles {
+		view.Tiles[x] = slices.Clone(tiles[x])
+	}
+	return view
+}
+
+// InBounds returns true if x, y is on the map
+func (v *View) InBounds(x, y int) bool {
+	return x >= 0 && y >= 0 && x < v.Size && y < v.Size
+}
+
+// IsRoad returns true if there is a road at x, y
+func (v *View) IsRoad(x, y int) bool {
+	return v.InBounds(x, y) && v.Tiles[x][y].LandUse == entities.TransportUse
+}
+
+// IsFree returns true if x, y can be built on and has not been zoned or built on yet
+func (v *View) IsFree(x, y int) bool {
+	if !v.InBounds(x, y) {
+		return false
+	}
+	tile := v.Tiles[x][y]
+	return tile.LandUse == entities.NoUse && tile.IsBuildable(&entities.Geography{SeaLevel: v.SeaLevel, HillLevel: v.HillLevel})
+}
+
+// CanBuildRoad returns true if a road can be built at x, y, which it can on land that has been zoned
+// but not built on yet
+func (v *View) CanBuildRoad(x, y int) bool {
+	if !v.InBounds(x, y) {
+		return false
+	}
+	tile := v.Tiles[x][y]
+	return !tile.IsBuilt() && tile.IsBuildable(&entities.Geography{SeaLevel: v.SeaLevel, HillLevel: v.HillLevel})
+}
+
+// IsZonable returns true if x, y is free and next to a road, so that it can be zoned
+func (v *View) IsZonable(x, y int) bool {
+	return v.IsFree(x, y) && (v.IsRoad(x+1, y) || v.IsRoad(x-1, y) || v.IsRoad(x, y+1) || v.IsRoad(x, y-1))
+}
+
+// Vacant returns the number of tiles zoned for a land use that have not been built on yet
+func (v *View) Vacant(use entities.LandUse) int {
+	vacant := 0
+	for x := range v.Tiles {
+		for _, tile := range v.Tiles[x] {
+			if tile.LandUse == use && tile.LandStatus == entities.UndevelopedStatus {
+				vacant++
+			}
+		}
+	}
+	return vacant
+}
+
+// Zoned returns the number of tiles zoned for a land use
+func (v *View) Zoned(use entities.LandUse) int {
+	zoned := 0
+	for x := range v.Tiles {
+		for _, tile := range v.Tiles[x] {
+			if tile.LandUse == use {
+				zoned++
+			}
+		}
+	}
+	return zoned
+}
+
+// System runs a mayor as a scheduler system, giving the city the mayor's commands through its
+// journal so that a game played by a mayor can be replayed without it
+type System struct {
+	Mayor Mayor
+}
+
+func (s *System) Name() string {
+	return "mayor"
+}
+
+func (s *System) Update(sim *entities.Simulation) {
+	for _, command := range s.Mayor.Decide(NewView(sim)) {
+		sim.Execute(command)
+	}
+}
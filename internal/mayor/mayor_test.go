@@ -0,0 +1,54 @@
+package mayor_test
+
+import (
+	"testing"
+
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/mayor"
+)
+
+// TestView checks that a mayor's view of the city is a copy, so changing it doesn't change the city
+func TestView(t *testing.T) {
+	sim := entities.NewSimulation(2020, 1000000, 42)
+	view := mayor.NewView(sim)
+	if view.Size != sim.Geography.Size || len(view.Tiles) != view.Size {
+		t.Fatalf("expected a %d tile map, got %d", sim.Geography.Size, len(view.Tiles))
+	}
+
+	view.Tiles[0][0].LandUse = entities.TransportUse
+	view.Taxes.IncomeTaxBrackets[0].Rate = 99
+	if sim.Geography.GetTiles()[0][0].LandUse == entities.TransportUse || sim.Government.IncomeTaxBrackets[0].Rate == 99 {
+		t.Error("expected changing the view not to change the city")
+	}
+}
+
+// TestPlanner checks that the planner builds a road on an empty map, and then zones land along it
+func TestPlanner(t *testing.T) {
+	sim := entities.NewSimulation(2020, 1000000, 42)
+	planner := &mayor.Planner{}
+
+	commands := planner.Decide(mayor.NewView(sim))
+	if len(commands) != 1 {
+		t.Fatalf("expected a road to be built on an empty map, got %v", commands)
+	}
+	road, ok := commands[0].(entities.PlaceRoadCommand)
+	if !ok || road.Start.Y != road.End.Y || road.RoadType != entities.Chipseal {
+		t.Fatalf("expected a straight chipseal road, got %v", commands[0])
+	}
+	sim.Execute(road)
+
+	uses := map[entities.LandUse]bool{}
+	for _, command := range planner.Decide(mayor.NewView(sim)) {
+		if zone, ok := command.(entities.PlaceLandUseCommand); ok {
+			uses[zone.Use] = true
+			sim.Execute(zone)
+		}
+	}
+	if !uses[entities.ResidentialUse] || !uses[entities.AgricultureUse] {
+		t.Errorf("expected residential and agricultural zones, got %v", uses)
+	}
+	view := mayor.NewView(sim)
+	if view.Vacant(entities.ResidentialUse) < 3 || view.Zoned(entities.ResidentialUse) != view.Vacant(entities.ResidentialUse) {
+		t.Errorf("expected at least 3 vacant residential tiles, got %d", view.Vacant(entities.ResidentialUse))
+	}
+}
@@ -0,0 +1,216 @@
+package mayor
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// Planner is a simple mayor that grows the city where there is demand: it zones land next to roads
+// for housing and shops when their demand is up and the zoned land has run out, builds roads out
+// from the centre when there is nowhere left to zone, and raises taxes when the reserves run low
+type Planner struct {
+	RoadType   entities.RoadType // type of road to build, chipseal if empty
+	RoadLength int               // length of new roads, 8 if zero
+}
+
+func (p *Planner) Name() string {
+	return "planner"
+}
+
+const (
+	minReserves      = 50000  // reserves below which no roads are built
+	lowReserves      = 100000 // reserves below which taxes are raised
+	maxTaxRate       = 25
+	zoneHalfLength   = 2 // zones are placed along 5 tiles of road, on both sides
+	minZonableTiles  = 3
+	highUnemployment = 5 // percentage of the workforce out of work
+)
+
+func (p *Planner) Decide(view *View) []entities.Command {
+	commands := []entities.Command{}
+	roadNeeded := false
+
+	needs := []struct {
+		use    entities.LandUse
+		needed bool
+	}{
+		{entities.ResidentialUse, (view.HousingDemand > 0.05 || view.Stats.FreeHouses < 3) && view.Vacant(entities.ResidentialUse) < 6},
+		{entities.RetailUse, view.RetailDemand > 0.01 && view.Vacant(entities.RetailUse) < 2},
+		{entities.AgricultureUse, view.Vacant(entities.AgricultureUse) < 2 && view.Zoned(entities.AgricultureUse) < 4+view.Stats.Population/200},
+		{entities.CommercialUse, view.Vacant(entities.CommercialUse) < p.sitesNeeded(view, entities.CommercialUse)},
+		{entities.IndustrialUse, view.Vacant(entities.IndustrialUse) < p.sitesNeeded(view, entities.IndustrialUse)},
+	}
+	for _, need := range needs {
+		if !need.needed {
+			continue
+		}
+		if zone := p.findZone(view, need.use); zone != nil {
+			commands = append(commands, *zone)
+			for x := min(zone.Start.X, zone.End.X); x <= max(zone.Start.X, zone.End.X); x++ { // so the next use zones elsewhere
+				for y := min(zone.Start.Y, zone.End.Y); y <= max(zone.Start.Y, zone.End.Y); y++ {
+					if view.IsZonable(x, y) {
+						view.Tiles[x][y].LandUse = need.use
+					}
+				}
+			}
+		} else {
+			roadNeeded = true
+		}
+	}
+
+	if roadNeeded && view.Stats.Reserves > minReserves {
+		if road := p.findRoad(view); road != nil {
+			commands = append(commands, *road)
+		}
+	}
+
+	if view.Stats.Reserves < lowReserves && view.Taxes.CorporateTaxRate < maxTaxRate {
+		taxes := view.Taxes
+		taxes.CorporateTaxRate = min(taxes.CorporateTaxRate+1, maxTaxRate)
+		taxes.SalesTaxRate = min(taxes.SalesTaxRate+0.5, maxTaxRate)
+		commands = append(commands, taxes)
+	}
+
+	return commands
+}
+
+// roadTiles returns the road tiles, nearest to the centre of the map first
+func roadTiles(view *View) []entities.Point {
+	tiles := []entities.Point{}
+	for x := range view.Tiles {
+		for y := range view.Tiles[x] {
+			if view.IsRoad(x, y) {
+				tiles = append(tiles, entities.Point{X: x, Y: y})
+			}
+		}
+	}
+	centre := view.Size / 2
+	slices.SortStableFunc(tiles, func(a, b entities.Point) int {
+		return cmp.Compare(distance(a, centre), distance(b, centre))
+	})
+	return tiles
+}
+
+func distance(p entities.Point, centre int) int {
+	return max(p.X-centre, centre-p.X) + max(p.Y-centre, centre-p.Y)
+}
+
+// sitesNeeded returns the number of vacant sites wanted in a company zone: one for each company
+// waiting for premises, and one more for new companies when people are out of work
+func (p *Planner) sitesNeeded(view *View, use entities.LandUse) int {
+	needed := view.Unsited[use]
+	if view.Stats.Unemployment > highUnemployment {
+		needed++
+	}
+	return needed
+}
+
+// findZone finds a stretch of road with free land alongside it. Shops, offices and houses go as near
+// to the centre as they can, and farms and factories as far away.
+func (p *Planner) findZone(view *View, use entities.LandUse) *entities.PlaceLandUseCommand {
+	roads := roadTiles(view)
+	if use == entities.AgricultureUse || use == entities.IndustrialUse {
+		slices.Reverse(roads)
+	}
+
+	for _, road := range roads {
+		alongX := view.IsRoad(road.X-1, road.Y) || view.IsRoad(road.X+1, road.Y)
+		start, end := entities.Point{X: road.X - zoneHalfLength, Y: road.Y - 1}, entities.Point{X: road.X + zoneHalfLength, Y: road.Y + 1}
+		if !alongX {
+			start, end = entities.Point{X: road.X - 1, Y: road.Y - zoneHalfLength}, entities.Point{X: road.X + 1, Y: road.Y + zoneHalfLength}
+		}
+
+		zonable := 0
+		for x := start.X; x <= end.X; x++ {
+			for y := start.Y; y <= end.Y; y++ {
+				if view.IsZonable(x, y) {
+					zonable++
+				}
+			}
+		}
+		if zonable >= minZonableTiles {
+			start = entities.Point{X: max(start.X, 0), Y: max(start.Y, 0)}
+			end = entities.Point{X: min(end.X, view.Size-1), Y: min(end.Y, view.Size-1)}
+			return &entities.PlaceLandUseCommand{Start: start, End: end, Use: use}
+		}
+	}
+	return nil
+}
+
+// findRoad finds where to build the next road: across the middle of the map if there are no roads,
+// otherwise branching off the road nearest the centre that has room for one
+func (p *Planner) findRoad(view *View) *entities.PlaceRoadCommand {
+	roadType, length := p.RoadType, p.RoadLength
+	if roadType == "" {
+		roadType = entities.Chipseal
+	}
+	if length == 0 {
+		length = 8
+	}
+
+	roads := roadTiles(view)
+	if len(roads) == 0 {
+		return p.findFirstRoad(view, roadType, length*2)
+	}
+
+	directions := []entities.Point{{X: 0, Y: 1}, {X: 0, Y: -1}, {X: 1, Y: 0}, {X: -1, Y: 0}}
+	for ; length >= 3; length /= 2 { // shorter roads fit in where longer ones don't
+		for _, road := range roads {
+			for _, d := range directions {
+				if p.canBranch(view, road, d, length) {
+					end := entities.Point{X: road.X + d.X*length, Y: road.Y + d.Y*length}
+					return &entities.PlaceRoadCommand{Start: road, End: end, RoadType: roadType}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// canBranch returns true if a road can be built from a road tile in a direction, over land that has
+// not been built on and is not right next to another road
+func (p *Planner) canBranch(view *View, from, d entities.Point, length int) bool {
+	if view.IsRoad(from.X+d.X, from.Y+d.Y) {
+		return false
+	}
+	side := entities.Point{X: d.Y, Y: d.X}
+	for i := 1; i <= length; i++ {
+		x, y := from.X+d.X*i, from.Y+d.Y*i
+		if !view.CanBuildRoad(x, y) {
+			return false
+		}
+		if i > 1 && (view.IsRoad(x+side.X, y+side.Y) || view.IsRoad(x-side.X, y-side.Y)) {
+			return false
+		}
+	}
+	return !view.IsRoad(from.X+d.X*(length+1), from.Y+d.Y*(length+1))
+}
+
+// findFirstRoad finds the row nearest the middle of the map with a long enough stretch of free land
+func (p *Planner) findFirstRoad(view *View, roadType entities.RoadType, length int) *entities.PlaceRoadCommand {
+	centre := view.Size / 2
+	for offset := 0; offset < view.Size; offset++ {
+		y := centre + offset/2*(1-offset%2*2) // centre, centre-1, centre+1, centre-2 and so on
+		if !view.InBounds(0, y) {
+			continue
+		}
+
+		runStart, bestStart, bestLength := 0, 0, 0
+		for x := 0; x <= view.Size; x++ {
+			if x < view.Size && view.IsFree(x, y) {
+				continue
+			}
+			if x-runStart > bestLength {
+				bestStart, bestLength = runStart, x-runStart
+			}
+			runStart = x + 1
+		}
+		if bestLength >= length {
+			start := bestStart + (bestLength-length)/2
+			return &entities.PlaceRoadCommand{Start: entities.Point{X: start, Y: y}, End: entities.Point{X: start + length - 1, Y: y}, RoadType: roadType}
+		}
+	}
+	return nil
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // getAge generates a random age based on a bell curve
-func getAge(mean, stdDev float64, minAge, maxAge int) (years int, months int) {
+func getAge(rng *rand.Rand, mean, stdDev float64, minAge, maxAge int) (years int, months int) {
 	if minAge < 0 {
 		minAge = 0
 	}
@@ -19,7 +19,7 @@ func getAge(mean, stdDev float64, minAge, maxAge int) (years int, months int) {
 
 	maxCalculations := 100
 	for i := 0; i < maxCalculations; i++ {
-		age := mean + rand.NormFloat64()*stdDev
+		age := mean + rng.NormFloat64()*stdDev
 
 		// Ensure age is within bounds
 		if age >= float64(minAge) && age <= float64(maxAge) {
@@ -27,21 +27,22 @@ func getAge(mean, stdDev float64, minAge, maxAge int) (years int, months int) {
 		}
 	}
 
-	return int(minAge + rand.IntN(maxAge-minAge)), 1 + rand.IntN(12)
+	return int(minAge + rng.IntN(maxAge-minAge)), 1 + rng.IntN(12)
 }
 
 // getRandomBirthdate generates a random birthdate given the age
-func getRandomBirthdate(ageY int, ageM int) time.Time {
-	currentDate := entities.Sim.Date
+func getRandomBirthdate(sim *entities.Simulation, ageY int, ageM int) time.Time {
+	currentDate := sim.Date
+	rng := sim.Rand()
 	year := currentDate.Year() - ageY
 
 	// Generate a random day based on the month and year
 	// Use time.Date to determine the last day of the month
-	day := rand.IntN(time.Date(year, time.Month(ageM), 0, 0, 0, 0, 0, time.UTC).Day()) + 1
+	day := rng.IntN(time.Date(year, time.Month(ageM), 0, 0, 0, 0, 0, time.UTC).Day()) + 1
 	birthdate := time.Date(year, time.Month(ageM), day, 0, 0, 0, 0, time.UTC)
 
 	if currentDate.Before(birthdate) {
-		return currentDate.AddDate(0, -rand.IntN(12), -rand.IntN(28))
+		return currentDate.AddDate(0, -rng.IntN(12), -rng.IntN(28))
 	} else {
 		return birthdate
 	}
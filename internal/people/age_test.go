@@ -2,6 +2,7 @@ package people
 
 import (
 	"math"
+	"math/rand/v2"
 	"testing"
 )
 
@@ -12,9 +13,10 @@ func TestGetAge(t *testing.T) {
 	minAge := 18
 	maxAge := 65
 	iterations := 10000
+	rng := rand.New(rand.NewPCG(1, 2))
 
 	for i := 0; i < iterations; i++ {
-		ageY, ageM := getAge(mean, stdDev, minAge, maxAge)
+		ageY, ageM := getAge(rng, mean, stdDev, minAge, maxAge)
 
 		if ageY < minAge || ageY > maxAge {
 			t.Errorf("getAge returned %dY %dM, which is outside the range [%d, %d]", ageY, ageM, minAge, maxAge)
@@ -34,8 +36,9 @@ func TestGetAgeEdgeCases(t *testing.T) {
 		{30, 10, -5, 10}, // Negative minAge should be corrected to 0
 	}
 
+	rng := rand.New(rand.NewPCG(1, 2))
 	for _, tt := range tests {
-		ageY, ageM := getAge(tt.mean, tt.stdDev, tt.minAge, tt.maxAge)
+		ageY, ageM := getAge(rng, tt.mean, tt.stdDev, tt.minAge, tt.maxAge)
 
 		expectedMin := int(math.Max(float64(tt.minAge), 0)) // Ensure no negative min
 		expectedMax := int(math.Max(float64(tt.maxAge), float64(expectedMin)))
@@ -1,86 +1,94 @@
 package people
 
 import (
-	"fmt"
-	"math/rand/v2"
-
 	"github.com/janithl/citylyf/internal/entities"
 	"github.com/janithl/citylyf/internal/utils"
 )
 
-func SimulateLifecycle() {
-	for _, person := range entities.Sim.People.People {
+func SimulateLifecycle(sim *entities.Simulation) {
+	rng := sim.Rand()
+	for _, id := range sim.People.GetPersonIDs() {
+		person := sim.People.GetPerson(id)
+		if person == nil { // removed earlier in this loop
+			continue
+		}
+
 		// --- Retirement ---
 		// Assume a normal distribution for the age of retirement
-		retirementAge := entities.MeanRetirementAge + rand.NormFloat64()*entities.StdDevRetirementAge
-		if person.Age() >= int(retirementAge) && person.CareerLevel != entities.Retired &&
-			rand.Float64() < 1/(entities.DaysPerYear*entities.StdDevRetirementAge*2) { // probability of retirement is spread out over a 5 year period
-			entities.Sim.Companies.RemoveEmployeeFromTheirCompany(person)
+		retirementAge := entities.MeanRetirementAge + rng.NormFloat64()*entities.StdDevRetirementAge
+		if person.Age(sim.Date) >= int(retirementAge) && person.CareerLevel != entities.Retired &&
+			rng.Float64() < 1/(entities.DaysPerYear*entities.StdDevRetirementAge*2) { // probability of retirement is spread out over a 5 year period
+			sim.Companies.RemoveEmployeeFromTheirCompany(person)
 			person.CareerLevel = entities.Retired
-			fmt.Printf("[  Job ] %s %s (%d) has retired\n", person.FirstName, person.FamilyName, person.Age())
+			sim.Events().Publish(entities.PersonRetired{FirstName: person.FirstName, FamilyName: person.FamilyName, Age: person.Age(sim.Date)})
 		}
 
 		// --- Marriage ---
-		if person.Relationship != entities.Married && person.Age() >= entities.AgeOfAdulthood {
+		if person.Relationship != entities.Married && person.Age(sim.Date) >= entities.AgeOfAdulthood {
 			// Calculate the probability of marriage for the current person's age
 			marriageProbability := utils.CalculateProbabilityByAge(entities.MeanMarriageAge, entities.StdDevMarriageAge,
-				float64(person.Age()), entities.ProbabilityOfMarriage/entities.DaysPerYear)
+				float64(person.Age(sim.Date)), entities.ProbabilityOfMarriage/entities.DaysPerYear)
 
-			if rand.Float64() < marriageProbability {
-				if candidate := findMarriageCandidate(person); candidate != nil {
-					Marry(person, candidate)
+			if rng.Float64() < marriageProbability {
+				if candidate := findMarriageCandidate(sim, person); candidate != nil {
+					Marry(sim, person, candidate)
 				}
 			}
 		}
 
 		// --- Childbirth Probability ---
-		if person.Gender == entities.Female && person.Age() > entities.AgeOfAdulthood && person.Age() < entities.AgeOfMenopause {
+		if person.Gender == entities.Female && person.Age(sim.Date) > entities.AgeOfAdulthood && person.Age(sim.Date) < entities.AgeOfMenopause {
 			// Calculate the probability of childbirth for the current person's age
 			childbirthProbability := utils.CalculateProbabilityByAge(entities.MeanChildbirthAge, entities.StdDevChildbirthAge,
-				float64(person.Age()), entities.ProbabilityOfChildbirth/entities.DaysPerYear)
+				float64(person.Age(sim.Date)), entities.ProbabilityOfChildbirth/entities.DaysPerYear)
 
-			if rand.Float64() < childbirthProbability {
+			if rng.Float64() < childbirthProbability {
 				var partner, baby *entities.Person
 				if person.Relationship == entities.Married {
-					partner = entities.Sim.People.GetSpouse(person.ID)
+					partner = sim.People.GetSpouse(person.ID)
 				}
 
-				kids := createKids(person, partner, 1)
+				kids := createKids(sim, person, partner, 1)
 				if len(kids) < 1 {
 					continue
 				}
 
 				baby = kids[0]
-				baby.ID = entities.Sim.GetNextID()
-				baby.Birthdate = entities.Sim.Date
-				entities.Sim.People.AddPerson(baby)
-				if household := entities.Sim.People.GetHouseholdByPersonID(person.ID); household != nil {
+				baby.ID = sim.GetNextID()
+				baby.Birthdate = sim.Date
+				sim.People.AddPerson(baby)
+				if household := sim.People.GetHouseholdByPersonID(person.ID); household != nil {
 					household.MemberIDs = append(household.MemberIDs, baby.ID)
 				}
-				fmt.Printf("[ Baby ] %s %s has been born!\n", baby.FirstName, baby.FamilyName)
+				sim.Events().Publish(entities.Birth{FirstName: baby.FirstName, FamilyName: baby.FamilyName})
 			}
 		}
 
 		// --- Moving Out of Home ---
-		if person.Age() >= entities.AgeOfAdulthood && person.Relationship != entities.Married {
-			oldHousehold := entities.Sim.People.GetHouseholdByPersonID(person.ID)
-			if oldHousehold == nil || oldHousehold.GetAdultCount() < 2 {
+		if person.Age(sim.Date) >= entities.AgeOfAdulthood && person.Relationship != entities.Married {
+			oldHousehold := sim.People.GetHouseholdByPersonID(person.ID)
+			if oldHousehold == nil || oldHousehold.GetAdultCount(sim.People, sim.Date) < 2 {
 				continue // already head adult, no need to move out
 			}
 
-			if rand.Float64() < entities.ProbabilityOfMovingOut/entities.DaysPerYear { // annual rate spread out over each day of the year
+			if rng.Float64() < entities.ProbabilityOfMovingOut/entities.DaysPerYear { // annual rate spread out over each day of the year
 				newHousehold := &entities.Household{
-					ID:         entities.Sim.GetNextID(),
+					ID:         sim.GetNextID(),
 					MemberIDs:  []int{person.ID},
-					MoveInDate: entities.Sim.Date,
-					LastPayDay: entities.Sim.Date,
+					MoveInDate: sim.Date,
+					LastPayDay: sim.Date,
 					Savings:    person.Savings,
 				}
-				if houseID := newHousehold.FindHousing(); houseID > 0 { // only move out if we can find new housing
+				if houseID := newHousehold.FindHousing(sim); houseID > 0 { // only move out if we can find new housing
 					oldHousehold.RemoveMember(person)
-					entities.Sim.People.Households[newHousehold.ID] = newHousehold
-					fmt.Printf("[ Move ] %s %s (%d) has moved into house #%d, %d houses remain\n", person.FirstName,
-						person.FamilyName, person.Age(), houseID, entities.Sim.Houses.GetFreeHouses())
+					sim.People.Households[newHousehold.ID] = newHousehold
+					sim.Events().Publish(entities.LeftHome{
+						FirstName:  person.FirstName,
+						FamilyName: person.FamilyName,
+						Age:        person.Age(sim.Date),
+						HouseID:    houseID,
+						FreeHouses: sim.Houses.GetFreeHouses(),
+					})
 				}
 			}
 		}
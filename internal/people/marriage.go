@@ -1,51 +1,59 @@
 package people
 
 import (
-	"fmt"
 	"math"
-	"math/rand"
 
 	"github.com/janithl/citylyf/internal/entities"
 )
 
 // Marry simulates marriage between two people.
-func Marry(person1 *entities.Person, person2 *entities.Person) {
+func Marry(sim *entities.Simulation, person1 *entities.Person, person2 *entities.Person) {
 	if person1.Relationship == entities.Married || person2.Relationship == entities.Married ||
-		person1.Age() < entities.AgeOfAdulthood || person2.Age() < entities.AgeOfAdulthood {
+		person1.Age(sim.Date) < entities.AgeOfAdulthood || person2.Age(sim.Date) < entities.AgeOfAdulthood {
 		return // cannot marry if already married or not an adult
 	}
 
 	person1.Relationship = entities.Married
 	person2.Relationship = entities.Married
 
-	fmt.Printf("[ Weds ] Wedding bells as %s %s (%d) marries %s %s (%d)!\n", person1.FirstName,
-		person1.FamilyName, person1.Age(), person2.FirstName, person2.FamilyName, person2.Age())
+	sim.Events().Publish(entities.Marriage{
+		FirstName1: person1.FirstName, FamilyName1: person1.FamilyName, Age1: person1.Age(sim.Date),
+		FirstName2: person2.FirstName, FamilyName2: person2.FamilyName, Age2: person2.Age(sim.Date),
+	})
 
-	p1household := entities.Sim.People.GetHouseholdByPersonID(person1.ID)
-	p2household := entities.Sim.People.GetHouseholdByPersonID(person2.ID)
+	p1household := sim.People.GetHouseholdByPersonID(person1.ID)
+	p2household := sim.People.GetHouseholdByPersonID(person2.ID)
 
 	if p1household != nil {
-		if p1household.GetAdultCount() == 1 {
+		if p1household.GetAdultCount(sim.People, sim.Date) == 1 {
 			// Person1 is the only adult in the household, so add Person2 to the same household
 			p1household.AddMember(person2.ID, person2.Savings)
-			fmt.Printf("[ Weds ] %s moves in with the %s family\n", person2.FirstName, p1household.FamilyName())
+			sim.Events().Publish(entities.JoinedHousehold{FirstName: person2.FirstName, HouseholdName: p1household.FamilyName(sim.People), Newlywed: true})
 		} else {
 			p1household.RemoveMember(person1)
 		}
 	}
 
 	if p2household != nil {
-		if p2household.GetAdultCount() == 1 {
+		if p2household.GetAdultCount(sim.People, sim.Date) == 1 {
 			// Person2 is the only adult in the household, so add Person1 to the same household or combine households
 			if p1household.IsMember(person2.ID) {
 				for _, id := range p2household.MemberIDs {
-					p1household.AddMember(id, 0)
+					if id != person2.ID {
+						p1household.AddMember(id, 0)
+					}
 				}
-				fmt.Printf("[ Weds ] %s and %s families combine\n", p1household.FamilyName(), p2household.FamilyName())
-				delete(entities.Sim.People.Households, p2household.ID)
+				sim.Events().Publish(entities.HouseholdsCombined{HouseholdName: p1household.FamilyName(sim.People), CombinedName: p2household.FamilyName(sim.People)})
+
+				// the household sells its house if it owns it, and brings what it has left and still owes
+				// along, less person2's savings that came with them
+				sim.Houses.MoveOut(sim, p2household.HouseID)
+				p1household.Savings += p2household.Savings - person2.Savings
+				sim.Bank.TransferLoans(p2household.ID, p1household.ID)
+				delete(sim.People.Households, p2household.ID)
 			} else {
 				p2household.AddMember(person1.ID, person1.Savings)
-				fmt.Printf("[ Move ] %s moves in with the %s family\n", person1.FirstName, p2household.FamilyName())
+				sim.Events().Publish(entities.JoinedHousehold{FirstName: person1.FirstName, HouseholdName: p2household.FamilyName(sim.People)})
 			}
 		} else {
 			p2household.RemoveMember(person2)
@@ -59,36 +67,37 @@ func Marry(person1 *entities.Person, person2 *entities.Person) {
 	}
 
 	household := &entities.Household{
-		ID:         entities.Sim.GetNextID(),
+		ID:         sim.GetNextID(),
 		MemberIDs:  []int{person1.ID, person2.ID},
-		MoveInDate: entities.Sim.Date,
-		LastPayDay: entities.Sim.Date,
+		MoveInDate: sim.Date,
+		LastPayDay: sim.Date,
 		Savings:    person1.Savings + person2.Savings,
 	}
 
-	if houseID := household.FindHousing(); houseID > 0 {
-		entities.Sim.People.Households[household.ID] = household
+	if houseID := household.FindHousing(sim); houseID > 0 {
+		sim.People.Households[household.ID] = household
 	} else {
-		fmt.Printf("[ Move ] The newlywed %s family has been unable to find housing, and has moved out of the city\n", household.FamilyName())
-		RemoveHousehold(household)
+		sim.Events().Publish(entities.HouseholdMovedOut{HouseholdName: household.FamilyName(sim.People)})
+		RemoveHousehold(sim, household)
 	}
 }
 
 // findMarriageCandidates finds a suitable marriage candidate for a person.
-func findMarriageCandidate(person *entities.Person) *entities.Person {
+func findMarriageCandidate(sim *entities.Simulation, person *entities.Person) *entities.Person {
 	eligibleCandidates := []*entities.Person{}
-	for _, candidate := range entities.Sim.People.People {
+	for _, id := range sim.People.GetPersonIDs() {
+		candidate := sim.People.People[id]
 		if candidate.ID != person.ID &&
 			candidate.Relationship != entities.Married &&
-			candidate.Age() > entities.AgeOfAdulthood &&
+			candidate.Age(sim.Date) > entities.AgeOfAdulthood &&
 			candidate.FamilyName != person.FamilyName && // Sorry, George-Michael!
-			math.Abs(float64(person.Age()-candidate.Age())) < entities.MaxMarriageAgeDifference { // Age difference within a reasonable range
+			math.Abs(float64(person.Age(sim.Date)-candidate.Age(sim.Date))) < entities.MaxMarriageAgeDifference { // Age difference within a reasonable range
 			eligibleCandidates = append(eligibleCandidates, candidate)
 		}
 	}
 
 	if len(eligibleCandidates) > 0 {
-		return eligibleCandidates[rand.Intn(len(eligibleCandidates))]
+		return eligibleCandidates[sim.Rand().IntN(len(eligibleCandidates))]
 	}
 
 	return nil
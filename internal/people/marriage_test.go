@@ -0,0 +1,71 @@
+package people
+
+import (
+	"testing"
+	"time"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// wealth returns what the city's households have, less what they owe
+func wealth(sim *entities.Simulation) float64 {
+	total := 0.0
+	for _, household := range sim.People.Households {
+		total += float64(household.Savings)
+	}
+	for _, house := range sim.Houses {
+		if house.Owner == entities.OwnerHousehold {
+			total += float64(house.Price)
+		}
+	}
+	for _, loan := range sim.Bank.Loans {
+		total -= loan.Balance
+	}
+	return total
+}
+
+// TestMarryHomeowners checks that when two homeowners marry, the household that moves sells its house
+// and brings its savings and debts along, so that nothing is lost
+func TestMarryHomeowners(t *testing.T) {
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	birthdate := time.Date(1990, time.January, 1, 0, 0, 0, 0, time.UTC)
+	addHomeowner := func(savings int, memberIDs ...int) *entities.Household {
+		household := &entities.Household{ID: sim.GetNextID(), MemberIDs: memberIDs, Savings: savings, HouseID: sim.GetNextID()}
+		sim.People.Households[household.ID] = household
+		sim.Houses[household.HouseID] = &entities.House{ID: household.HouseID, HouseholdID: household.ID, Bedrooms: 3,
+			MonthlyRent: 1500, Owner: entities.OwnerHousehold, Price: 300000}
+		return household
+	}
+	addPerson := func(age, income, savings int) *entities.Person {
+		person := &entities.Person{ID: sim.GetNextID(), FirstName: "Nimal", FamilyName: "Perera", Birthdate: birthdate.AddDate(30-age, 0, 0),
+			Relationship: entities.Single, AnnualIncome: income, EmployerID: 1, Savings: savings}
+		sim.People.AddPerson(person)
+		return person
+	}
+
+	person1, person2, child := addPerson(30, 90000, 10000), addPerson(30, 90000, 20000), addPerson(5, 0, 0)
+	household1 := addHomeowner(10000, person1.ID)
+	household2 := addHomeowner(30000, person2.ID, child.ID)
+	if _, ok := sim.Bank.Lend(sim, household2, entities.Mortgage, 250000); !ok {
+		t.Fatal("expected the second household to get a mortgage")
+	}
+	if _, ok := sim.Bank.Lend(sim, household2, entities.PersonalLoan, 5000); !ok {
+		t.Fatal("expected the second household to get a personal loan")
+	}
+	household2.Savings -= 255000 // spent on the house and a car
+	before := wealth(sim)
+
+	Marry(sim, person1, person2)
+	if len(sim.People.Households) != 1 || household1.Size() != 3 {
+		t.Fatalf("expected the households to be combined, got %d households and %d members", len(sim.People.Households), household1.Size())
+	}
+	if house := sim.Houses[household2.HouseID]; house.Owner == entities.OwnerHousehold || house.HouseholdID != 0 {
+		t.Errorf("expected the second household's house to be sold, got it owned by the %s", house.Owner)
+	}
+	if loans := sim.Bank.HouseholdLoans(household1.ID); len(loans) != 1 || len(sim.Bank.Loans) != 1 {
+		t.Errorf("expected the personal loan to be brought along and the mortgage paid off, got %d loans", len(sim.Bank.Loans))
+	}
+	if after := wealth(sim); after != before {
+		t.Errorf("expected the households' wealth of %.0f to be kept, got %.0f", before, after)
+	}
+}
@@ -1,40 +1,50 @@
 package people
 
 import (
-	"fmt"
-	"math/rand/v2"
-
 	"github.com/janithl/citylyf/internal/entities"
 )
 
 // Immigrate simulates inwards migration
-func Immigrate() {
-	if entities.Sim.Houses.GetFreeHouses() == 0 || rand.Float64() < 0.95 { // 5% change of moving in if there are free houses
+func Immigrate(sim *entities.Simulation) {
+	if sim.Houses.GetFreeHouses() == 0 || sim.Rand().Float64() < 0.95 { // 5% change of moving in if there are free houses
 		return
 	}
 
-	household := CreateHousehold()
-	if houseID := household.FindHousing(); houseID > 0 {
-		entities.Sim.People.Households[household.ID] = household
-	} else {
-		RemoveHousehold(household)
+	MoveIn(sim)
+}
+
+// MoveIn creates a household and moves it into a free house it can afford, returning false if it
+// could not find one
+func MoveIn(sim *entities.Simulation) bool {
+	household := CreateHousehold(sim)
+	if houseID := household.FindHousing(sim); houseID > 0 {
+		sim.People.Households[household.ID] = household
+		return true
 	}
+	RemoveHousehold(sim, household)
+	return false
 }
 
 // Emigrate simulates outwards migration
-func Emigrate() {
-	for _, household := range entities.Sim.People.Households {
+func Emigrate(sim *entities.Simulation) {
+	for _, id := range sim.People.GetHouseholdIDs() {
+		household, exists := sim.People.Households[id]
+		if !exists {
+			continue
+		}
+
 		if household.Size() == 0 { // if a household is empty, remove it from the Sim and go to the next one
-			delete(entities.Sim.People.Households, household.ID)
+			sim.Houses.MoveOut(sim, household.HouseID)
+			delete(sim.People.Households, household.ID)
 			continue
 		}
 
-		if household.IsEligibleForMoveOut() {
-			movedName := household.FamilyName()
+		if household.IsEligibleForMoveOut(sim) {
+			movedName := household.FamilyName(sim.People)
 			houseID := household.HouseID
-			RemoveHousehold(household)
-			entities.Sim.Houses.MoveOut(houseID)
-			fmt.Printf("[ Move ] %s family has moved out of house #%d and the city, %d houses remain\n", movedName, houseID, entities.Sim.Houses.GetFreeHouses())
+			sim.Houses.MoveOut(sim, houseID) // households that own their house sell it before they leave
+			RemoveHousehold(sim, household)
+			sim.Events().Publish(entities.HouseholdMovedOut{HouseholdName: movedName, HouseID: houseID, FreeHouses: sim.Houses.GetFreeHouses()})
 		}
 	}
 }
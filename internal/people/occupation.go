@@ -1,18 +1,18 @@
 package people
 
 import (
-	"math/rand"
+	"math/rand/v2"
 
 	"github.com/janithl/citylyf/internal/entities"
 )
 
 // getEducationLevel returns education level based on age
-func getEducationLevel(age int) entities.EducationLevel {
+func getEducationLevel(rng *rand.Rand, age int) entities.EducationLevel {
 	if age <= entities.AgeOfAdulthood {
 		return entities.Unqualified
 	}
 
-	randomEducation := rand.Intn(100)
+	randomEducation := rng.IntN(100)
 	if age < 24 {
 		if randomEducation < 60 {
 			return entities.HighSchool
@@ -9,32 +9,33 @@ import (
 	"github.com/janithl/citylyf/internal/entities"
 )
 
-func CreateRandomPerson(minAge int, maxAge int) *entities.Person {
-	gender := entities.GetRandomGender()
-	name, familyName := entities.Sim.NameService.GetPersonName(gender)
+func CreateRandomPerson(sim *entities.Simulation, minAge int, maxAge int) *entities.Person {
+	rng := sim.Rand()
+	gender := entities.GetRandomGender(rng)
+	name, familyName := sim.NameService.GetPersonName(gender)
 
 	meanAge := entities.MeanAgeMale
 	if gender == entities.Female {
 		meanAge = entities.MeanAgeFemale
 	}
 
-	ageY, ageM := getAge(meanAge, entities.AgeStdDev, minAge, maxAge)
-	education := getEducationLevel(ageY)
+	ageY, ageM := getAge(rng, meanAge, entities.AgeStdDev, minAge, maxAge)
+	education := getEducationLevel(rng, ageY)
 	careerLevel := getCareerLevel(ageY, education)
 
-	var job economy.IndustryJob
+	var job entities.IndustryJob
 	var salary float64
 	if careerLevel != entities.Unemployed {
-		job, salary = economy.GetIndustryJob(education, careerLevel)
-		salary *= entities.Sim.Houses.GetCostOfLivingFactor() // adjust salary for cost of living factor
+		job, salary = economy.GetIndustryJob(rng, sim.Catalogue, education, careerLevel)
+		salary *= sim.Houses.GetCostOfLivingFactor() // adjust salary for cost of living factor
 	}
 
-	savings := salary * rand.Float64() * 0.5 * math.Max(float64(ageY-25), 1)
+	savings := salary * rng.Float64() * 0.5 * math.Max(float64(ageY-25), 1)
 
 	return &entities.Person{
 		FirstName:      name,
 		FamilyName:     familyName,
-		Birthdate:      getRandomBirthdate(ageY, ageM),
+		Birthdate:      getRandomBirthdate(sim, ageY, ageM),
 		Gender:         gender,
 		EducationLevel: education,
 		Occupation:     job.Job,
@@ -42,45 +43,45 @@ func CreateRandomPerson(minAge int, maxAge int) *entities.Person {
 		CareerLevel:    careerLevel,
 		AnnualIncome:   int(salary),
 		Savings:        int(savings),
-		Relationship:   entities.GetRelationshipStatus(ageY),
+		Relationship:   entities.GetRelationshipStatus(rng, ageY),
 	}
 }
 
-func CreateHousehold() *entities.Household {
+func CreateHousehold(sim *entities.Simulation) *entities.Household {
 	var p, q *entities.Person
-	householdID := entities.Sim.GetNextID()
+	householdID := sim.GetNextID()
 
 	household := &entities.Household{
 		ID:         householdID,
 		MemberIDs:  []int{},
-		MoveInDate: entities.Sim.Date,
-		LastPayDay: entities.Sim.Date,
+		MoveInDate: sim.Date,
+		LastPayDay: sim.Date,
 	}
 
-	p = CreateRandomPerson(16, 100)
-	p.ID = entities.Sim.GetNextID()
-	entities.Sim.People.AddPerson(p)
+	p = CreateRandomPerson(sim, 16, 100)
+	p.ID = sim.GetNextID()
+	sim.People.AddPerson(p)
 	household.MemberIDs = append(household.MemberIDs, p.ID)
 	household.Savings = p.Savings
 
 	if p.Relationship == entities.Married {
-		q = CreateRandomPerson(int(math.Max(entities.AgeOfAdulthood, float64(p.Age()-15))), p.Age()+15)
-		q.ID = entities.Sim.GetNextID()
-		entities.Sim.People.AddPerson(q)
+		q = CreateRandomPerson(sim, int(math.Max(entities.AgeOfAdulthood, float64(p.Age(sim.Date)-15))), p.Age(sim.Date)+15)
+		q.ID = sim.GetNextID()
+		sim.People.AddPerson(q)
 		q.Relationship = entities.Married
 		household.Savings += q.Savings
-		if rand.IntN(100) < 80 {
+		if sim.Rand().IntN(100) < 80 {
 			q.FamilyName = p.FamilyName
 		}
 
 		household.MemberIDs = append(household.MemberIDs, q.ID)
 	}
 
-	if rand.IntN(100) < 58 {
-		kids := createKids(p, q, getNumberOfKids())
+	if sim.Rand().IntN(100) < 58 {
+		kids := createKids(sim, p, q, getNumberOfKids(sim.Rand()))
 		for _, kid := range kids {
-			kid.ID = entities.Sim.GetNextID()
-			entities.Sim.People.AddPerson(kid)
+			kid.ID = sim.GetNextID()
+			sim.People.AddPerson(kid)
 			household.MemberIDs = append(household.MemberIDs, kid.ID)
 		}
 	}
@@ -89,19 +90,19 @@ func CreateHousehold() *entities.Household {
 }
 
 // RemoveHousehold removes a household and its members from the Sim, and removes them from their jobs
-func RemoveHousehold(household *entities.Household) {
+func RemoveHousehold(sim *entities.Simulation, household *entities.Household) {
 	for _, memberID := range household.MemberIDs {
-		member := entities.Sim.People.GetPerson(memberID)
+		member := sim.People.GetPerson(memberID)
 		if member != nil {
-			entities.Sim.Companies.RemoveEmployeeFromTheirCompany(member)
-			entities.Sim.People.RemovePerson(memberID)
+			sim.Companies.RemoveEmployeeFromTheirCompany(member)
+			sim.People.RemovePerson(memberID)
 		}
 	}
-	delete(entities.Sim.People.Households, household.ID)
+	delete(sim.People.Households, household.ID)
 }
 
-func getNumberOfKids() int {
-	randomKids := rand.IntN(100)
+func getNumberOfKids(rng *rand.Rand) int {
+	randomKids := rng.IntN(100)
 	switch {
 	case randomKids < 34:
 		return 0
@@ -118,7 +119,7 @@ func getNumberOfKids() int {
 	}
 }
 
-func createKids(p *entities.Person, q *entities.Person, numberOfKids int) []*entities.Person {
+func createKids(sim *entities.Simulation, p *entities.Person, q *entities.Person, numberOfKids int) []*entities.Person {
 	var kids []*entities.Person
 
 	if numberOfKids == 0 {
@@ -126,20 +127,20 @@ func createKids(p *entities.Person, q *entities.Person, numberOfKids int) []*ent
 	}
 
 	familyName := p.FamilyName
-	if q != nil && !strings.Contains(familyName, "-") && rand.Float32() < 0.1 { // 10% of surnames are double‑barrelled
+	if q != nil && !strings.Contains(familyName, "-") && sim.Rand().Float32() < 0.1 { // 10% of surnames are double‑barrelled
 		familyName += "-" + q.FamilyName
 	}
 
 	for len(kids) < numberOfKids {
-		parentMaxAge := p.Age()
+		parentMaxAge := p.Age(sim.Date)
 		kidMinAge := 0
-		if p.Relationship == entities.Married && q != nil && q.Age() > parentMaxAge {
-			parentMaxAge = q.Age()
+		if p.Relationship == entities.Married && q != nil && q.Age(sim.Date) > parentMaxAge {
+			parentMaxAge = q.Age(sim.Date)
 		} else if p.Relationship == entities.Widowed || p.Relationship == entities.Divorced {
 			kidMinAge = 1
 		}
 
-		if kid := CreateRandomPerson(kidMinAge, parentMaxAge-entities.AgeOfAdulthood); kid != nil {
+		if kid := CreateRandomPerson(sim, kidMinAge, parentMaxAge-entities.AgeOfAdulthood); kid != nil {
 			kid.Relationship = entities.Single
 			kid.FamilyName = familyName
 			kids = append(kids, kid)
@@ -0,0 +1,224 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// Companies are a number of companies of one size and industry that the city starts with
+type Companies struct {
+	Industry entities.Industry
+	Size     entities.CompanySize
+	Count    int
+}
+
+// Scenario describes the starting conditions of a new game
+type Scenario struct {
+	Name        string
+	Description string
+	CityName    string
+	Seed        uint64 // seed of the simulation if none is given, a random seed is used if zero
+	StartYear   int
+	Reserves    int
+	Taxes       *entities.SetTaxRatesCommand   // tax rates, the default brackets are kept if none are given
+	Expenses    map[entities.CostType]float64  // costs that differ from the defaults
+	Map         entities.MapSettings           // how the terrain is generated
+	Companies   []Companies                    // companies the city starts with, random ones if empty
+	Roads       []entities.PlaceRoadCommand    // roads built before the game starts
+	Zones       []entities.PlaceLandUseCommand // land zoned before the game starts
+	Population  int                            // number of people housed before the game starts
+	Goals       []entities.Goal                // goals that win the scenario when met, or lose it when failed
+	Catalogue   *entities.Catalogue            // industries and jobs added to the game's, like a catalogue file
+	Path        string                         `json:"-"` // file the scenario was loaded from
+}
+
+// Default returns the scenario of a regular new game
+func Default() *Scenario {
+	return &Scenario{
+		Name:      "Default",
+		StartYear: 2020,
+		Reserves:  1000000,
+		Map:       entities.DefaultMapSettings,
+	}
+}
+
+// Load reads a scenario from a JSON file, with the fields it leaves out taken from the default scenario
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := Default()
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("scenario %s: %w", path, err)
+	}
+	s.Path = path
+	if s.Name == "Default" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if s.CityName == "" {
+		s.CityName = s.Name
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("scenario %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Validate returns an error describing every problem with the scenario, or nil if it can be played
+func (s *Scenario) Validate() error {
+	errs := []error{}
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	rate := func(r float64) bool { return r >= 0 && r <= 100 }
+	probability := func(p float64) bool { return p >= 0 && p <= 1 }
+
+	check(s.StartYear >= 1900 && s.StartYear <= 2200, "start year %d is not between 1900 and 2200", s.StartYear)
+	check(s.Reserves >= 0, "reserves %d are negative", s.Reserves)
+	check(s.Population >= 0, "population %d is negative", s.Population)
+
+	if s.Taxes != nil {
+		check(rate(s.Taxes.CorporateTaxRate), "corporate tax rate %g is not between 0 and 100", s.Taxes.CorporateTaxRate)
+		check(rate(s.Taxes.SalesTaxRate), "sales tax rate %g is not between 0 and 100", s.Taxes.SalesTaxRate)
+		for i, bracket := range s.Taxes.IncomeTaxBrackets {
+			check(rate(bracket.Rate), "income tax bracket %d rate %g is not between 0 and 100", i+1, bracket.Rate)
+			check(i == 0 || bracket.Threshold < s.Taxes.IncomeTaxBrackets[i-1].Threshold, "income tax bracket %d threshold is not below the one before it", i+1)
+		}
+	}
+
+	for costType, cost := range s.Expenses {
+		_, known := entities.NewExpenses()[costType]
+		check(known, "unknown expense %q", costType)
+		check(cost >= 0, "expense %s %g is negative", costType, cost)
+	}
+
+	m := s.Map
+	check(m.RegionSize > 0 && m.Size >= m.RegionSize && m.Size%m.RegionSize == 0, "map size %d is not a multiple of region size %d", m.Size, m.RegionSize)
+	check(m.SeaLevel >= 0 && m.SeaLevel <= m.HillLevel && m.HillLevel <= m.MaxElevation, "map levels are not 0 <= sea level <= hill level <= max elevation")
+	check(probability(m.PeakProbability) && probability(m.RangeProbability) && probability(m.CliffProbability), "map probabilities are not between 0 and 1")
+
+	catalogue := entities.DefaultCatalogue()
+	if s.Catalogue != nil {
+		catalogue = catalogue.Override(s.Catalogue)
+		if err := catalogue.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, c := range s.Companies {
+		check(catalogue.HasIndustry(c.Industry), "unknown industry %q", c.Industry)
+		check(c.Size.IsValid(), "unknown company size %q", c.Size)
+		check(c.Count >= 0, "%s %s company count %d is negative", c.Size, c.Industry, c.Count)
+	}
+
+	onMap := func(p entities.Point) bool { return p.X >= 0 && p.Y >= 0 && p.X < m.Size && p.Y < m.Size }
+	for i, road := range s.Roads {
+		check(onMap(road.Start) && onMap(road.End), "road %d is off the map", i+1)
+		check(slices.Contains([]entities.RoadType{entities.Asphalt, entities.Chipseal, entities.Unsealed}, road.RoadType), "road %d has unknown type %q", i+1, road.RoadType)
+	}
+	for i, zone := range s.Zones {
+		check(onMap(zone.Start) && onMap(zone.End), "zone %d is off the map", i+1)
+		check(slices.Contains(entities.ZoningUses, zone.Use), "zone %d has unknown use %q", i+1, zone.Use)
+	}
+
+	for _, goal := range s.Goals {
+		if err := goal.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Fingerprint returns a short hash of the scenario's starting conditions, leaving out the name it's known by
+func (s *Scenario) Fingerprint() string {
+	start := *s
+	start.Name = ""
+	return entities.Fingerprint(start)
+}
+
+// NewSimulation creates the scenario's city with the given seed and catalogue, the default one if nil,
+// with its map, taxes, costs, roads, zones, goals and the scenario's industries and jobs
+func (s *Scenario) NewSimulation(seed uint64, catalogue *entities.Catalogue) *entities.Simulation {
+	sim := entities.NewSimulationWithMap(s.StartYear, s.Reserves, seed, s.Map)
+	sim.CityName = s.CityName
+	if catalogue != nil {
+		sim.Catalogue = catalogue
+	}
+	if s.Catalogue != nil {
+		sim.Catalogue = sim.Catalogue.Override(s.Catalogue)
+	}
+	sim.Journal().Scenario, sim.Journal().Catalogue = s.Fingerprint(), entities.Fingerprint(sim.Catalogue)
+	if s.Taxes != nil {
+		taxes := *s.Taxes
+		if len(taxes.IncomeTaxBrackets) == 0 {
+			taxes.IncomeTaxBrackets = sim.Government.IncomeTaxBrackets
+		}
+		taxes.Apply(sim)
+	}
+	for costType, cost := range s.Expenses {
+		sim.Government.Expenses[costType] = cost
+	}
+	for _, road := range s.Roads { // the scenario's roads and zones are part of the starting city, not player actions
+		road.Apply(sim)
+	}
+	for _, zone := range s.Zones {
+		zone.Apply(sim)
+	}
+	sim.Government.CapEx = 0
+	for _, goal := range s.Goals {
+		sim.Goals = append(sim.Goals, &entities.Goal{
+			Name:       goal.Name,
+			Statistic:  goal.Statistic,
+			Comparison: goal.Comparison,
+			Value:      goal.Value,
+			Months:     goal.Months,
+			Always:     goal.Always,
+			By:         goal.By,
+			Status:     entities.GoalInProgress,
+		})
+	}
+	return sim
+}
+
+// GetScenariosDir returns the directory scenario files are read from
+func GetScenariosDir() string {
+	scenariosdir := ""
+	if homedir, err := os.UserHomeDir(); err == nil {
+		scenariosdir = homedir + "/.citylyf/scenarios"
+		if err := os.MkdirAll(scenariosdir, os.ModePerm); err != nil {
+			log.Println(err)
+			scenariosdir = ""
+		}
+	} else {
+		log.Println(err)
+	}
+
+	return scenariosdir
+}
+
+// List loads the scenarios in a directory, sorted by name, logging and skipping any that are invalid
+func List(dir string) []*Scenario {
+	scenarios := []*Scenario{}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, path := range paths {
+		if s, err := Load(path); err == nil {
+			scenarios = append(scenarios, s)
+		} else {
+			log.Println(err)
+		}
+	}
+	slices.SortFunc(scenarios, func(a, b *Scenario) int { return strings.Compare(a.Name, b.Name) })
+	return scenarios
+}
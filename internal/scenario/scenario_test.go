@@ -0,0 +1,110 @@
+package scenario_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/scenario"
+)
+
+const coastalTown = `{
+	"Name": "Coastal Town",
+	"CityName": "Seaview",
+	"Seed": 7,
+	"StartYear": 1990,
+	"Reserves": 250000,
+	"Taxes": {"CorporateTaxRate": 20, "SalesTaxRate": 5},
+	"Expenses": {"AsphaltRoadConstruction": 20000},
+	"Map": {"Size": 32, "RegionSize": 8, "SeaLevel": 4},
+	"Companies": [{"Industry": "Retail", "Size": "Micro", "Count": 2}, {"Industry": "Mining", "Size": "SME", "Count": 1}],
+	"Roads": [{"Start": {"X": 4, "Y": 10}, "End": {"X": 20, "Y": 10}, "RoadType": "asphalt"}],
+	"Zones": [{"Start": {"X": 4, "Y": 8}, "End": {"X": 20, "Y": 12}, "Use": "residential"}],
+	"Population": 40,
+	"Goals": [{"Name": "Grow", "Statistic": "Population", "Comparison": ">=", "Value": 5000, "By": "2035-01-01"}],
+	"Catalogue": {"Industries": ["Mining"], "Zones": {"Mining": "industrial"}, "Jobs": [{"Industry": "Mining", "Job": "Miner",
+		"EducationLevels": ["Unqualified"], "JobAbundance": 3,
+		"SalaryRange": {"Entry Level": [50000, 60000], "Mid Level": [60000, 70000], "Senior Level": [70000, 80000], "Executive Level": [80000, 90000]}}]}
+}`
+
+func writeScenario(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	s, err := scenario.Load(writeScenario(t, t.TempDir(), "coastal.json", coastalTown))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Name != "Coastal Town" || s.StartYear != 1990 || s.Population != 40 || len(s.Roads) != 1 {
+		t.Errorf("scenario was not loaded: %+v", s)
+	}
+	// fields the scenario leaves out keep their defaults
+	if s.Map.MaxElevation != entities.DefaultMapSettings.MaxElevation || s.Map.HillLevel != entities.DefaultMapSettings.HillLevel {
+		t.Errorf("map settings %+v lost their defaults", s.Map)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := map[string]string{
+		"start year":   `{"StartYear": 1200}`,
+		"tax rate":     `{"Taxes": {"CorporateTaxRate": 120}}`,
+		"expense":      `{"Expenses": {"Monorail": 100}}`,
+		"region size":  `{"Map": {"Size": 60}}`,
+		"industry":     `{"Companies": [{"Industry": "Piracy", "Size": "Micro", "Count": 1}]}`,
+		"company size": `{"Companies": [{"Industry": "Retail", "Size": "Huge", "Count": 1}]}`,
+		"road":         `{"Roads": [{"Start": {"X": 0, "Y": 0}, "End": {"X": 70, "Y": 0}, "RoadType": "asphalt"}]}`,
+		"zone":         `{"Zones": [{"Start": {"X": 0, "Y": 0}, "End": {"X": 1, "Y": 1}, "Use": "transport"}]}`,
+		"population":   `{"Population": -1}`,
+		"goal":         `{"Goals": [{"Name": "Happy", "Statistic": "Happiness", "Comparison": ">", "Value": 1}]}`,
+	}
+	dir := t.TempDir()
+	for name, contents := range invalid {
+		if _, err := scenario.Load(writeScenario(t, dir, strings.ReplaceAll(name, " ", "-")+".json", contents)); err == nil {
+			t.Errorf("scenario with an invalid %s was loaded", name)
+		}
+	}
+
+	if err := scenario.Default().Validate(); err != nil {
+		t.Errorf("default scenario is invalid: %v", err)
+	}
+	if len(scenario.List(dir)) != 0 {
+		t.Errorf("invalid scenarios were listed")
+	}
+}
+
+func TestNewSimulation(t *testing.T) {
+	s, err := scenario.Load(writeScenario(t, t.TempDir(), "coastal.json", coastalTown))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim := s.NewSimulation(1, nil)
+
+	switch {
+	case sim.CityName != "Seaview":
+		t.Errorf("city name is %q", sim.CityName)
+	case sim.Date.Year() != 1990:
+		t.Errorf("start year is %d", sim.Date.Year())
+	case sim.Geography.Size != 32 || sim.Geography.SeaLevel != 4:
+		t.Errorf("map size %d and sea level %d do not match the scenario", sim.Geography.Size, sim.Geography.SeaLevel)
+	case sim.Government.CorporateTaxRate != 20 || len(sim.Government.IncomeTaxBrackets) == 0:
+		t.Errorf("taxes %+v do not match the scenario", sim.Government)
+	case sim.Government.Expenses[entities.AsphaltRoadConstruction] != 20000:
+		t.Errorf("road construction costs %g", sim.Government.Expenses[entities.AsphaltRoadConstruction])
+	case len(sim.Geography.GetRoads()) != 1:
+		t.Errorf("%d roads were built", len(sim.Geography.GetRoads()))
+	case !sim.Catalogue.HasIndustry("Mining") || sim.Catalogue.LandUse("Mining") != entities.IndustrialUse:
+		t.Errorf("industries %v do not include the scenario's", sim.Catalogue.Industries)
+	case len(sim.Goals) != 1 || sim.Goals[0].Status != entities.GoalInProgress || &sim.Goals[0].Name == &s.Goals[0].Name:
+		t.Errorf("goals %v were not copied from the scenario", sim.Goals)
+	case sim.Government.GetReservesAtHand() != 250000:
+		t.Errorf("reserves are %g, the scenario's roads should be free", sim.Government.GetReservesAtHand())
+	}
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+// System is a part of the simulation that is updated on a regular cadence
+type System interface {
+	Name() string
+	Update(sim *entities.Simulation)
+}
+
+// Cadence is how often a system is updated
+type Cadence int
+
+const (
+	Daily     Cadence = iota
+	Weekly            // every Monday
+	Monthly           // on the first of every month
+	Quarterly         // on the first of January, April, July and October
+	Annually          // on the first of January
+)
+
+func (c Cadence) String() string {
+	return [...]string{"Daily", "Weekly", "Monthly", "Quarterly", "Annually"}[c]
+}
+
+// ParseCadence returns the cadence with the given name, such as "monthly"
+func ParseCadence(name string) (Cadence, error) {
+	for c := Daily; c <= Annually; c++ {
+		if strings.EqualFold(name, c.String()) {
+			return c, nil
+		}
+	}
+	return Daily, fmt.Errorf("unknown cadence %q", name)
+}
+
+// IsDue returns true if a system with this cadence should be updated on the given date
+func (c Cadence) IsDue(date time.Time) bool {
+	switch c {
+	case Weekly:
+		return date.Weekday() == time.Monday
+	case Monthly:
+		return date.Day() == 1
+	case Quarterly:
+		return date.Day() == 1 && (date.Month()-1)%3 == 0
+	case Annually:
+		return date.Day() == 1 && date.Month() == time.January
+	default:
+		return true
+	}
+}
+
+// Func turns a function into a System
+type Func struct {
+	name   string
+	update func(sim *entities.Simulation)
+}
+
+// NewFunc returns a System with the given name that calls update
+func NewFunc(name string, update func(sim *entities.Simulation)) *Func {
+	return &Func{name: name, update: update}
+}
+
+func (f *Func) Name() string {
+	return f.name
+}
+
+func (f *Func) Update(sim *entities.Simulation) {
+	f.update(sim)
+}
+
+type registration struct {
+	system  System
+	cadence Cadence
+	order   int
+	enabled bool
+}
+
+// Scheduler updates its registered systems in order, each on its own cadence
+type Scheduler struct {
+	registrations []*registration
+}
+
+// New returns a scheduler with no systems registered
+func New() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a system to the scheduler. Systems with a lower order are updated first,
+// and systems with the same order are updated in the order they were registered.
+func (s *Scheduler) Register(system System, cadence Cadence, order int) {
+	s.registrations = append(s.registrations, &registration{system: system, cadence: cadence, order: order, enabled: true})
+	slices.SortStableFunc(s.registrations, func(a, b *registration) int {
+		return a.order - b.order
+	})
+}
+
+// SetEnabled enables or disables the named system, returning false if there is no such system
+func (s *Scheduler) SetEnabled(name string, enabled bool) bool {
+	found := false
+	for _, r := range s.registrations {
+		if r.system.Name() == name {
+			r.enabled = enabled
+			found = true
+		}
+	}
+	return found
+}
+
+// Enabled returns true if the named system is registered and enabled
+func (s *Scheduler) Enabled(name string) bool {
+	for _, r := range s.registrations {
+		if r.system.Name() == name {
+			return r.enabled
+		}
+	}
+	return false
+}
+
+// Systems returns the names of the registered systems, in the order they are updated
+func (s *Scheduler) Systems() []string {
+	names := make([]string, len(s.registrations))
+	for i, r := range s.registrations {
+		names[i] = r.system.Name()
+	}
+	return names
+}
+
+// Update updates every enabled system that is due on the current simulation date
+func (s *Scheduler) Update(sim *entities.Simulation) {
+	for _, r := range s.registrations {
+		if r.enabled && r.cadence.IsDue(sim.Date) {
+			r.system.Update(sim)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/janithl/citylyf/internal/entities"
+)
+
+func TestCadenceIsDue(t *testing.T) {
+	tests := []struct {
+		cadence Cadence
+		days    int // number of days in 2021 the cadence is due on
+	}{
+		{Daily, 365},
+		{Weekly, 52},
+		{Monthly, 12},
+		{Quarterly, 4},
+		{Annually, 1},
+	}
+
+	for _, test := range tests {
+		due := 0
+		for date := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC); date.Year() == 2021; date = date.AddDate(0, 0, 1) {
+			if test.cadence.IsDue(date) {
+				due++
+			}
+		}
+		if due != test.days {
+			t.Errorf("%s: expected to be due on %d days, got %d", test.cadence, test.days, due)
+		}
+	}
+}
+
+func TestParseCadence(t *testing.T) {
+	if cadence, err := ParseCadence("monthly"); err != nil || cadence != Monthly {
+		t.Errorf("expected monthly to parse as Monthly, got %s, %v", cadence, err)
+	}
+	if _, err := ParseCadence("fortnightly"); err == nil {
+		t.Error("expected an error for an unknown cadence")
+	}
+}
+
+func TestSchedulerUpdate(t *testing.T) {
+	sim := entities.NewSimulation(2020, 1e6, 1)
+	sim.Date = time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC) // a Thursday
+
+	updated := []string{}
+	record := func(name string) System {
+		return NewFunc(name, func(*entities.Simulation) { updated = append(updated, name) })
+	}
+
+	s := New()
+	s.Register(record("economy"), Monthly, 70)
+	s.Register(record("housing"), Daily, 10)
+	s.Register(record("weekly"), Weekly, 5)
+	s.Register(record("rates"), Quarterly, 60)
+	s.Register(record("lifecycle"), Daily, 50)
+	s.Register(record("taxes"), Annually, 80)
+
+	if !s.SetEnabled("lifecycle", false) {
+		t.Error("expected lifecycle system to be found")
+	}
+	if s.SetEnabled("missing", false) {
+		t.Error("expected missing system not to be found")
+	}
+	if s.Enabled("lifecycle") || !s.Enabled("taxes") || s.Enabled("missing") {
+		t.Error("expected only registered, enabled systems to be enabled")
+	}
+
+	s.Update(sim)
+	if expected := []string{"housing", "rates", "economy"}; !slices.Equal(updated, expected) {
+		t.Errorf("expected %v to be updated, got %v", expected, updated)
+	}
+}
@@ -2,69 +2,281 @@ package internal
 
 import (
 	"fmt"
-	"math/rand"
+	"io"
+	"log"
+	"os"
+	"slices"
 	"time"
 
 	"github.com/janithl/citylyf/internal/economy"
 	"github.com/janithl/citylyf/internal/entities"
 	"github.com/janithl/citylyf/internal/gamefile"
+	"github.com/janithl/citylyf/internal/mayor"
 	"github.com/janithl/citylyf/internal/people"
+	"github.com/janithl/citylyf/internal/scenario"
+	"github.com/janithl/citylyf/internal/scheduler"
 )
 
 type SimRunner struct {
-	employment         *economy.Employment
-	calculationService *economy.CalculationService
-	ticker             *time.Ticker
-	done               chan bool
+	Seed       uint64              // seed for new games, a random seed is used if zero
+	EventLog   io.Writer           // where simulation events are logged, os.Stdout if nil
+	Autosave   *gamefile.Autosave  // autosaves the city if not nil
+	AutosaveOn scheduler.Cadence   // how often the city is autosaved
+	Debug      bool                // check and repair references between entities every day
+	Scenario   *scenario.Scenario  // starting conditions of new games, the default ones if nil
+	Catalogue  *entities.Catalogue // industries and jobs of new games, the default ones if nil
+	Mayor      mayor.Mayor         // plays the city every month if not nil, see SetAutopilot
+	sim        *entities.Simulation
+	employment *economy.Employment
+	scheduler  *scheduler.Scheduler
+	replay     []entities.JournalEntry // journalled commands still to be replayed
+	loadReport *entities.IntegrityReport
+	ticker     *time.Ticker
+	done       chan bool
 }
 
-func (sr *SimRunner) NewGame(gamePath *string) {
-	if gamePath != nil && gamefile.CheckExists(*gamePath) { // load sim from savegame file
-		gamefile.Load(*gamePath)
+// NewGame starts a new game, or loads one from the save file at gamePath if it is not nil
+func (sr *SimRunner) NewGame(gamePath *string) error {
+	sr.sim = nil
+	if gamePath != nil { // load sim from savegame file
+		sim, report, err := gamefile.LoadWithReport(*gamePath)
+		if err != nil {
+			return err
+		}
+		sr.sim, sr.loadReport = sim, report
 	} else { // create a new simulation
-		entities.StartNewSim()
+		start := sr.Scenario
+		if start == nil {
+			start = scenario.Default()
+		}
+		seed := sr.Seed
+		if seed == 0 {
+			seed = start.Seed
+		}
+		if seed == 0 {
+			seed = entities.NewSeed()
+		}
+		sr.sim = start.NewSimulation(seed, sr.Catalogue)
+		sr.sim.SendStats()
 	}
 
-	sr.employment = &economy.Employment{CompanyService: &economy.CompanyService{}}
-	sr.calculationService = economy.NewCalculationService(sr.employment.CompanyService)
-
-	if len(entities.Sim.Companies) == 0 {
-		// set up some initial entities.Sim.Companies
-		for i := 0; i < 8+rand.Intn(8); i++ {
-			entities.Sim.Mutex.Lock()
-			newCompany := sr.employment.CompanyService.GenerateRandomCompany(entities.GetRandomCompanySize(), entities.GetRandomIndustry())
-			entities.Sim.Companies.Add(newCompany)
-			entities.Sim.Mutex.Unlock()
-			fmt.Printf("[ Econ ] %s (%s) founded!\n", newCompany.Name, newCompany.Industry)
-		}
+	eventLog := sr.EventLog
+	if eventLog == nil {
+		eventLog = os.Stdout
 	}
+	sr.sim.Events().Subscribe(func(event entities.Event) {
+		fmt.Fprintln(eventLog, event)
+	})
+
+	sr.employment = &economy.Employment{CompanyService: &economy.CompanyService{}}
+	sr.scheduler = scheduler.New()
+	sr.registerSystems()
+
+	sr.sim.Mutex.Lock()
+	sr.setUpCity(gamePath == nil)
+	sr.sim.Mutex.Unlock()
 
 	sr.ticker = time.NewTicker(100 * time.Millisecond) // tick every 1/10th of a second
 	sr.done = make(chan bool)                          // channel to send kill signal to goroutine
+	return nil
+}
+
+// setUpCity founds the initial companies if the city has none, moving them into any zones for them,
+// and houses the scenario's starting population if it is a new game
+func (sr *SimRunner) setUpCity(newGame bool) {
+	found := func(size entities.CompanySize, industry entities.Industry) {
+		newCompany := sr.employment.CompanyService.GenerateRandomCompany(sr.sim, size, industry)
+		sr.sim.Companies.Add(sr.sim, newCompany)
+		sr.sim.Events().Publish(entities.CompanyFounded{Name: newCompany.Name, Industry: newCompany.Industry})
+	}
+
+	switch {
+	case len(sr.sim.Companies) > 0:
+	case sr.Scenario == nil || len(sr.Scenario.Companies) == 0:
+		// set up some initial companies
+		for i := 0; i < 8+sr.sim.Rand().IntN(8); i++ {
+			found(entities.GetRandomCompanySize(sr.sim.Rand()), sr.sim.Catalogue.RandomIndustry(sr.sim.Rand()))
+		}
+	default:
+		for _, companies := range sr.Scenario.Companies {
+			for range companies.Count {
+				found(companies.Size, companies.Industry)
+			}
+		}
+	}
+	sr.sim.Companies.Settle(sr.sim)
+
+	if !newGame || sr.Scenario == nil {
+		return
+	}
+	// houses are built as they are needed, giving up if households can't be housed for a while
+	for failures := 0; sr.sim.People.Population() < sr.Scenario.Population && failures < 100; {
+		if sr.sim.Houses.GetFreeHouses() == 0 {
+			sr.sim.Houses.PlaceHousing(sr.sim)
+		}
+		if !people.MoveIn(sr.sim) {
+			failures++
+		}
+	}
+	sr.employment.AssignJobs(sr.sim)
+}
+
+// Sim returns the simulation being run
+func (sr *SimRunner) Sim() *entities.Simulation {
+	return sr.sim
+}
+
+// LoadReport returns the references between entities that were repaired when the game was loaded,
+// or nil for a new game
+func (sr *SimRunner) LoadReport() *entities.IntegrityReport {
+	return sr.loadReport
+}
+
+// Scheduler returns the scheduler that updates the simulation systems every game tick
+func (sr *SimRunner) Scheduler() *scheduler.Scheduler {
+	return sr.scheduler
+}
+
+// registerSystems registers the built-in simulation systems with the scheduler
+func (sr *SimRunner) registerSystems() {
+	calculationService := economy.NewCalculationService(sr.employment.CompanyService)
+
+	sr.scheduler.Register(scheduler.NewFunc("housing", func(sim *entities.Simulation) { sim.Houses.PlaceHousing(sim) }), scheduler.Daily, 10)
+	sr.scheduler.Register(scheduler.NewFunc("immigration", people.Immigrate), scheduler.Daily, 20)
+	sr.scheduler.Register(scheduler.NewFunc("employment", sr.employment.AssignJobs), scheduler.Daily, 30)
+	sr.scheduler.Register(scheduler.NewFunc("emigration", people.Emigrate), scheduler.Daily, 40)
+	sr.scheduler.Register(scheduler.NewFunc("lifecycle", people.SimulateLifecycle), scheduler.Daily, 50)
+	sr.scheduler.Register(scheduler.NewFunc("interest-rate", func(sim *entities.Simulation) { sim.Market.ReviseInterestRate(sim) }), scheduler.Quarterly, 60)
+	sr.scheduler.Register(scheduler.NewFunc("economy", calculationService.CalculateEconomy), scheduler.Monthly, 70)
+	sr.scheduler.Register(scheduler.NewFunc("taxes", func(sim *entities.Simulation) { sim.Government.CollectTaxes(sim) }), scheduler.Annually, 80)
+	sr.scheduler.Register(scheduler.NewFunc("statistics", func(sim *entities.Simulation) { sim.Statistics.Record(sim) }), scheduler.Monthly, 90)
+	sr.scheduler.Register(scheduler.NewFunc("goals", func(sim *entities.Simulation) { sim.EvaluateGoals() }), scheduler.Monthly, 95)
+	sr.scheduler.Register(scheduler.NewFunc("snapshots", func(sim *entities.Simulation) { sim.Snapshots().Add(sim.Snapshot()) }), scheduler.Monthly, 100)
+	if sr.Autosave != nil {
+		sr.scheduler.Register(sr.Autosave, sr.AutosaveOn, 110)
+	}
+	if sr.Mayor != nil {
+		// the mayor decides last, so that its journalled commands replay at the same point of the day
+		sr.scheduler.Register(&mayor.System{Mayor: sr.Mayor}, scheduler.Monthly, 120)
+	}
+	if sr.Debug {
+		sr.scheduler.Register(scheduler.NewFunc("integrity", func(sim *entities.Simulation) {
+			if report := sim.CheckIntegrity(true); !report.OK() {
+				log.Printf("%s: %s", sim.Date.Format("2006-01-02"), report)
+			}
+		}), scheduler.Daily, 1000)
+	}
+}
+
+// SetAutopilot turns the mayor playing the city on or off. It returns false if there is no mayor.
+func (sr *SimRunner) SetAutopilot(on bool) bool {
+	sr.sim.Mutex.Lock()
+	defer sr.sim.Mutex.Unlock()
+	return sr.scheduler.SetEnabled("mayor", on)
+}
+
+// Autopilot returns true if the mayor is playing the city
+func (sr *SimRunner) Autopilot() bool {
+	sr.sim.Mutex.RLock()
+	defer sr.sim.Mutex.RUnlock()
+	return sr.scheduler.Enabled("mayor")
 }
 
 func (sr *SimRunner) GameTick() {
-	entities.Sim.Houses.PlaceHousing()
-	people.Immigrate()
-	sr.employment.AssignJobs()
-	people.Emigrate()
-	people.SimulateLifecycle()
-	entities.Sim.Market.ReviseInterestRate()
-	sr.calculationService.CalculateEconomy()
+	sr.scheduler.Update(sr.sim)
+	sr.applyReplay()
+}
+
+// Replay gives the simulation the journal's commands on the dates they were originally given.
+// Replaying on a new game with the journal's seed reproduces the journalled session exactly.
+func (sr *SimRunner) Replay(journal *entities.Journal) {
+	sr.sim.Mutex.Lock()
+	defer sr.sim.Mutex.Unlock()
+
+	sr.replay = slices.Clone(journal.Entries)
+	sr.applyReplay()
+}
+
+// applyReplay gives the simulation the replayed commands that are due by the current date
+func (sr *SimRunner) applyReplay() {
+	for len(sr.replay) > 0 && !sr.replay[0].Date.After(sr.sim.Date) {
+		sr.sim.Execute(sr.replay[0].Command)
+		sr.replay = sr.replay[1:]
+	}
+}
+
+// Step advances the simulation by a single day
+func (sr *SimRunner) Step() {
+	sr.sim.Mutex.Lock()
+	sr.sim.NextDay(sr.GameTick)
+	sr.sim.SendStats()
+	sr.sim.Mutex.Unlock()
+}
+
+// Advance runs the simulation for a number of days as fast as possible
+func (sr *SimRunner) Advance(days int) {
+	for range days {
+		sr.Step()
+	}
+}
+
+// RunUntil runs the simulation as fast as possible until the given date
+func (sr *SimRunner) RunUntil(date time.Time) {
+	sr.RunUntilFunc(func(*entities.Simulation) bool { return false }, date)
+}
+
+// RunUntilFunc runs the simulation as fast as possible until predicate returns true, giving up
+// at the limit date. It returns true if the predicate was met.
+func (sr *SimRunner) RunUntilFunc(predicate func(sim *entities.Simulation) bool, limit time.Time) bool {
+	for {
+		sr.sim.Mutex.RLock()
+		met, expired := predicate(sr.sim), !sr.sim.Date.Before(limit)
+		sr.sim.Mutex.RUnlock()
+		if met || expired {
+			return met
+		}
+		sr.Step()
+	}
+}
+
+// runFlatOut runs as many days as fit in one ticker interval, unlocking between days so the UI stays responsive
+func (sr *SimRunner) runFlatOut() {
+	deadline := time.Now().Add(90 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		sr.sim.Mutex.Lock()
+		if !sr.sim.IsRunningFlatOut() {
+			sr.sim.Mutex.Unlock()
+			return
+		}
+		sr.sim.NextDay(sr.GameTick)
+		sr.sim.SendStats()
+		sr.sim.Mutex.Unlock()
+	}
 }
 
 func (sr *SimRunner) RunGameLoop() {
+	lastTick := time.Now()
 	for {
 		select {
 		case <-sr.done:
 			return
-		case <-sr.ticker.C:
-			entities.Sim.Mutex.Lock()
-			if entities.Sim.SimulationSpeed != entities.Pause {
-				entities.Sim.Tick(sr.GameTick)
-				entities.Sim.SendStats()
+		case now := <-sr.ticker.C:
+			sr.sim.Mutex.Lock()
+			sr.sim.AddPlayTime(now.Sub(lastTick))
+			flatOut := sr.sim.IsRunningFlatOut()
+			sr.sim.Mutex.Unlock()
+			lastTick = now
+			if flatOut {
+				sr.runFlatOut()
+				continue
+			}
+
+			sr.sim.Mutex.Lock()
+			if sr.sim.SimulationSpeed != entities.Pause {
+				sr.sim.Tick(sr.GameTick)
+				sr.sim.SendStats()
 			}
-			entities.Sim.Mutex.Unlock()
+			sr.sim.Mutex.Unlock()
 		}
 	}
 }
@@ -76,4 +288,7 @@ func (sr *SimRunner) EndGame() {
 	if sr.done != nil {
 		sr.done <- true
 	}
+	if sr.Autosave != nil {
+		sr.Autosave.Wait() // let the last autosave finish writing
+	}
 }
@@ -1,39 +1,342 @@
 package internal_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand/v2"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/janithl/citylyf/internal"
 	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/mayor"
+	"github.com/janithl/citylyf/internal/scenario"
 )
 
+// runSeededSim runs a new simulation with the given seed for a number of days
+// and returns the resulting city as JSON
+func runSeededSim(t *testing.T, seed uint64, days int) []byte {
+	simRunner := &internal.SimRunner{Seed: seed}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	sim.SimulationSpeed = entities.Fast
+
+	// add the same roads and zones every run
+	for i := 0; i < 8; i++ {
+		x, y := 8+i*6, 8+i*6
+		entities.PlaceRoad(sim, entities.Point{X: x - 4, Y: y}, entities.Point{X: x + 4, Y: y}, entities.Asphalt)
+		use := entities.ResidentialUse
+		if i >= 6 {
+			use = entities.RetailUse
+		}
+		sim.Geography.PlaceLandUse(entities.Point{X: x - 4, Y: y - 2}, entities.Point{X: x + 4, Y: y + 2}, use)
+	}
+
+	for range days {
+		sim.Tick(simRunner.GameTick)
+	}
+
+	return cityJSON(t, sim)
+}
+
+// cityJSON returns the city as JSON, for comparing cities
+func cityJSON(t *testing.T, sim *entities.Simulation) []byte {
+	city, err := json.Marshal(struct {
+		Sim   *entities.Simulation
+		Tiles [][]entities.Tile
+		Roads []*entities.Road
+	}{sim, sim.Geography.GetTiles(), sim.Geography.GetRoads()})
+	if err != nil {
+		t.Error(err)
+	}
+	return city
+}
+
+// TestSimRunnerDeterminism checks that two runs with the same seed produce the same city,
+// running the simulations side by side
+func TestSimRunnerDeterminism(t *testing.T) {
+	t.Parallel()
+	seeds := []uint64{42, 42, 43}
+	cities := make([][]byte, len(seeds))
+
+	var wg sync.WaitGroup
+	for i, seed := range seeds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cities[i] = runSeededSim(t, seed, 400)
+		}()
+	}
+	wg.Wait()
+
+	if !bytes.Equal(cities[0], cities[1]) {
+		t.Errorf("two runs with seed 42 produced different cities")
+	}
+	if bytes.Equal(cities[0], cities[2]) {
+		t.Errorf("runs with seeds 42 and 43 produced the same city")
+	}
+}
+
+// TestSimRunnerDisableSystem checks that a disabled system is no longer updated
+func TestSimRunnerDisableSystem(t *testing.T) {
+	t.Parallel()
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	if !simRunner.Scheduler().SetEnabled("immigration", false) {
+		t.Fatal("expected an immigration system to be registered")
+	}
+
+	for i := 0; i < 4; i++ {
+		x, y := 8+i*6, 8+i*6
+		entities.PlaceRoad(sim, entities.Point{X: x - 4, Y: y}, entities.Point{X: x + 4, Y: y}, entities.Asphalt)
+		sim.Geography.PlaceLandUse(entities.Point{X: x - 4, Y: y - 2}, entities.Point{X: x + 4, Y: y + 2}, entities.ResidentialUse)
+	}
+	simRunner.Advance(100)
+
+	if population := sim.People.Population(); population != 0 {
+		t.Errorf("expected nobody to move in with immigration disabled, got a population of %d", population)
+	}
+}
+
+func TestSimRunnerRunUntil(t *testing.T) {
+	t.Parallel()
+	simRunner := &internal.SimRunner{Seed: 7, EventLog: io.Discard}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	simRunner.Advance(10)
+	if !sim.Date.Equal(date(2020, time.January, 11)) {
+		t.Errorf("expected Advance(10) to reach 2020-01-11, got %s", sim.Date.Format("2006-01-02"))
+	}
+
+	inMarch := func(sim *entities.Simulation) bool { return sim.Date.Month() == time.March }
+	if !simRunner.RunUntilFunc(inMarch, date(2021, time.January, 1)) || !sim.Date.Equal(date(2020, time.March, 1)) {
+		t.Errorf("expected to stop on 2020-03-01, got %s", sim.Date.Format("2006-01-02"))
+	}
+
+	sim.SkipAhead(date(2020, time.May, 1))
+	simRunner.RunUntil(date(2020, time.April, 1))
+	if progress, _ := sim.SkipAheadProgress(); !sim.IsSkippingAhead() || progress < 0.5 || progress > 0.55 {
+		t.Errorf("expected to be about halfway through skipping ahead, got %.2f", progress)
+	}
+	simRunner.RunUntil(date(2020, time.May, 1))
+	if sim.IsSkippingAhead() {
+		t.Error("expected skipping ahead to be finished")
+	}
+
+	if simRunner.RunUntilFunc(func(*entities.Simulation) bool { return false }, date(2021, time.January, 1)) {
+		t.Error("expected a predicate that is never met to return false")
+	}
+	if !sim.Date.Equal(date(2021, time.January, 1)) {
+		t.Errorf("expected to stop at the limit, got %s", sim.Date.Format("2006-01-02"))
+	}
+}
+
+// TestSnapshotRestore checks that a restored simulation carries on exactly as it did before
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	for i := 0; i < 4; i++ {
+		x, y := 8+i*6, 8+i*6
+		entities.PlaceRoad(sim, entities.Point{X: x - 4, Y: y}, entities.Point{X: x + 4, Y: y}, entities.Asphalt)
+		sim.Geography.PlaceLandUse(entities.Point{X: x - 4, Y: y - 2}, entities.Point{X: x + 4, Y: y + 2}, entities.ResidentialUse)
+	}
+
+	simRunner.Advance(200)
+	snapshot := sim.Snapshot()
+	before := cityJSON(t, sim)
+	simRunner.Advance(100)
+	after := cityJSON(t, sim)
+
+	sim.Restore(snapshot)
+	if !bytes.Equal(before, cityJSON(t, sim)) {
+		t.Fatal("restored city is different to the snapshot")
+	}
+	simRunner.Advance(100)
+	if !bytes.Equal(after, cityJSON(t, sim)) {
+		t.Error("restored city carried on differently")
+	}
+
+	if snapshots := sim.Snapshots().List(); len(snapshots) != entities.MaxSnapshots ||
+		!snapshots[0].Date.After(snapshots[len(snapshots)-1].Date) {
+		t.Errorf("expected %d monthly snapshots, newest first", entities.MaxSnapshots)
+	}
+}
+
+// TestJournalReplay checks that replaying a session's journal on a new game with the same seed
+// reproduces the session, including after the journal has been through JSON
+func TestJournalReplay(t *testing.T) {
+	t.Parallel()
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	sim.Execute(entities.NameCityCommand{Name: "Replayville"})
+	for i := 0; i < 4; i++ {
+		x, y := 8+i*6, 8+i*6
+		sim.Execute(entities.PlaceRoadCommand{Start: entities.Point{X: x - 4, Y: y}, End: entities.Point{X: x + 4, Y: y}, RoadType: entities.Asphalt})
+		sim.Execute(entities.PlaceLandUseCommand{Start: entities.Point{X: x - 4, Y: y - 2}, End: entities.Point{X: x + 4, Y: y + 2}, Use: entities.ResidentialUse})
+		simRunner.Advance(30 + i*17)
+	}
+	sim.Execute(entities.PlaceRoadCommand{Start: entities.Point{X: 20, Y: 4}, End: entities.Point{X: 20, Y: 30}, RoadType: entities.Asphalt})
+	sim.Execute(entities.ToggleRoundaboutCommand{At: entities.Point{X: 20, Y: 20}})
+	simRunner.Advance(100)
+	city := cityJSON(t, sim)
+
+	data, err := json.Marshal(sim.Journal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	journal := &entities.Journal{}
+	if err := json.Unmarshal(data, journal); err != nil {
+		t.Fatal(err)
+	}
+	if len(journal.Entries) != 11 || journal.Seed != 42 {
+		t.Fatalf("expected 11 journalled commands with seed 42, got %d with seed %d", len(journal.Entries), journal.Seed)
+	}
+
+	replayRunner := &internal.SimRunner{Seed: journal.Seed, EventLog: io.Discard}
+	replayRunner.NewGame(nil)
+	if err := journal.CheckStart(replayRunner.Sim()); err != nil {
+		t.Fatal(err)
+	}
+	replayRunner.Replay(journal)
+	replayRunner.RunUntil(sim.Date)
+	if !bytes.Equal(city, cityJSON(t, replayRunner.Sim())) {
+		t.Error("replayed city is different to the original")
+	}
+
+	start := scenario.Default()
+	start.Reserves *= 2
+	otherScenario := &internal.SimRunner{Seed: journal.Seed, EventLog: io.Discard, Scenario: start}
+	otherScenario.NewGame(nil)
+	if journal.CheckStart(otherScenario.Sim()) == nil {
+		t.Error("expected the journal not to be replayed on a city from a different scenario")
+	}
+	catalogue := entities.DefaultCatalogue()
+	catalogue.Jobs[0].JobAbundance++
+	otherCatalogue := &internal.SimRunner{Seed: journal.Seed, EventLog: io.Discard, Catalogue: catalogue}
+	otherCatalogue.NewGame(nil)
+	if journal.CheckStart(otherCatalogue.Sim()) == nil {
+		t.Error("expected the journal not to be replayed on a city with a different catalogue")
+	}
+}
+
+// TestScenarioGame checks that a new game starts with the scenario's companies and population
+func TestScenarioGame(t *testing.T) {
+	t.Parallel()
+	start := scenario.Default()
+	start.Companies = []scenario.Companies{{Industry: entities.Retail, Size: entities.SME, Count: 2}, {Industry: entities.Finance, Size: entities.Micro, Count: 1}}
+	start.Roads = []entities.PlaceRoadCommand{{Start: entities.Point{X: 4, Y: 10}, End: entities.Point{X: 40, Y: 10}, RoadType: entities.Asphalt}}
+	start.Zones = []entities.PlaceLandUseCommand{{Start: entities.Point{X: 4, Y: 8}, End: entities.Point{X: 40, Y: 12}, Use: entities.ResidentialUse}}
+	start.Population = 60
+
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard, Scenario: start}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	if len(sim.Companies) != 3 {
+		t.Errorf("expected the scenario's 3 companies, got %d", len(sim.Companies))
+	}
+	if sim.People.Population() < 60 || sim.Houses.GetFreeHouses() == len(sim.Houses) {
+		t.Errorf("expected a housed population of at least 60, got %d in %d houses", sim.People.Population(), len(sim.Houses))
+	}
+	if report := sim.CheckIntegrity(false); !report.OK() {
+		t.Error(report)
+	}
+}
+
 func BenchmarkSimRunner(b *testing.B) {
 	// Set up the simulation
 	simRunner := &internal.SimRunner{}
 	simRunner.NewGame(nil)
-	entities.Sim.SimulationSpeed = entities.Slow
+	sim := simRunner.Sim()
+	sim.SimulationSpeed = entities.Slow
 
 	// add some roads and residential zones
-	simSize := entities.Sim.Geography.Size
+	simSize := sim.Geography.Size
 	for i := 0; i < 16; i++ {
 		x, y := rand.IntN(simSize), rand.IntN(simSize)
-		entities.PlaceRoad(entities.Point{X: x - 1, Y: y}, entities.Point{X: x + 1, Y: y}, entities.Asphalt)
+		entities.PlaceRoad(sim, entities.Point{X: x - 1, Y: y}, entities.Point{X: x + 1, Y: y}, entities.Asphalt)
 		use := entities.ResidentialUse
 		if i >= 12 {
 			use = entities.RetailUse
 		}
-		entities.Sim.Geography.PlaceLandUse(entities.Point{X: x - 2, Y: y - 1}, entities.Point{X: x + 2, Y: y + 1}, use)
+		sim.Geography.PlaceLandUse(entities.Point{X: x - 2, Y: y - 1}, entities.Point{X: x + 2, Y: y + 1}, use)
 	}
 
 	for b.Loop() {
-		entities.Sim.Tick(simRunner.GameTick)
-		entities.Sim.SendStats()
+		sim.Tick(simRunner.GameTick)
+		sim.SendStats()
+	}
+
+	for _, household := range sim.People.Households {
+		fmt.Println(household.GetStats(sim))
+		fmt.Println(household.GetMemberStats(sim))
+	}
+}
+
+// TestMayorGame checks that the mayor builds a city by itself, that it can be switched off, and
+// that replaying the journal of a game it played reproduces the game without it
+func TestMayorGame(t *testing.T) {
+	t.Parallel()
+	simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard, Mayor: &mayor.Planner{}}
+	simRunner.NewGame(nil)
+	sim := simRunner.Sim()
+	if !simRunner.Autopilot() {
+		t.Fatal("expected the mayor to be playing")
+	}
+	simRunner.Advance(3 * 365)
+	if len(sim.Geography.GetRoads()) == 0 || len(sim.Houses) == 0 || sim.People.Population() == 0 {
+		t.Fatalf("expected the mayor to build roads and houses, got %d roads and %d houses", len(sim.Geography.GetRoads()), len(sim.Houses))
 	}
+	city := cityJSON(t, sim)
 
-	for _, household := range entities.Sim.People.Households {
-		fmt.Println(household.GetStats())
-		fmt.Println(household.GetMemberStats())
+	replayRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard}
+	replayRunner.NewGame(nil)
+	replayRunner.Replay(sim.Journal())
+	replayRunner.RunUntil(sim.Date)
+	if !bytes.Equal(city, cityJSON(t, replayRunner.Sim())) {
+		t.Error("replayed city is different to the one the mayor played")
+	}
+
+	simRunner.SetAutopilot(false)
+	commands := len(sim.Journal().Entries)
+	simRunner.Advance(90)
+	if simRunner.Autopilot() || len(sim.Journal().Entries) != commands {
+		t.Error("expected the mayor to stop playing")
+	}
+}
+
+// TestBankDeterminism checks that two mayor games with the same seed keep the same books once the
+// bank has lent to the city, as its loan book moves market sentiment
+func TestBankDeterminism(t *testing.T) {
+	t.Parallel()
+	sims := make([]*entities.Simulation, 2)
+	var wg sync.WaitGroup
+	for i := range sims {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			simRunner := &internal.SimRunner{Seed: 42, EventLog: io.Discard, Mayor: &mayor.Planner{}}
+			simRunner.NewGame(nil)
+			simRunner.Advance(10 * 365)
+			sims[i] = simRunner.Sim()
+		}()
+	}
+	wg.Wait()
+
+	if sims[0].Bank.LoanBook() == 0 || len(sims[0].Bank.Loans) == 0 {
+		t.Fatal("expected the bank to have lent to the city")
+	}
+	if !bytes.Equal(cityJSON(t, sims[0]), cityJSON(t, sims[1])) {
+		t.Error("two games with seed 42 kept different books")
 	}
 }
@@ -17,15 +17,13 @@ type Animation struct {
 	finished                        bool
 }
 
-func (a *Animation) Update() error {
+func (a *Animation) Update(simulationSpeed entities.SimulationSpeed) error {
 	if len(a.path) <= 1 {
 		a.finished = true
 		return nil
 	}
 
-	entities.Sim.Mutex.RLock()
-	simSpeed := float64(entities.Sim.SimulationSpeed)
-	entities.Sim.Mutex.RUnlock()
+	simSpeed := float64(simulationSpeed)
 
 	if simSpeed > 0 { // Pause walking if sim is paused
 		a.frameCounter += int(math.Sqrt(1600 / simSpeed))
@@ -29,7 +29,6 @@ type Animation struct {
 type AssetManager struct {
 	Animations map[string]Animation // Stores animations by name
 	Sprites    map[string]Sprite
-	Names      map[string][]string
 }
 
 // Global instance
@@ -40,9 +39,7 @@ func init() {
 	Assets = &AssetManager{
 		Sprites:    make(map[string]Sprite),
 		Animations: make(map[string]Animation),
-		Names:      make(map[string][]string),
 	}
-	LoadNames()
 }
 
 // LoadSpritesheet loads a multi-line sprite sheet
@@ -103,17 +100,3 @@ func LoadVariableSpritesheet(prefix, imagePath, jsonPath string) {
 		}
 	}
 }
-
-// LoadNames loads the names.json
-func LoadNames() {
-	// Load JSON file
-	data, err := fs.ReadFile(assetsFolder, "names.json")
-	if err != nil {
-		log.Fatal("Failed to load names JSON:", err)
-	}
-
-	// Parse JSON
-	if err := json.Unmarshal(data, &Assets.Names); err != nil {
-		log.Fatal("Failed to parse sprite JSON:", err)
-	}
-}
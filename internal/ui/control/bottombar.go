@@ -1,6 +1,8 @@
 package control
 
 import (
+	"fmt"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -9,16 +11,27 @@ import (
 )
 
 type BottomBar struct {
+	sim                       *entities.Simulation
 	WindowsVisible            bool
 	toggleWindows             func()
+	autopilot                 bool // whether the mayor is playing the city
 	screenHeight, screenWidth int
 	bottomButtons             []*Button
 	bottomText                string
+	stats                     chan string // latest stats from the simulation
+	skipProgress              float64     // progress of skipping ahead, or zero if not skipping ahead
+	skipText                  string
 }
 
 func (b *BottomBar) Draw(screen *ebiten.Image) {
-	vector.DrawFilledRect(screen, buttonWidth, float32(b.screenHeight-buttonHeight), float32(b.screenWidth-buttonWidth*2), buttonHeight, colour.DarkSemiBlack, false)
-	ebitenutil.DebugPrintAt(screen, b.bottomText, buttonWidth+10, b.screenHeight-buttonHeight+4)
+	barWidth := float32(b.screenWidth - buttonWidth*4)
+	vector.DrawFilledRect(screen, buttonWidth*2, float32(b.screenHeight-buttonHeight), barWidth, buttonHeight, colour.DarkSemiBlack, false)
+	if b.skipProgress > 0 {
+		vector.DrawFilledRect(screen, buttonWidth*2, float32(b.screenHeight-buttonHeight), barWidth*float32(b.skipProgress), buttonHeight, colour.DarkGreen, false)
+		ebitenutil.DebugPrintAt(screen, b.skipText, buttonWidth*2+10, b.screenHeight-buttonHeight+4)
+	} else {
+		ebitenutil.DebugPrintAt(screen, b.bottomText, buttonWidth*2+10, b.screenHeight-buttonHeight+4)
+	}
 
 	for i := range b.bottomButtons {
 		b.bottomButtons[i].Draw(screen)
@@ -27,19 +40,30 @@ func (b *BottomBar) Draw(screen *ebiten.Image) {
 
 func (b *BottomBar) Update() error {
 	select { // non-blocking read from stats channel
-	case stats := <-entities.SimStats:
+	case stats := <-b.stats:
 		b.bottomText = stats
 	default:
 	}
 
 	buttonColour := colour.DarkSemiBlack
-	switch entities.Sim.SimulationSpeed {
+	b.sim.Mutex.RLock()
+	simulationSpeed := b.sim.SimulationSpeed
+	b.skipProgress = 0
+	if b.sim.IsSkippingAhead() {
+		progress, until := b.sim.SkipAheadProgress()
+		b.skipProgress = max(progress, 0.01)
+		b.skipText = fmt.Sprintf("Skipping ahead to %s... %.0f%% | %s", until.Format("2006-01-02"), progress*100, b.bottomText)
+	}
+	b.sim.Mutex.RUnlock()
+	switch simulationSpeed {
 	case entities.Slow:
 		b.bottomButtons[0].Label = ">  "
 	case entities.Mid:
 		b.bottomButtons[0].Label = ">> "
 	case entities.Fast:
 		b.bottomButtons[0].Label = ">>>"
+	case entities.Ultra:
+		b.bottomButtons[0].Label = "ULT"
 	default:
 		b.bottomButtons[0].Label = "|| "
 		buttonColour = colour.DarkRed
@@ -52,6 +76,11 @@ func (b *BottomBar) Update() error {
 		b.bottomButtons[1].Label = "[+]"
 	}
 
+	b.bottomButtons[3].Color = colour.DarkSemiBlack
+	if b.autopilot {
+		b.bottomButtons[3].Color = colour.DarkGreen
+	}
+
 	for i := range b.bottomButtons {
 		b.bottomButtons[i].Update()
 	}
@@ -64,16 +93,27 @@ func (b *BottomBar) Layout(width, height int) {
 	b.screenHeight = height
 	b.bottomButtons[0].SetOffset(0, height-buttonHeight)
 	b.bottomButtons[1].SetOffset(width-buttonWidth, height-buttonHeight)
+	b.bottomButtons[2].SetOffset(buttonWidth, height-buttonHeight)
+	b.bottomButtons[3].SetOffset(width-buttonWidth*2, height-buttonHeight)
 }
 
-func NewBottomBar(screenHeight, screenWidth int, toggleWindows func()) *BottomBar {
+func NewBottomBar(screenHeight, screenWidth int, sim *entities.Simulation, toggleWindows func(), toggleAutopilot func() bool) *BottomBar {
 	bar := &BottomBar{
+		sim:            sim,
 		WindowsVisible: false,
 		toggleWindows:  toggleWindows,
 		screenHeight:   screenHeight,
 		screenWidth:    screenWidth,
 		bottomText:     "",
+		stats:          make(chan string, 1),
 	}
+	entities.SubscribeTo(sim.StatsEvents(), func(stats entities.Stats) {
+		select { // replace the bar's copy if it hasn't been read yet, rather than block the simulation
+		case <-bar.stats:
+		default:
+		}
+		bar.stats <- stats.String()
+	})
 	bar.bottomButtons = []*Button{
 		{
 			Label:      ">  ",
@@ -84,9 +124,9 @@ func NewBottomBar(screenHeight, screenWidth int, toggleWindows func()) *BottomBa
 			Color:      colour.DarkSemiBlack,
 			HoverColor: colour.Blue,
 			OnClick: func() {
-				entities.Sim.Mutex.Lock()
-				entities.Sim.ChangeSimulationSpeed()
-				entities.Sim.Mutex.Unlock()
+				sim.Mutex.Lock()
+				sim.ChangeSimulationSpeed()
+				sim.Mutex.Unlock()
 
 			},
 		},
@@ -103,6 +143,36 @@ func NewBottomBar(screenHeight, screenWidth int, toggleWindows func()) *BottomBa
 
 			},
 		},
+		{
+			Label:      "+1Y",
+			X:          buttonWidth,
+			Y:          screenHeight - buttonHeight,
+			Width:      buttonWidth,
+			Height:     buttonHeight,
+			Color:      colour.DarkSemiBlack,
+			HoverColor: colour.DarkGreen,
+			OnClick: func() { // skip ahead a year, or another year if already skipping ahead
+				sim.Mutex.Lock()
+				until := sim.Date
+				if sim.IsSkippingAhead() {
+					_, until = sim.SkipAheadProgress()
+				}
+				sim.SkipAhead(until.AddDate(1, 0, 0))
+				sim.Mutex.Unlock()
+			},
+		},
+		{
+			Label:      "AUT",
+			X:          screenWidth - buttonWidth*2,
+			Y:          screenHeight - buttonHeight,
+			Width:      buttonWidth,
+			Height:     buttonHeight,
+			Color:      colour.DarkSemiBlack,
+			HoverColor: colour.DarkGreen,
+			OnClick: func() { // let the mayor play the city, or take it back
+				bar.autopilot = toggleAutopilot()
+			},
+		},
 	}
 
 	return bar
@@ -6,6 +6,7 @@ import (
 )
 
 type GraphWindow struct {
+	sim          *entities.Simulation
 	dataSource   func() []float64 // Function to dynamically fetch data
 	Window       *Window
 	frameCounter int
@@ -18,9 +19,9 @@ func (gw *GraphWindow) Update() error {
 
 		// Find and update the existing graph
 		if graph, ok := gw.Window.Children[0].(*Graph); ok {
-			entities.Sim.Mutex.RLock()
+			gw.sim.Mutex.RLock()
 			graph.Data = gw.dataSource() // Get fresh data from source
-			entities.Sim.Mutex.RUnlock()
+			gw.sim.Mutex.RUnlock()
 		}
 	}
 	gw.Window.Update()
@@ -32,7 +33,7 @@ func (gw *GraphWindow) Draw(screen *ebiten.Image) {
 }
 
 // NewGraphWindow creates a new graph window instance
-func NewGraphWindow(x, y, width, height int, title string, closeFunc func(string), graphType GraphType, dataSource func() []float64) *GraphWindow {
+func NewGraphWindow(x, y, width, height int, title string, closeFunc func(string), graphType GraphType, sim *entities.Simulation, dataSource func() []float64) *GraphWindow {
 	window := NewWindow(x, y, width, height, title, closeFunc)
 	window.AddChild(&Graph{
 		x:         0,
@@ -43,6 +44,7 @@ func NewGraphWindow(x, y, width, height int, title string, closeFunc func(string
 		Data:      dataSource(),
 	})
 	return &GraphWindow{
+		sim:        sim,
 		Window:     window,
 		dataSource: dataSource,
 	}
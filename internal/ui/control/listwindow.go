@@ -6,6 +6,7 @@ import (
 )
 
 type ListWindow struct {
+	sim          *entities.Simulation
 	dataSource   func() []Statable // Function to dynamically fetch data
 	Window       *Window
 	frameCounter int
@@ -18,9 +19,9 @@ func (lw *ListWindow) Update() error {
 
 		// Find and update the existing text list
 		if list, ok := lw.Window.Children[0].(*TextList); ok {
-			entities.Sim.Mutex.RLock()
+			lw.sim.Mutex.RLock()
 			list.UpdateItems(lw.dataSource()) // Updates text without resetting buttons
-			entities.Sim.Mutex.RUnlock()
+			lw.sim.Mutex.RUnlock()
 		}
 	}
 	lw.Window.Update()
@@ -32,7 +33,7 @@ func (lw *ListWindow) Draw(screen *ebiten.Image) {
 }
 
 // NewListWindow creates a new graph window instance
-func NewListWindow(x, y, width, height int, title string, closeFunc func(string), clickFunc func(string, int), dataSource func() []Statable) *ListWindow {
+func NewListWindow(x, y, width, height int, title string, closeFunc func(string), clickFunc func(string, int), sim *entities.Simulation, dataSource func() []Statable) *ListWindow {
 	window := NewWindow(x, y, width, height, title, closeFunc)
 	textlist := NewTextList(0, 0, width, height-titleBarHeight, dataSource())
 	textlist.OnClick = func(index int) {
@@ -40,6 +41,7 @@ func NewListWindow(x, y, width, height int, title string, closeFunc func(string)
 	}
 	window.AddChild(textlist)
 	return &ListWindow{
+		sim:        sim,
 		Window:     window,
 		dataSource: dataSource,
 	}
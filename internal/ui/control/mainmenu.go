@@ -1,11 +1,16 @@
 package control
 
 import (
+	"fmt"
+	"log"
+	"strings"
+
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/janithl/citylyf/internal/entities"
 	"github.com/janithl/citylyf/internal/gamefile"
+	"github.com/janithl/citylyf/internal/scenario"
 	"github.com/janithl/citylyf/internal/ui/colour"
 )
 
@@ -13,12 +18,19 @@ type MainMenu struct {
 	x, y, width, height, screenWidth, screenHeight, startIndex int
 	entries                                                    []string
 	layoutGrid                                                 *Grid
-	onEntryClick                                               func(*string)
+	onEntryClick                                               func(index int)
+	saving                                                     chan error // receives the result of a save in progress
+	onSaved                                                    func()
+	saveButton                                                 *Button
+	details                                                    *SaveDetails // details of the selected save, in the load menu
 }
 
 func (m *MainMenu) Draw(screen *ebiten.Image) {
 	vector.DrawFilledRect(screen, 0, 0, float32(m.screenWidth), float32(m.screenHeight), colour.DarkSemiBlack, false)
 	m.layoutGrid.Draw(screen)
+	if m.details != nil {
+		m.details.Draw(screen)
+	}
 }
 
 func (m *MainMenu) Update() {
@@ -34,20 +46,70 @@ func (m *MainMenu) Update() {
 		m.updateEntries()
 	}
 
+	if m.saving != nil {
+		select { // non-blocking check for the save to finish
+		case err := <-m.saving:
+			m.saving = nil
+			if err != nil {
+				log.Println(err)
+				m.saveButton.Label = "Save Failed"
+			} else {
+				m.onSaved()
+			}
+		default:
+		}
+	}
+
 	m.layoutGrid.Update()
+	if m.details != nil {
+		m.details.Update()
+	}
+}
+
+// exportStatistics writes the city's statistics to the exports directory, and returns a label saying how it went
+func exportStatistics(sim *entities.Simulation) string {
+	sim.Mutex.RLock()
+	defer sim.Mutex.RUnlock()
+
+	paths, err := gamefile.ExportCityStatistics(sim)
+	if err != nil {
+		log.Println(err)
+		return "Export Failed"
+	}
+	log.Println("exported statistics to", strings.Join(paths, " and "))
+	return "Stats Exported"
+}
+
+// save saves a copy of the simulation in the background, so the menu keeps drawing while it is written
+func (m *MainMenu) save(sim *entities.Simulation) {
+	if m.saving != nil {
+		return
+	}
+	m.saveButton.Label = "Saving..."
+
+	sim.Mutex.RLock()
+	simCopy := sim.Clone()
+	sim.Mutex.RUnlock()
+
+	saving := make(chan error, 1)
+	m.saving = saving
+	go func() { saving <- gamefile.Save(simCopy) }()
 }
 
 func (m *MainMenu) updateEntries() {
-	savedir := gamefile.GetSavesDir()
-	for i, entry := range m.entries[m.startIndex:] {
-		if i >= m.layoutGrid.rows-2 {
-			break
+	for i := 0; i < m.layoutGrid.rows-2; i++ {
+		button, ok := m.layoutGrid.Children[i+2][0].(*Button)
+		if !ok {
+			continue
 		}
 
-		path := savedir + "/" + entry
-		if m.layoutGrid.Children[i+2][0] != nil {
-			m.layoutGrid.Children[i+2][0].(*Button).Label = entry
-			m.layoutGrid.Children[i+2][0].(*Button).OnClick = func() { m.onEntryClick(&path) }
+		index := m.startIndex + i
+		if index < len(m.entries) {
+			button.Label = m.entries[index]
+			button.OnClick = func() { m.onEntryClick(index) }
+		} else { // the list has got shorter, such as after deleting a save
+			button.Label = ""
+			button.OnClick = func() {}
 		}
 	}
 }
@@ -58,9 +120,12 @@ func (m *MainMenu) Layout(width, height int) {
 	m.x = (width - m.width*3) / 2
 	m.y = (height - m.height) / 2
 	m.layoutGrid.SetOffset(m.x, m.y)
+	if m.details != nil {
+		m.details.SetOffset(m.x+m.width*3+16, m.y)
+	}
 }
 
-func NewMainMenu(width, maxEntries int, resumable bool, toggleMenuMode, loadGame, endGame func(), startNewGame func(*string)) *MainMenu {
+func NewMainMenu(width, maxEntries int, sim *entities.Simulation, toggleMenuMode, loadGame, loadScenario, rollBack, endGame func(), startNewGame func(*string)) *MainMenu {
 	menu := &MainMenu{
 		x:          0,
 		y:          0,
@@ -69,42 +134,209 @@ func NewMainMenu(width, maxEntries int, resumable bool, toggleMenuMode, loadGame
 		layoutGrid: NewGrid(0, 0, width, maxEntries*menuEntryHeight, 1, maxEntries),
 	}
 
-	if resumable {
+	if sim != nil {
 		menu.layoutGrid.Children[0][0] = &Button{Label: "Resume Game", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: toggleMenuMode}
 	}
 	menu.layoutGrid.Children[1][0] = &Button{Label: "New Game", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: func() { startNewGame(nil) }}
-	menu.layoutGrid.Children[2][0] = &Button{Label: "Load Game", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: loadGame}
+	menu.layoutGrid.Children[2][0] = &Button{Label: "New Scenario", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: loadScenario}
+	menu.layoutGrid.Children[3][0] = &Button{Label: "Load Game", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: loadGame}
 	exitBtn := &Button{Label: "Exit", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: endGame}
-	if entities.Sim != nil && entities.Sim.CityName != "" {
-		menu.layoutGrid.Children[3][0] = &Button{Label: "Save Game", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: func() { gamefile.Save(); toggleMenuMode() }}
-		menu.layoutGrid.Children[4][0] = exitBtn
-	} else {
-		menu.layoutGrid.Children[3][0] = exitBtn
+	row := 4
+	if sim != nil && sim.CityName != "" {
+		menu.onSaved = toggleMenuMode
+		menu.saveButton = &Button{Label: "Save Game", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: func() { menu.save(sim) }}
+		menu.layoutGrid.Children[row][0] = menu.saveButton
+		row++
+
+		exportButton := &Button{Label: "Export Stats", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red}
+		exportButton.OnClick = func() { exportButton.Label = exportStatistics(sim) }
+		menu.layoutGrid.Children[row][0] = exportButton
+		row++
+	}
+	if sim != nil && len(sim.Snapshots().List()) > 0 {
+		menu.layoutGrid.Children[row][0] = &Button{Label: "Roll Back", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: rollBack}
+		row++
 	}
+	menu.layoutGrid.Children[row][0] = exitBtn
 
 	return menu
 }
 
+// NewLoadGameMenu lists the saves in the saves directory. Selecting one shows its details,
+// from where it can be loaded, duplicated or deleted.
 func NewLoadGameMenu(width, maxEntries int, loadMainMenu func(), startNewGame func(*string)) *MainMenu {
 	menu := &MainMenu{
-		x:            0,
-		y:            0,
-		width:        width,
-		height:       maxEntries * menuEntryHeight,
-		layoutGrid:   NewGrid(0, 0, width, maxEntries*menuEntryHeight, 1, maxEntries),
-		onEntryClick: startNewGame,
+		x:          0,
+		y:          0,
+		width:      width,
+		height:     maxEntries * menuEntryHeight,
+		layoutGrid: NewGrid(0, 0, width, maxEntries*menuEntryHeight, 1, maxEntries),
+	}
+
+	var saves []gamefile.SaveFile
+	order := gamefile.LastSaved
+	sortButton := &Button{Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red}
+	listSaves := func() {
+		saves = []gamefile.SaveFile{}
+		if savesdir := gamefile.GetSavesDir(); savesdir != "" {
+			saves = gamefile.ListSaves(savesdir)
+		}
+		gamefile.SortSaves(saves, order)
+		sortButton.Label = "Sort: " + order.String()
+
+		menu.entries = make([]string, len(saves))
+		for i, save := range saves {
+			menu.entries[i] = saveLabel(save)
+		}
+		menu.startIndex = max(min(menu.startIndex, len(saves)-(maxEntries-2)), 0)
+		menu.details = nil
+		menu.updateEntries()
+	}
+	sortButton.OnClick = func() {
+		order = (order + 1) % 2
+		listSaves()
+	}
+
+	menu.onEntryClick = func(index int) {
+		save := saves[index]
+		menu.details = NewSaveDetails(save,
+			func() { startNewGame(&save.Path) },
+			func() {
+				if _, err := gamefile.DuplicateSave(save.Path); err != nil {
+					log.Println(err)
+				}
+				listSaves()
+			},
+			func() {
+				if err := gamefile.DeleteSave(save.Path); err != nil {
+					log.Println(err)
+				}
+				listSaves()
+			})
+		menu.Layout(menu.screenWidth, menu.screenHeight)
 	}
 
 	menu.layoutGrid.Children[0][0] = &Button{Label: "<- Back", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: loadMainMenu}
+	menu.layoutGrid.Children[1][0] = sortButton
 
 	for i := 2; i < maxEntries; i++ {
 		menu.layoutGrid.Children[i][0] = &Button{Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red}
 	}
 
-	if savesdir := gamefile.GetSavesDir(); savesdir != "" {
-		menu.entries = gamefile.GetDirFiles(savesdir)
-		menu.updateEntries()
+	listSaves()
+	return menu
+}
+
+// NewScenarioMenu lists the scenarios in the scenarios directory, starting a new game from the one selected
+func NewScenarioMenu(width, maxEntries int, loadMainMenu func(), startScenario func(string)) *MainMenu {
+	menu := &MainMenu{
+		x:          0,
+		y:          0,
+		width:      width,
+		height:     maxEntries * menuEntryHeight,
+		layoutGrid: NewGrid(0, 0, width, maxEntries*menuEntryHeight, 1, maxEntries),
+	}
+
+	menu.layoutGrid.Children[0][0] = &Button{Label: "<- Back", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: loadMainMenu}
+
+	for i := 2; i < maxEntries; i++ {
+		menu.layoutGrid.Children[i][0] = &Button{Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red}
 	}
 
+	scenarios := []*scenario.Scenario{}
+	if scenariosdir := scenario.GetScenariosDir(); scenariosdir != "" {
+		scenarios = scenario.List(scenariosdir)
+	}
+	for _, s := range scenarios {
+		menu.entries = append(menu.entries, s.Name)
+	}
+	menu.onEntryClick = func(index int) { startScenario(scenarios[index].Path) }
+	menu.updateEntries()
+
+	return menu
+}
+
+// saveLabel is the text shown for a save in the load menu
+func saveLabel(save gamefile.SaveFile) string {
+	if save.Header == nil {
+		return save.Name
+	}
+	return fmt.Sprintf("%s %s", save.Header.CityName, save.Header.Date.Format("2006-01"))
+}
+
+// NewRollbackMenu lists the snapshots the simulation can be rolled back to, newest first
+func NewRollbackMenu(width, maxEntries int, sim *entities.Simulation, loadMainMenu func(), rollBack func(*entities.Snapshot)) *MainMenu {
+	menu := &MainMenu{
+		x:          0,
+		y:          0,
+		width:      width,
+		height:     maxEntries * menuEntryHeight,
+		layoutGrid: NewGrid(0, 0, width, maxEntries*menuEntryHeight, 1, maxEntries),
+	}
+
+	menu.layoutGrid.Children[0][0] = &Button{Label: "<- Back", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: loadMainMenu}
+
+	for i := 2; i < maxEntries; i++ {
+		menu.layoutGrid.Children[i][0] = &Button{Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red}
+	}
+
+	sim.Mutex.RLock()
+	snapshots := sim.Snapshots().List()
+	sim.Mutex.RUnlock()
+	for _, snapshot := range snapshots {
+		menu.entries = append(menu.entries, snapshot.Date.Format("2006-01-02"))
+	}
+	menu.onEntryClick = func(index int) { rollBack(snapshots[index]) }
+	menu.updateEntries()
+
+	return menu
+}
+
+// NewStatusMenu shows a message while the game is busy, such as when loading
+func NewStatusMenu(width, maxEntries int, message string) *MainMenu {
+	menu := &MainMenu{
+		x:          0,
+		y:          0,
+		width:      width,
+		height:     maxEntries * menuEntryHeight,
+		layoutGrid: NewGrid(0, 0, width, maxEntries*menuEntryHeight, 1, maxEntries),
+	}
+
+	menu.layoutGrid.Children[maxEntries/2][0] = &Button{Label: message, Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Transparent, OnClick: func() {}}
+	return menu
+}
+
+// NewSummaryMenu shows whether the scenario was won or lost and how each goal went, from where the
+// player can keep playing the city or go to the main menu
+func NewSummaryMenu(width int, sim *entities.Simulation, keepPlaying, loadMainMenu func()) *MainMenu {
+	sim.Mutex.RLock()
+	outcome := sim.GoalsOutcome()
+	goals := []string{}
+	for _, goal := range sim.Goals {
+		goals = append(goals, goal.String())
+	}
+	date := sim.Date
+	sim.Mutex.RUnlock()
+
+	maxEntries := len(goals) + 4
+	menu := &MainMenu{
+		x:          0,
+		y:          0,
+		width:      width,
+		height:     maxEntries * menuEntryHeight,
+		layoutGrid: NewGrid(0, 0, width, maxEntries*menuEntryHeight, 1, maxEntries),
+	}
+
+	title := "Scenario Lost"
+	if outcome == entities.GoalPassed {
+		title = "Scenario Won!"
+	}
+	menu.layoutGrid.Children[0][0] = &Button{Label: title, Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Transparent, OnClick: func() {}}
+	menu.layoutGrid.Children[1][0] = &Label{Padding: 4, Text: "On " + date.Format("2006-01-02")}
+	for i, goal := range goals {
+		menu.layoutGrid.Children[i+2][0] = &Label{Padding: 4, Text: goal}
+	}
+	menu.layoutGrid.Children[maxEntries-2][0] = &Button{Label: "Keep Playing", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: keepPlaying}
+	menu.layoutGrid.Children[maxEntries-1][0] = &Button{Label: "Main Menu", Width: width, Height: buttonHeight, Scale: 3, Color: colour.Transparent, HoverColor: colour.Red, OnClick: loadMainMenu}
 	return menu
 }
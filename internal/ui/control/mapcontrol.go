@@ -7,6 +7,7 @@ import (
 )
 
 type MapControl struct {
+	sim                            *entities.Simulation
 	x, y, width, height            int
 	peakPerc, rangePerc, cliffPerc int
 	layoutGrid                     *Grid
@@ -52,21 +53,24 @@ func (mc *MapControl) regenerateMap() {
 	peakProb := 0.00005 * float64(mc.peakPerc)
 	rangeProb := 0.0005 * float64(mc.rangePerc)
 	cliffProb := 0.01 * float64(mc.cliffPerc)
-	entities.Sim.Mutex.Lock()
-	entities.Sim.RegenerateMap(peakProb, rangeProb, cliffProb)
-	entities.Sim.Mutex.Unlock()
+	mc.sim.Mutex.Lock()
+	mc.sim.Execute(entities.RegenerateMapCommand{PeakProbability: peakProb, RangeProbability: rangeProb, CliffProbability: cliffProb})
+	mc.sim.Mutex.Unlock()
 }
 
 func (mc *MapControl) saveCityName() {
+	cityName := "UnnamedCity"
 	if mc.layoutGrid.Children[0][2].(*TextInput).Text != "" {
-		entities.Sim.CityName = mc.layoutGrid.Children[0][2].(*TextInput).Text
-	} else {
-		entities.Sim.CityName = "UnnamedCity"
+		cityName = mc.layoutGrid.Children[0][2].(*TextInput).Text
 	}
+	mc.sim.Mutex.Lock()
+	mc.sim.Execute(entities.NameCityCommand{Name: cityName})
+	mc.sim.Mutex.Unlock()
 }
 
-func NewMapControl(x, y, width, height int, closeFunc func()) *MapControl {
+func NewMapControl(x, y, width, height int, sim *entities.Simulation, closeFunc func()) *MapControl {
 	mc := &MapControl{
+		sim:        sim,
 		x:          x,
 		y:          y,
 		width:      width,
@@ -15,6 +15,7 @@ type MapGrid struct {
 	x, y, size, gridSize, dataMax, frameCounter int
 	data                                        [][]int
 	dataSource                                  func() ([][]int, int) // Function to dynamically fetch data given an x, y coordinate
+	sim                                         *entities.Simulation
 }
 
 func (mg *MapGrid) getColour(x, y int) color.Color {
@@ -47,9 +48,9 @@ func (mg *MapGrid) Update() {
 	mg.frameCounter++
 	if mg.frameCounter >= 60 { // update every second
 		mg.frameCounter = 0
-		entities.Sim.Mutex.RLock()
+		mg.sim.Mutex.RLock()
 		mg.data, mg.dataMax = mg.dataSource()
-		entities.Sim.Mutex.RUnlock()
+		mg.sim.Mutex.RUnlock()
 	}
 }
 
@@ -59,12 +60,13 @@ func (mg *MapGrid) SetOffset(x, y int) {
 }
 
 // NewMapGrid creates a new Map Grid
-func NewMapGrid(x, y, size, gridSize int, dataSource func() ([][]int, int)) *MapGrid {
+func NewMapGrid(x, y, size, gridSize int, sim *entities.Simulation, dataSource func() ([][]int, int)) *MapGrid {
 	return &MapGrid{
 		x:          x,
 		y:          y,
 		size:       size,
 		gridSize:   gridSize,
 		dataSource: dataSource,
+		sim:        sim,
 	}
 }
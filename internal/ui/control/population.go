@@ -13,6 +13,7 @@ import (
 type PopulationPyramid struct {
 	X, Y, Width, Height, frameCounter, maxPopPerGroup int
 	ageGroups                                         map[int]entities.AgeGroup
+	Sim                                               *entities.Simulation
 }
 
 const BarGraphPadding = 4
@@ -42,13 +43,13 @@ func (pp *PopulationPyramid) Update() {
 	if pp.frameCounter >= 60 { // update every second
 		pp.frameCounter = 0
 
-		entities.Sim.Mutex.RLock()
-		pp.ageGroups = entities.Sim.People.AgeGroups
-		pp.maxPopPerGroup = entities.Sim.People.Population()
-		if len(pp.ageGroups) > 0 && entities.Sim.People.Population() > 20 { // bigger populations are easier to predict
-			pp.maxPopPerGroup = 3 * entities.Sim.People.Population() / len(pp.ageGroups)
+		pp.Sim.Mutex.RLock()
+		pp.ageGroups = pp.Sim.People.AgeGroups
+		pp.maxPopPerGroup = pp.Sim.People.Population()
+		if len(pp.ageGroups) > 0 && pp.Sim.People.Population() > 20 { // bigger populations are easier to predict
+			pp.maxPopPerGroup = 3 * pp.Sim.People.Population() / len(pp.ageGroups)
 		}
-		entities.Sim.Mutex.RUnlock()
+		pp.Sim.Mutex.RUnlock()
 	}
 }
 
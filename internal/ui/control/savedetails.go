@@ -0,0 +1,112 @@
+package control
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/janithl/citylyf/internal/gamefile"
+	"github.com/janithl/citylyf/internal/ui/colour"
+	"github.com/janithl/citylyf/internal/utils"
+)
+
+const (
+	saveDetailsWidth  = 320
+	saveDetailsHeight = 440
+	thumbnailScale    = 4
+)
+
+// SaveDetails shows the header of a save file, with buttons to load, duplicate or delete it
+type SaveDetails struct {
+	x, y      int
+	save      gamefile.SaveFile
+	thumbnail *ebiten.Image
+	buttons   []*Button
+}
+
+func (sd *SaveDetails) Draw(screen *ebiten.Image) {
+	vector.DrawFilledRect(screen, float32(sd.x), float32(sd.y), saveDetailsWidth, saveDetailsHeight, colour.DarkSemiBlack, false)
+
+	textY := sd.y + 10
+	if sd.thumbnail != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(thumbnailScale, thumbnailScale)
+		op.GeoM.Translate(float64(sd.x+(saveDetailsWidth-sd.thumbnail.Bounds().Dx()*thumbnailScale)/2), float64(textY))
+		screen.DrawImage(sd.thumbnail, op)
+		textY += sd.thumbnail.Bounds().Dy()*thumbnailScale + 10
+	}
+
+	for _, line := range sd.lines() {
+		ebitenutil.DebugPrintAt(screen, line, sd.x+10, textY)
+		textY += 16
+	}
+
+	for _, button := range sd.buttons {
+		button.Draw(screen)
+	}
+}
+
+func (sd *SaveDetails) lines() []string {
+	header := sd.save.Header
+	if header == nil {
+		return []string{sd.save.Name, "Saved " + sd.save.ModTime.Format("2006-01-02 15:04"), "No details saved with this file"}
+	}
+	return []string{
+		header.CityName,
+		fmt.Sprintf("City date:  %s", header.Date.Format("2006-01-02")),
+		fmt.Sprintf("Population: %d", header.Population),
+		fmt.Sprintf("Reserves:   %s", utils.FormatCurrency(float64(header.Reserves), "$")),
+		fmt.Sprintf("Play time:  %s", header.PlayTime.Round(time.Minute)),
+		fmt.Sprintf("Saved:      %s", header.SavedAt.Format("2006-01-02 15:04")),
+		sd.save.Name,
+	}
+}
+
+func (sd *SaveDetails) Update() {
+	for _, button := range sd.buttons {
+		button.Update()
+	}
+}
+
+func (sd *SaveDetails) SetOffset(x, y int) {
+	sd.x = x
+	sd.y = y
+
+	buttonX := x + 10
+	for _, button := range sd.buttons {
+		button.SetOffset(buttonX, y+saveDetailsHeight-buttonHeight-10)
+		buttonX += button.Width + 10
+	}
+}
+
+// NewSaveDetails shows the details of a save file
+func NewSaveDetails(save gamefile.SaveFile, load, duplicate, delete func()) *SaveDetails {
+	sd := &SaveDetails{save: save}
+	if save.Header != nil && len(save.Header.Thumbnail) > 0 {
+		if img, err := png.Decode(bytes.NewReader(save.Header.Thumbnail)); err == nil {
+			sd.thumbnail = ebiten.NewImageFromImage(img)
+		} else {
+			log.Println(err)
+		}
+	}
+
+	deleteButton := &Button{Label: "Delete", Width: 90, Height: buttonHeight, Color: colour.DarkRed, HoverColor: colour.Red}
+	deleteButton.OnClick = func() { // ask before deleting
+		if deleteButton.Label != "Sure?" {
+			deleteButton.Label = "Sure?"
+			return
+		}
+		delete()
+	}
+	sd.buttons = []*Button{
+		{Label: "Load", Width: 90, Height: buttonHeight, Color: colour.DarkGreen, HoverColor: colour.Green, OnClick: load},
+		{Label: "Duplicate", Width: 90, Height: buttonHeight, Color: colour.DarkGray, HoverColor: colour.Gray, OnClick: duplicate},
+		deleteButton,
+	}
+	return sd
+}
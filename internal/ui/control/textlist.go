@@ -10,6 +10,20 @@ type Statable interface {
 	GetID() int
 }
 
+// ListItem is a Statable whose stats have already been worked out
+type ListItem struct {
+	ID    int
+	Stats string
+}
+
+func (li ListItem) GetStats() string {
+	return li.Stats
+}
+
+func (li ListItem) GetID() int {
+	return li.ID
+}
+
 type TextList struct {
 	X, Y, Width, Height int
 	items               []Statable
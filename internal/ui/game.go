@@ -18,11 +18,18 @@ const (
 )
 
 type Game struct {
-	worldRenderer *world.WorldRenderer
-	windowSystem  *WindowSystem
-	mainMenu      *control.MainMenu
-	mapControl    *control.MapControl
-	startGame     func(*string)
+	sim             *entities.Simulation
+	worldRenderer   *world.WorldRenderer
+	windowSystem    *WindowSystem
+	mainMenu        *control.MainMenu
+	mapControl      *control.MapControl
+	startGame       func(*string) *entities.Simulation
+	startScenario   func(string) *entities.Simulation
+	toggleAutopilot func() bool               // turns the mayor playing the city on or off, returning whether it is on
+	gameStarted     chan *entities.Simulation // receives the simulation of a game being started in the background
+	gamePath        *string
+	scenarioPath    *string
+	scenarioEnded   chan entities.ScenarioEnded // receives the end of the scenario being played
 
 	terminate bool
 }
@@ -32,6 +39,21 @@ func (g *Game) Update() error {
 		return ebiten.Termination
 	}
 
+	if g.gameStarted != nil {
+		select { // non-blocking check for the game to be started
+		case sim := <-g.gameStarted:
+			g.gameStarted = nil
+			g.setUpGame(sim)
+		default:
+		}
+	}
+
+	select { // non-blocking check for the scenario to be won or lost
+	case <-g.scenarioEnded:
+		g.ShowSummaryMenu()
+	default:
+	}
+
 	if g.mainMenu != nil {
 		g.mainMenu.Update()
 		return nil
@@ -85,11 +107,11 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 func (g *Game) EndRegenMode() {
 	g.mapControl = nil
 
-	entities.Sim.Mutex.Lock()
-	entities.Sim.ChangeSimulationSpeed()
-	entities.Sim.Mutex.Unlock()
+	g.sim.Mutex.Lock()
+	g.sim.ChangeSimulationSpeed()
+	g.sim.Mutex.Unlock()
 
-	g.windowSystem = NewWindowSystem()
+	g.windowSystem = NewWindowSystem(g.sim, g.toggleAutopilot)
 }
 
 func (g *Game) EndGame() {
@@ -97,7 +119,31 @@ func (g *Game) EndGame() {
 }
 
 func (g *Game) ShowMainMenu() {
-	g.mainMenu = control.NewMainMenu(192, 5, g.worldRenderer != nil, g.ToggleMenuMode, g.ShowLoadGameMenu, g.EndGame, g.StartNewGame)
+	g.mainMenu = control.NewMainMenu(192, 8, g.sim, g.ToggleMenuMode, g.ShowLoadGameMenu, g.ShowScenarioMenu, g.ShowRollbackMenu, g.EndGame, g.StartNewGame)
+}
+
+func (g *Game) ShowScenarioMenu() {
+	g.mainMenu = control.NewScenarioMenu(192, 8, g.ShowMainMenu, g.StartScenario)
+}
+
+// ShowSummaryMenu pauses the game and shows how the scenario went
+func (g *Game) ShowSummaryMenu() {
+	g.sim.Mutex.Lock()
+	g.sim.PauseSimulation()
+	g.sim.Mutex.Unlock()
+	g.mainMenu = control.NewSummaryMenu(192, g.sim, g.ToggleMenuMode, g.ShowMainMenu)
+}
+
+func (g *Game) ShowRollbackMenu() {
+	g.mainMenu = control.NewRollbackMenu(192, 8, g.sim, g.ShowMainMenu, g.RollBack)
+}
+
+// RollBack restores the simulation to a snapshot and resumes the game
+func (g *Game) RollBack(snapshot *entities.Snapshot) {
+	g.sim.Mutex.Lock()
+	g.sim.Restore(snapshot)
+	g.sim.Mutex.Unlock()
+	g.ToggleMenuMode()
 }
 
 func (g *Game) ShowLoadGameMenu() {
@@ -105,37 +151,79 @@ func (g *Game) ShowLoadGameMenu() {
 }
 
 func (g *Game) ToggleMenuMode() {
-	entities.Sim.Mutex.Lock()
+	g.sim.Mutex.Lock()
 	if g.mainMenu != nil {
 		g.mainMenu = nil
-		entities.Sim.ChangeSimulationSpeed()
+		g.sim.ChangeSimulationSpeed()
 	} else {
 		g.ShowMainMenu()
-		entities.Sim.PauseSimulation()
+		g.sim.PauseSimulation()
 	}
-	entities.Sim.Mutex.Unlock()
+	g.sim.Mutex.Unlock()
 }
 
+// StartNewGame starts a new game, or loads one if gamePath is not nil, in the background
 func (g *Game) StartNewGame(gamePath *string) {
-	g.startGame(gamePath)
+	if g.gameStarted != nil {
+		return
+	}
+
+	message := "Starting..."
+	if gamePath != nil {
+		message = "Loading..."
+	}
+	g.mainMenu = control.NewStatusMenu(192, 5, message)
+	g.gamePath, g.scenarioPath = gamePath, nil
+	started := make(chan *entities.Simulation, 1)
+	g.gameStarted = started
+	go func() { started <- g.startGame(gamePath) }()
+}
+
+// StartScenario starts a new game from the scenario file at scenarioPath in the background
+func (g *Game) StartScenario(scenarioPath string) {
+	if g.gameStarted != nil {
+		return
+	}
+
+	g.mainMenu = control.NewStatusMenu(192, 5, "Starting...")
+	g.gamePath, g.scenarioPath = nil, &scenarioPath
+	started := make(chan *entities.Simulation, 1)
+	g.gameStarted = started
+	go func() { started <- g.startScenario(scenarioPath) }()
+}
+
+func (g *Game) setUpGame(sim *entities.Simulation) {
+	g.sim = sim
 	g.mainMenu = nil
 
-	if gamePath == nil {
-		g.mapControl = control.NewMapControl(0, 0, mcWidth, mcHeight, g.EndRegenMode)
+	switch {
+	case g.scenarioPath != nil: // the scenario has already set up the map and named the city
+		g.EndRegenMode()
+	case g.gamePath == nil:
+		g.mapControl = control.NewMapControl(0, 0, mcWidth, mcHeight, g.sim, g.EndRegenMode)
 		g.mapControl.SetOffset(screenWidth-mcWidth, screenHeight-mcHeight)
-	} else {
-		g.windowSystem = NewWindowSystem()
+	default:
+		g.windowSystem = NewWindowSystem(g.sim, g.toggleAutopilot)
 	}
 
-	g.worldRenderer = world.NewWorldRenderer(screenWidth, screenHeight, g.ToggleMenuMode)
+	g.worldRenderer = world.NewWorldRenderer(screenWidth, screenHeight, g.sim, g.ToggleMenuMode)
+
+	scenarioEnded := make(chan entities.ScenarioEnded, 1)
+	g.scenarioEnded = scenarioEnded
+	entities.SubscribeTo(g.sim.Events(), func(e entities.ScenarioEnded) {
+		select { // published while the simulation is locked, so don't wait for the UI
+		case scenarioEnded <- e:
+		default:
+		}
+	})
 }
 
-func RunGame(startGame func(*string)) {
+func RunGame(startGame func(*string) *entities.Simulation, startScenario func(string) *entities.Simulation, toggleAutopilot func() bool) {
 	ebiten.SetWindowSize(screenWidth, screenHeight)
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("citylyf")
 
-	game := &Game{startGame: startGame}
+	game := &Game{startGame: startGame, startScenario: startScenario, toggleAutopilot: toggleAutopilot}
 	game.ShowMainMenu()
 
 	if err := ebiten.RunGame(game); err != nil {
@@ -10,11 +10,24 @@ import (
 )
 
 type WindowSystem struct {
+	sim            *entities.Simulation
 	windowsVisible bool
 	windows        []control.Window
 	listWindows    []control.ListWindow
 	graphWindows   []control.GraphWindow
 	bottomBar      *control.BottomBar
+	news           []string // latest simulation events, newest first
+}
+
+const maxNewsItems = 50
+
+// addNews is subscribed to the simulation events, which are published while the simulation is locked
+func (ws *WindowSystem) addNews(event entities.Event) {
+	item := fmt.Sprintf("%s %s", ws.sim.Date.Format("2006-01-02"), event)
+	ws.news = append([]string{item}, ws.news...)
+	if len(ws.news) > maxNewsItems {
+		ws.news = ws.news[:maxNewsItems]
+	}
 }
 
 func (ws *WindowSystem) Update() error {
@@ -85,19 +98,23 @@ func (ws *WindowSystem) toggleAllWindows() {
 func (ws *WindowSystem) onWindowItemClick(title string, index int) {
 	switch title {
 	case "Companies":
-		company, exists := entities.Sim.Companies[index]
+		ws.sim.Mutex.RLock()
+		company, exists := ws.sim.Companies[index]
 		if exists {
-			fmt.Println(company.Name, company.CompanyAge(), company.Industry, company.GetNumberOfEmployees(), company.GetNumberOfJobOpenings())
-			for _, emp := range company.GetEmployees() {
-				fmt.Println(emp)
+			fmt.Println(company.Name, company.CompanyAge(ws.sim.Date), company.Industry, company.GetNumberOfEmployees(), company.GetNumberOfJobOpenings())
+			for _, emp := range company.GetEmployees(ws.sim.People) {
+				fmt.Println(emp.GetStats(ws.sim.Date))
 			}
 		}
+		ws.sim.Mutex.RUnlock()
 	case "Households":
-		household, exists := entities.Sim.People.Households[index]
+		ws.sim.Mutex.RLock()
+		household, exists := ws.sim.People.Households[index]
 		if exists {
-			fmt.Println(household.FamilyName(), household.HouseID, household.Size(), household.MoveInDate.Year())
-			fmt.Println(household.GetMemberStats())
+			fmt.Println(household.FamilyName(ws.sim.People), household.HouseID, household.Size(), household.MoveInDate.Year())
+			fmt.Println(household.GetMemberStats(ws.sim))
 		}
+		ws.sim.Mutex.RUnlock()
 	}
 }
 
@@ -105,73 +122,110 @@ func (ws *WindowSystem) Layout(width, height int) {
 	ws.bottomBar.Layout(width, height)
 }
 
-func NewWindowSystem() *WindowSystem {
+func NewWindowSystem(sim *entities.Simulation, toggleAutopilot func() bool) *WindowSystem {
 	ws := &WindowSystem{
+		sim:            sim,
 		windowsVisible: false,
 		windows:        []control.Window{},
 	}
+	sim.Events().Subscribe(ws.addNews)
 
 	ppWin := *control.NewWindow(970, 10, 300, 270, "Population Pyramid", ws.closeWindows)
-	ppWin.AddChild(&control.PopulationPyramid{X: 0, Y: 0, Width: 300, Height: 250})
+	ppWin.AddChild(&control.PopulationPyramid{X: 0, Y: 0, Width: 300, Height: 250, Sim: sim})
 	ws.windows = append(ws.windows, ppWin)
 
 	gridWin := *control.NewWindow(990, 290, 240, 160, "Population Map", ws.closeWindows)
-	gridWin.AddChild(control.NewMapGrid(0, 0, 240, 8, entities.Sim.Geography.Regions.GetPopulationStats))
+	gridWin.AddChild(control.NewMapGrid(0, 0, 240, 8, sim, sim.Geography.Regions.GetPopulationStats))
 	ws.windows = append(ws.windows, gridWin)
 
 	ws.listWindows = []control.ListWindow{
-		*control.NewListWindow(10, 290, 500, 360, "Companies", ws.closeWindows, ws.onWindowItemClick,
+		*control.NewListWindow(10, 290, 500, 360, "Companies", ws.closeWindows, ws.onWindowItemClick, sim,
 			func() []control.Statable {
 				companies := []control.Statable{}
-				for _, companyID := range entities.Sim.Companies.GetIDs() {
-					company, exists := entities.Sim.Companies[companyID]
+				for _, companyID := range sim.Companies.GetIDs() {
+					company, exists := sim.Companies[companyID]
 					if exists {
-						companies = append(companies, company)
+						companies = append(companies, control.ListItem{ID: company.ID, Stats: company.GetStats(sim)})
 					}
 				}
 				return companies
 			}),
-		*control.NewListWindow(520, 290, 460, 360, "Households", ws.closeWindows, ws.onWindowItemClick,
+		*control.NewListWindow(520, 290, 460, 360, "Households", ws.closeWindows, ws.onWindowItemClick, sim,
 			func() []control.Statable {
 				households := []control.Statable{}
-				for _, householdID := range entities.Sim.People.GetHouseholdIDs() {
-					household, exists := entities.Sim.People.Households[householdID]
+				for _, householdID := range sim.People.GetHouseholdIDs() {
+					household, exists := sim.People.Households[householdID]
 					if exists {
-						households = append(households, household)
+						households = append(households, control.ListItem{ID: household.ID, Stats: household.GetStats(sim)})
 					}
 				}
 				return households
 			}),
+		*control.NewListWindow(10, 500, 500, 150, "Bank", ws.closeWindows, ws.onWindowItemClick, sim,
+			func() []control.Statable {
+				bank := sim.Bank
+				items := []control.Statable{
+					control.ListItem{ID: 0, Stats: fmt.Sprintf("Capital: %s   Profit: %s/month   Deposits: %s",
+						utils.FormatCurrency(bank.Capital, "$"), utils.FormatCurrency(bank.LastProfit, "$"), utils.FormatCurrency(utils.GetLastValue(bank.History.Deposits), "$"))},
+					control.ListItem{ID: 0, Stats: fmt.Sprintf("Loan book: %s   Written off in last year: %.2f%%",
+						utils.FormatCurrency(bank.LoanBook(), "$"), bank.DefaultRate())},
+					control.ListItem{ID: 0, Stats: fmt.Sprintf("Mortgages: %.2f%%   Personal loans: %.2f%%   Savings: %.2f%%",
+						bank.Rate(sim, entities.Mortgage), bank.Rate(sim, entities.PersonalLoan), bank.DepositRate(sim))},
+				}
+				for _, loanID := range bank.GetLoanIDs() {
+					items = append(items, control.ListItem{ID: loanID, Stats: bank.Loans[loanID].GetStats(sim)})
+				}
+				return items
+			}),
+		*control.NewListWindow(990, 460, 280, 200, "News", ws.closeWindows, ws.onWindowItemClick, sim,
+			func() []control.Statable {
+				news := []control.Statable{}
+				for i, item := range ws.news {
+					news = append(news, control.ListItem{ID: i, Stats: item})
+				}
+				return news
+			}),
 	}
 
 	ws.graphWindows = []control.GraphWindow{
-		*control.NewGraphWindow(10, 10, 150, 120, "Population", ws.closeWindows, control.Int,
-			func() []float64 { return utils.ConvertToF64(entities.Sim.People.PopulationValues) }),
-		*control.NewGraphWindow(170, 10, 150, 120, "Market Value", ws.closeWindows, control.Float,
-			func() []float64 { return entities.Sim.Market.History.MarketValue }),
-		*control.NewGraphWindow(330, 10, 150, 120, "Inflation Rate", ws.closeWindows, control.Percentage,
-			func() []float64 { return entities.Sim.Market.History.InflationRate }),
-		*control.NewGraphWindow(490, 10, 150, 120, "Gov. Reserves", ws.closeWindows, control.Currency,
-			func() []float64 { return utils.ConvertToF64(entities.Sim.Government.ReserveValues) }),
-		*control.NewGraphWindow(650, 10, 150, 120, "Avg. Annual Wage", ws.closeWindows, control.Currency,
-			func() []float64 { return entities.Sim.People.AverageWageValues }),
-		*control.NewGraphWindow(810, 10, 150, 120, "Avg. Monthly Rent", ws.closeWindows, control.Currency,
-			func() []float64 { return entities.Sim.Market.History.AverageRent }),
-
-		*control.NewGraphWindow(10, 150, 150, 120, "Market Growth Rate", ws.closeWindows, control.Percentage,
-			func() []float64 { return entities.Sim.Market.History.MarketGrowthRate }),
-		*control.NewGraphWindow(170, 150, 150, 120, "Market Sentiment", ws.closeWindows, control.Float,
-			func() []float64 { return entities.Sim.Market.History.MarketSentiment }),
-		*control.NewGraphWindow(330, 150, 150, 120, "Company Profits", ws.closeWindows, control.Currency,
-			func() []float64 { return entities.Sim.Market.History.CompanyProfits }),
-		*control.NewGraphWindow(490, 150, 150, 120, "Gov. Income", ws.closeWindows, control.Currency,
-			func() []float64 { return utils.ConvertToF64(entities.Sim.Government.IncomeValues) }),
-		*control.NewGraphWindow(650, 150, 150, 120, "Unemployment Rate", ws.closeWindows, control.Percentage,
-			func() []float64 { return entities.Sim.People.UnemploymentRateValues }),
-		*control.NewGraphWindow(810, 150, 150, 120, "Interest Rate", ws.closeWindows, control.Percentage,
-			func() []float64 { return entities.Sim.Market.History.InterestRate }),
-	}
-
-	ws.bottomBar = control.NewBottomBar(screenHeight, screenWidth, ws.toggleAllWindows)
+		*control.NewGraphWindow(10, 10, 150, 120, "Population", ws.closeWindows, control.Int, sim,
+			func() []float64 { return utils.ConvertToF64(sim.People.PopulationValues) }),
+		*control.NewGraphWindow(170, 10, 150, 120, "Market Value", ws.closeWindows, control.Float, sim,
+			func() []float64 { return sim.Market.History.MarketValue }),
+		*control.NewGraphWindow(330, 10, 150, 120, "Inflation Rate", ws.closeWindows, control.Percentage, sim,
+			func() []float64 { return sim.Market.History.InflationRate }),
+		*control.NewGraphWindow(490, 10, 150, 120, "Gov. Reserves", ws.closeWindows, control.Currency, sim,
+			func() []float64 { return utils.ConvertToF64(sim.Government.ReserveValues) }),
+		*control.NewGraphWindow(650, 10, 150, 120, "Avg. Annual Wage", ws.closeWindows, control.Currency, sim,
+			func() []float64 { return sim.People.AverageWageValues }),
+		*control.NewGraphWindow(810, 10, 150, 120, "Avg. Monthly Rent", ws.closeWindows, control.Currency, sim,
+			func() []float64 { return sim.Market.History.AverageRent }),
+
+		*control.NewGraphWindow(10, 150, 150, 120, "Market Growth Rate", ws.closeWindows, control.Percentage, sim,
+			func() []float64 { return sim.Market.History.MarketGrowthRate }),
+		*control.NewGraphWindow(170, 150, 150, 120, "Market Sentiment", ws.closeWindows, control.Float, sim,
+			func() []float64 { return sim.Market.History.MarketSentiment }),
+		*control.NewGraphWindow(330, 150, 150, 120, "Company Profits", ws.closeWindows, control.Currency, sim,
+			func() []float64 { return sim.Market.History.CompanyProfits }),
+		*control.NewGraphWindow(490, 150, 150, 120, "Gov. Income", ws.closeWindows, control.Currency, sim,
+			func() []float64 { return utils.ConvertToF64(sim.Government.IncomeValues) }),
+		*control.NewGraphWindow(650, 150, 150, 120, "Unemployment Rate", ws.closeWindows, control.Percentage, sim,
+			func() []float64 { return sim.People.UnemploymentRateValues }),
+		*control.NewGraphWindow(810, 150, 150, 120, "Interest Rate", ws.closeWindows, control.Percentage, sim,
+			func() []float64 { return sim.Market.History.InterestRate }),
+	}
+
+	if len(sim.Goals) > 0 { // the city was started from a scenario with goals
+		ws.listWindows = append(ws.listWindows, *control.NewListWindow(520, 500, 460, 150, "Goals", ws.closeWindows, ws.onWindowItemClick, sim,
+			func() []control.Statable {
+				goals := []control.Statable{}
+				for i, goal := range sim.Goals {
+					goals = append(goals, control.ListItem{ID: i, Stats: goal.String()})
+				}
+				return goals
+			}))
+	}
+
+	ws.bottomBar = control.NewBottomBar(screenHeight, screenWidth, sim, ws.toggleAllWindows, toggleAutopilot)
 	return ws
 }
@@ -73,6 +73,14 @@ func (wr *WorldRenderer) getUserInput() {
 		wr.placingRoad = entities.NoRoad
 		wr.placingUse = entities.AgricultureUse
 		wr.startTile = entities.Point{X: wr.cursorTile.X, Y: wr.cursorTile.Y}
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyO) {
+		wr.placingRoad = entities.NoRoad
+		wr.placingUse = entities.CommercialUse
+		wr.startTile = entities.Point{X: wr.cursorTile.X, Y: wr.cursorTile.Y}
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyI) {
+		wr.placingRoad = entities.NoRoad
+		wr.placingUse = entities.IndustrialUse
+		wr.startTile = entities.Point{X: wr.cursorTile.X, Y: wr.cursorTile.Y}
 	}
 
 	if inpututil.IsKeyJustPressed(ebiten.KeyJ) { // start placing asphalt road
@@ -88,14 +96,14 @@ func (wr *WorldRenderer) getUserInput() {
 	// end placing road/zone
 	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
 		if wr.placingRoad != entities.NoRoad {
-			entities.Sim.Mutex.Lock()
-			entities.PlaceRoad(wr.startTile, wr.cursorTile, wr.placingRoad)
-			entities.Sim.Mutex.Unlock()
+			wr.sim.Mutex.Lock()
+			wr.sim.Execute(entities.PlaceRoadCommand{Start: wr.startTile, End: wr.cursorTile, RoadType: wr.placingRoad})
+			wr.sim.Mutex.Unlock()
 			wr.placingRoad = entities.NoRoad
 		} else if wr.placingUse != entities.NoUse {
-			entities.Sim.Mutex.Lock()
-			entities.Sim.Geography.PlaceLandUse(wr.startTile, wr.cursorTile, wr.placingUse)
-			entities.Sim.Mutex.Unlock()
+			wr.sim.Mutex.Lock()
+			wr.sim.Execute(entities.PlaceLandUseCommand{Start: wr.startTile, End: wr.cursorTile, Use: wr.placingUse})
+			wr.sim.Mutex.Unlock()
 			wr.placingUse = entities.NoUse
 		}
 	}
@@ -112,8 +120,8 @@ func (wr *WorldRenderer) getUserInput() {
 
 	// toggle roundabout
 	if inpututil.IsKeyJustPressed(ebiten.KeyK) {
-		entities.Sim.Mutex.Lock()
-		entities.Sim.Geography.ToggleRoundabout(wr.cursorTile.X, wr.cursorTile.Y)
-		entities.Sim.Mutex.Unlock()
+		wr.sim.Mutex.Lock()
+		wr.sim.Execute(entities.ToggleRoundaboutCommand{At: wr.cursorTile})
+		wr.sim.Mutex.Unlock()
 	}
 }
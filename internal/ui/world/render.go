@@ -1,6 +1,7 @@
 package world
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -11,13 +12,13 @@ import (
 // Renders the base tile
 func (wr *WorldRenderer) renderBaseTiles(screen *ebiten.Image, op *ebiten.DrawImageOptions, tiles [][]entities.Tile, x, y int) {
 	switch tiles[x][y].Elevation {
-	case entities.Sim.Geography.SeaLevel:
+	case wr.sim.Geography.SeaLevel:
 		if sprite, exists := assets.Assets.Sprites[string(tiles[x][y].LandSlope)+"-sand"]; exists {
 			screen.DrawImage(sprite.Image, op)
 		} else {
 			screen.DrawImage(assets.Assets.Sprites["flat-sand"].Image, op)
 		}
-	case entities.Sim.Geography.SeaLevel - 1:
+	case wr.sim.Geography.SeaLevel - 1:
 		screen.DrawImage(assets.Assets.Sprites["shallow-water"].Image, op)
 	case 1:
 		screen.DrawImage(assets.Assets.Sprites["mid-water"].Image, op)
@@ -30,14 +31,14 @@ func (wr *WorldRenderer) renderBaseTiles(screen *ebiten.Image, op *ebiten.DrawIm
 	// draw tile borders
 	borderOp := *op
 	borderOp.ColorScale.Scale(1, 1, 1, 0.4)
-	if tiles[x][y].Elevation < entities.Sim.Geography.SeaLevel {
+	if tiles[x][y].Elevation < wr.sim.Geography.SeaLevel {
 		screen.DrawImage(assets.Assets.Sprites["ui-tile-border"].Image, &borderOp)
 	}
 }
 
 // Renders the mountains
 func (wr *WorldRenderer) renderMountains(screen *ebiten.Image, op *ebiten.DrawImageOptions, tiles [][]entities.Tile, x, y int) {
-	hillLevel := entities.Sim.Geography.HillLevel
+	hillLevel := wr.sim.Geography.HillLevel
 	switch tiles[x][y].Elevation {
 	case hillLevel + 1:
 		mountainOp := *op
@@ -58,9 +59,9 @@ func (wr *WorldRenderer) renderHouses(screen *ebiten.Image, op *ebiten.DrawImage
 		return
 	}
 
-	entities.Sim.Mutex.RLock()
-	house := entities.Sim.Houses.GetLocationHouse(x, y)
-	entities.Sim.Mutex.RUnlock()
+	wr.sim.Mutex.RLock()
+	house := wr.sim.Houses.GetLocationHouse(x, y)
+	wr.sim.Mutex.RUnlock()
 
 	lighting := "dark"
 	if house.HouseholdID != 0 {
@@ -76,22 +77,25 @@ func (wr *WorldRenderer) renderHouses(screen *ebiten.Image, op *ebiten.DrawImage
 	}
 }
 
-// Renders industries/shops
+// Renders industries/shops/offices/factories
 func (wr *WorldRenderer) renderIndusty(screen *ebiten.Image, op *ebiten.DrawImageOptions, tiles [][]entities.Tile, x, y int) {
-	if !(tiles[x][y].LandUse == entities.RetailUse || tiles[x][y].LandUse == entities.AgricultureUse) || tiles[x][y].LandStatus != entities.DevelopedStatus { // not a built shop or farm
+	if !slices.Contains(entities.CompanyUses, tiles[x][y].LandUse) || tiles[x][y].LandStatus != entities.DevelopedStatus { // not a built company site
 		return
 	}
 
-	entities.Sim.Mutex.RLock()
-	company := entities.Sim.Companies.GetLocationCompany(x, y)
-	entities.Sim.Mutex.RUnlock()
+	wr.sim.Mutex.RLock()
+	company := wr.sim.Companies.GetLocationCompany(x, y)
+	wr.sim.Mutex.RUnlock()
 
 	if company == nil {
 		return
 	}
 
-	if retailSprite, exists := assets.Assets.Sprites["industry-"+strings.ToLower(string(company.Industry))+"-"+string(company.RoadDirection)]; exists {
-		screen.DrawImage(retailSprite.Image, op)
+	// shops and farms have their own sprites, other industries share their zone's offices and factories
+	if industrySprite, exists := assets.Assets.Sprites["industry-"+strings.ToLower(string(company.Industry))+"-"+string(company.RoadDirection)]; exists {
+		screen.DrawImage(industrySprite.Image, op)
+	} else if zoneSprite, exists := assets.Assets.Sprites["industry-"+string(tiles[x][y].LandUse)+"-"+string(company.RoadDirection)]; exists {
+		screen.DrawImage(zoneSprite.Image, op)
 	}
 }
 
@@ -101,7 +105,7 @@ func (wr *WorldRenderer) renderRoads(screen *ebiten.Image, op *ebiten.DrawImageO
 		return
 	}
 
-	roadDirection, roadType := entities.Sim.Geography.IsWithinRoad(x, y)
+	roadDirection, roadType := wr.sim.Geography.IsWithinRoad(x, y)
 	roadPrefix := "road-" + string(roadType) + "-"
 
 	// check intersection and draw
@@ -116,7 +120,7 @@ func (wr *WorldRenderer) renderRoads(screen *ebiten.Image, op *ebiten.DrawImageO
 		}
 
 		// draw correct bridge
-		if tiles[x][y].Elevation < entities.Sim.Geography.SeaLevel {
+		if tiles[x][y].Elevation < wr.sim.Geography.SeaLevel {
 			if bridge, exists := assets.Assets.Sprites["bridge-"+string(roadDirection)]; exists {
 				screen.DrawImage(bridge.Image, op)
 			}
@@ -125,7 +129,7 @@ func (wr *WorldRenderer) renderRoads(screen *ebiten.Image, op *ebiten.DrawImageO
 }
 
 func (wr *WorldRenderer) assignAnimations() {
-	for _, region := range entities.Sim.Geography.Regions {
+	for _, region := range wr.sim.Geography.Regions {
 		delay := 0
 		for _, trip := range region.Trips {
 			if trip.Start == nil || trip.End == nil {
@@ -134,7 +138,7 @@ func (wr *WorldRenderer) assignAnimations() {
 
 			for _, anim := range wr.animations {
 				if anim.IsFinished() {
-					anim.SetPath(entities.Sim.Geography.FindPath(trip.Start, trip.End))
+					anim.SetPath(wr.sim.Geography.FindPath(trip.Start, trip.End))
 					anim.CalculateSpeed(delay)
 					delay += 60 // delay next animation by 1 seconds
 					break
@@ -3,6 +3,7 @@ package world
 import (
 	"fmt"
 	"math"
+	"slices"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/janithl/citylyf/internal/entities"
@@ -35,9 +36,9 @@ func (wr *WorldRenderer) screenToGrid(screenX, screenY float64) entities.Point {
 // converts elevation to screen position changes
 func (wr *WorldRenderer) elevationToZ(elevation int) float64 {
 	switch {
-	case elevation < entities.Sim.Geography.SeaLevel:
+	case elevation < wr.sim.Geography.SeaLevel:
 		return 0
-	case elevation >= entities.Sim.Geography.HillLevel:
+	case elevation >= wr.sim.Geography.HillLevel:
 		return -16
 	default:
 		return -8
@@ -63,42 +64,46 @@ func (wr *WorldRenderer) getImageOptions(x, y float64) *ebiten.DrawImageOptions
 
 // getCursorTileData returns current cursor tile data
 func (wr *WorldRenderer) getCursorTileData() string {
-	entities.Sim.Mutex.RLock()
-	tiles := entities.Sim.Geography.GetTiles()
-	entities.Sim.Mutex.RUnlock()
+	wr.sim.Mutex.RLock()
+	tiles := wr.sim.Geography.GetTiles()
+	wr.sim.Mutex.RUnlock()
 
 	if wr.cursorTile.X >= 0 && wr.cursorTile.X < len(tiles) && wr.cursorTile.Y >= 0 && wr.cursorTile.Y < len(tiles) {
 		tile := tiles[wr.cursorTile.X][wr.cursorTile.Y]
 		built := ""
 		if tile.IsBuilt() {
 			output := ""
-			entities.Sim.Mutex.RLock()
+			wr.sim.Mutex.RLock()
 			if tile.LandUse == entities.ResidentialUse {
-				if house := entities.Sim.Houses.GetLocationHouse(wr.cursorTile.X, wr.cursorTile.Y); house != nil {
-					output = fmt.Sprintf("#%d: %d Bedroom House\nRent: $%d/month\n", house.ID, house.Bedrooms, house.MonthlyRent)
-					if household, ok := entities.Sim.People.Households[house.HouseholdID]; ok {
-						output += fmt.Sprintf("%s family (#%d)\n%d members, moved in %s", household.FamilyName(), household.ID,
+				if house := wr.sim.Houses.GetLocationHouse(wr.cursorTile.X, wr.cursorTile.Y); house != nil {
+					output = fmt.Sprintf("#%d: %d Bedroom House\nRent: $%d/month, Price: %s\nOwned by the %s\n", house.ID, house.Bedrooms,
+						house.MonthlyRent, utils.FormatCurrency(float64(house.Price), "$"), house.Owner)
+					if household, ok := wr.sim.People.Households[house.HouseholdID]; ok {
+						output += fmt.Sprintf("%s family (#%d)\n%d members, moved in %s", household.FamilyName(wr.sim.People), household.ID,
 							household.Size(), household.MoveInDate.Format("2006-01-02"))
 					}
 				}
-			} else if tile.LandUse == entities.RetailUse || tile.LandUse == entities.AgricultureUse {
-				if company := entities.Sim.Companies.GetLocationCompany(wr.cursorTile.X, wr.cursorTile.Y); company != nil {
-					output = fmt.Sprintf("#%d: %s\n%d Employees / %d Openings\nProfit/Loss: %s\nRevenue: %s", company.ID, company.Name,
-						company.GetNumberOfEmployees(), company.GetNumberOfJobOpenings(),
+			} else if slices.Contains(entities.CompanyUses, tile.LandUse) {
+				if company := wr.sim.Companies.GetLocationCompany(wr.cursorTile.X, wr.cursorTile.Y); company != nil {
+					output = fmt.Sprintf("#%d: %s\n%s (%s)\n%d Employees / %d Openings\nProfit/Loss: %s\nRevenue: %s\nCash: %s / Debt: %s\nAssets: %s", company.ID, company.Name,
+						company.Industry, company.CompanySize, company.GetNumberOfEmployees(), company.GetNumberOfJobOpenings(),
 						utils.FormatCurrency(company.LastProfit, "$"),
-						utils.FormatCurrency(company.LastRevenue, "$"))
+						utils.FormatCurrency(company.LastRevenue, "$"),
+						utils.FormatCurrency(company.Cash, "$"),
+						utils.FormatCurrency(company.Debt, "$"),
+						utils.FormatCurrency(company.Assets, "$"))
 				}
 			} else if tile.LandUse == entities.TransportUse {
-				for _, road := range entities.Sim.Geography.GetLocationRoads(wr.cursorTile.X, wr.cursorTile.Y) {
+				for _, road := range wr.sim.Geography.GetLocationRoads(wr.cursorTile.X, wr.cursorTile.Y) {
 					output += fmt.Sprintf("%s (%s)\n", road.Name, utils.FormatDistance(float64(road.GetLength())*entities.TileSize))
 				}
 			}
-			entities.Sim.Mutex.RUnlock()
+			wr.sim.Mutex.RUnlock()
 			if output != "" {
 				return output
 			}
 			built = "Built"
-		} else if tile.IsBuildable() {
+		} else if tile.IsBuildable(wr.sim.Geography) {
 			built = "Buildable"
 		}
 		return fmt.Sprintf("Elev: %02d | %s\n%s %s", tile.Elevation, tile.LandSlope, built, tile.LandUse)
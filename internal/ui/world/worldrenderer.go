@@ -26,6 +26,7 @@ const (
 var animatedHumans = []string{"teal", "green", "orange", "pink"}
 
 type WorldRenderer struct {
+	sim                                *entities.Simulation
 	playerX, playerY, offsetX, offsetY float64
 	cameraX, cameraY, zoomFactor       float64
 	width, height, frameCounter        int
@@ -43,13 +44,16 @@ func (wr *WorldRenderer) Update() error {
 		wr.frameCounter = 0
 
 		// assign animations
-		entities.Sim.Mutex.RLock()
+		wr.sim.Mutex.RLock()
 		wr.assignAnimations()
-		entities.Sim.Mutex.RUnlock()
+		wr.sim.Mutex.RUnlock()
 	}
 
+	wr.sim.Mutex.RLock()
+	simSpeed := wr.sim.SimulationSpeed
+	wr.sim.Mutex.RUnlock()
 	for i := range wr.animations {
-		wr.animations[i].Update()
+		wr.animations[i].Update(simSpeed)
 	}
 
 	wr.handleMovement()
@@ -71,7 +75,7 @@ func (wr *WorldRenderer) Update() error {
 }
 
 func (wr *WorldRenderer) Draw(screen *ebiten.Image) {
-	tiles := entities.Sim.Geography.GetTiles()
+	tiles := wr.sim.Geography.GetTiles()
 	for x := range tiles {
 		for y := range tiles[x] {
 			op := wr.getImageOptions(float64(x), float64(y))
@@ -123,7 +127,7 @@ func (wr *WorldRenderer) Draw(screen *ebiten.Image) {
 					(utils.IsWithinRange(wr.cursorTile.X, turningPointX, x) && utils.IsWithinRange(wr.cursorTile.Y, turningPointY, y)))
 
 			if landUseHighlight || roadHighlight {
-				if tiles[x][y].IsBuildable() {
+				if tiles[x][y].IsBuildable(wr.sim.Geography) {
 					screen.DrawImage(assets.Assets.Sprites["ui-highlight"].Image, op)
 				} else {
 					screen.DrawImage(assets.Assets.Sprites["ui-highlight-danger"].Image, op)
@@ -141,7 +145,7 @@ func (wr *WorldRenderer) Layout(width, height int) {
 	wr.offsetY = float64(height / 4)
 }
 
-func NewWorldRenderer(screenWidth, screenHeight int, toggleMenuMode func()) *WorldRenderer {
+func NewWorldRenderer(screenWidth, screenHeight int, sim *entities.Simulation, toggleMenuMode func()) *WorldRenderer {
 	assets.LoadVariableSpritesheet("", "spritesheet-geo.png", "spriteinfo-geo.json")
 	assets.LoadVariableSpritesheet("house", "spritesheet-house.png", "spriteinfo-house.json")
 	assets.LoadVariableSpritesheet("industry", "spritesheet-industry.png", "spriteinfo-industry.json")
@@ -153,8 +157,9 @@ func NewWorldRenderer(screenWidth, screenHeight int, toggleMenuMode func()) *Wor
 		animations[i] = animation.NewAnimation(animatedHumans[rand.IntN(len(animatedHumans))], 0, 0)
 	}
 
-	mapSize := entities.Sim.Geography.Size
+	mapSize := sim.Geography.Size
 	return &WorldRenderer{
+		sim:            sim,
 		playerX:        float64(mapSize / 3),
 		playerY:        float64(mapSize / 3),
 		cameraX:        float64(mapSize / 2),
@@ -42,7 +42,7 @@ func GetElevationSlice(max, min, maxNum int, decayFactor float64) []int {
 
 // GenerateElevationMap generates the elevation values on the map
 // From: https://janithl.github.io/2019/09/go-terrain-gen-part-4/
-func GenerateElevationMap(seaLevel, maxElevation, size int, peakProbability, rangeProbability, cliffProbability float64) [][]int {
+func GenerateElevationMap(rng *rand.Rand, seaLevel, maxElevation, size int, peakProbability, rangeProbability, cliffProbability float64) [][]int {
 	elevationSteps := GetElevationSlice(maxElevation, seaLevel-1, 20, 0.7+peakProbability*50)
 	elevationSteps = append(elevationSteps, GetElevationSlice(seaLevel-1, 0, 10, 0.3+peakProbability*100)...)
 
@@ -52,8 +52,8 @@ func GenerateElevationMap(seaLevel, maxElevation, size int, peakProbability, ran
 	}
 
 	// bias x and y create a vector along which mountain ranges form
-	biasX := rand.IntN(6) - 3
-	biasY := rand.IntN(6) - 3
+	biasX := rng.IntN(6) - 3
+	biasY := rng.IntN(6) - 3
 
 	// iterate down from max elevation, assigning vals
 	for _, e := range elevationSteps {
@@ -62,12 +62,12 @@ func GenerateElevationMap(seaLevel, maxElevation, size int, peakProbability, ran
 				// if the element is next to a element with elevation x, it
 				// should get elevation x - 1
 				// alternately, if the random value meets our criteria, it's a peak
-				if GetAdjacentElevation(elevations, x, y, e, cliffProbability) || rand.Float64() < peakProbability {
+				if GetAdjacentElevation(rng, elevations, x, y, e, cliffProbability) || rng.Float64() < peakProbability {
 					setElevation(elevations, x, y, e)
-					if rand.Float64() > rangeProbability { // randomly add follow-up peaks
+					if rng.Float64() > rangeProbability { // randomly add follow-up peaks
 						setElevation(elevations, x+biasX, y+biasY, e)
 					}
-					if rand.Float64() > rangeProbability {
+					if rng.Float64() > rangeProbability {
 						setElevation(elevations, x-biasX, y-biasY, e)
 					}
 				}
@@ -80,7 +80,7 @@ func GenerateElevationMap(seaLevel, maxElevation, size int, peakProbability, ran
 
 // adjacentElevation checks if an adjacent element
 // to the given element (h, w) is at a given elevation
-func GetAdjacentElevation(elevations [][]int, w, h, elevation int, cliffProbability float64) bool {
+func GetAdjacentElevation(rng *rand.Rand, elevations [][]int, w, h, elevation int, cliffProbability float64) bool {
 	for x := w - 1; x <= w+1; x++ {
 		for y := h - 1; y <= h+1; y++ {
 			if x == w && y == h {
@@ -89,7 +89,7 @@ func GetAdjacentElevation(elevations [][]int, w, h, elevation int, cliffProbabil
 
 			if currentElevation, err := getElevation(elevations, x, y); err == nil && currentElevation == elevation+1 {
 				// if this element is *not* randomly a cliff, return true
-				return rand.Float64() > cliffProbability
+				return rng.Float64() > cliffProbability
 			}
 		}
 	}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"math/rand/v2"
 	"testing"
 )
 
@@ -10,7 +11,7 @@ func TestGenerateElevationMap(t *testing.T) {
 	max := 10
 	sea := 5
 
-	elevationMap := GenerateElevationMap(sea, max, size, 0.0015, 0.005, 0.01)
+	elevationMap := GenerateElevationMap(rand.New(rand.NewPCG(1, 2)), sea, max, size, 0.0015, 0.005, 0.01)
 	if len(elevationMap) != size {
 		t.Errorf("Map size is %d, expected %d", len(elevationMap), size)
 	}
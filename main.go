@@ -1,23 +1,84 @@
 package main
 
 import (
+	"flag"
+	"log"
+
 	"github.com/janithl/citylyf/internal"
+	"github.com/janithl/citylyf/internal/api"
+	"github.com/janithl/citylyf/internal/entities"
+	"github.com/janithl/citylyf/internal/gamefile"
+	"github.com/janithl/citylyf/internal/mayor"
+	"github.com/janithl/citylyf/internal/scenario"
+	"github.com/janithl/citylyf/internal/scheduler"
 	"github.com/janithl/citylyf/internal/ui"
 )
 
-var simRunner *internal.SimRunner
+var (
+	simRunner *internal.SimRunner
+	apiServer *api.Server         // serves the API for the game being run, if enabled
+	catalogue *entities.Catalogue // industries and jobs of new games, the default ones if nil
+)
+
+const autosaveSlots = 3 // number of autosaves kept per city
+
+func startGame(gamePath *string) *entities.Simulation {
+	return runGame(gamePath, nil)
+}
 
-func startGame(gamePath *string) {
+// startScenario starts a new game from a scenario file, or a regular new game if it can't be loaded
+func startScenario(scenarioPath string) *entities.Simulation {
+	start, err := scenario.Load(scenarioPath)
+	if err != nil {
+		log.Println(err)
+	}
+	return runGame(nil, start)
+}
+
+func runGame(gamePath *string, start *scenario.Scenario) *entities.Simulation {
 	if simRunner != nil { // if a game is already running, end it
 		simRunner.EndGame()
 	}
-	simRunner = &internal.SimRunner{}
-	simRunner.NewGame(gamePath)
+	simRunner = &internal.SimRunner{
+		Autosave:   gamefile.NewAutosave(gamefile.GetSavesDir(), autosaveSlots),
+		AutosaveOn: scheduler.Monthly,
+		Scenario:   start,
+		Catalogue:  catalogue,
+		Mayor:      &mayor.Planner{},
+	}
+	if err := simRunner.NewGame(gamePath); err != nil { // if the game could not be loaded, start a new one
+		log.Println(err)
+		simRunner.NewGame(nil)
+	} else if report := simRunner.LoadReport(); report != nil && !report.OK() {
+		log.Println(report)
+	}
+	simRunner.SetAutopilot(false) // the player plays until they hand the city to the mayor
+	if apiServer != nil {
+		apiServer.SetSimulation(simRunner.Sim())
+	}
 	go simRunner.RunGameLoop() // start the game loop in a separate goroutine
+	return simRunner.Sim()
+}
+
+// toggleAutopilot hands the city to the mayor, or takes it back, returning whether the mayor has it
+func toggleAutopilot() bool {
+	on := !simRunner.Autopilot()
+	simRunner.SetAutopilot(on)
+	return on
 }
 
 func main() {
-	ui.RunGame(startGame)
+	apiAddr := flag.String("api", "", "serve the local HTTP API on this address, such as localhost:8080")
+	flag.Parse()
+
+	catalogue = gamefile.FindCatalogue(gamefile.GetCataloguePath())
+
+	if *apiAddr != "" {
+		apiServer = api.NewServer()
+		go func() { log.Fatal(apiServer.ListenAndServe(*apiAddr)) }()
+	}
+
+	ui.RunGame(startGame, startScenario, toggleAutopilot)
 	if simRunner != nil { // if a game is running, end it
 		simRunner.EndGame()
 	}